@@ -0,0 +1,111 @@
+package commands
+
+import (
+	"context"
+	"fmt"
+	"github.com/jfrog/jfrog-cli-core/v2/plugins/components"
+	"github.com/jfrog/live-logs/internal"
+	"github.com/jfrog/live-logs/internal/constants"
+	"strconv"
+	"time"
+)
+
+func GetBundleCommand() components.Command {
+	return components.Command{
+		Name: "bundle",
+		Description: "Download all the log files from all the nodes into a single tar.gz archive, " +
+			"including a manifest with the product details, the file sizes and checksums, and any download failure",
+		Aliases:   []string{"b"},
+		Arguments: getBundleArguments(),
+		Flags:     getBundleFlags(),
+		EnvVars:   getBundleEnvVar(),
+		Action:    bundleCmd,
+	}
+}
+
+func getBundleArguments() []components.Argument {
+	return []components.Argument{
+		{Name: "product-id", Description: "JFrog product id; the value can be one of the following, \n" +
+			"\t\t\t" + constants.ArtifactoryId + " - Artifactory\n" +
+			"\t\t\t" + constants.XrayId + " - Xray\n" +
+			"\t\t\t" + constants.McId + " - Mission Control\n" +
+			"\t\t\t" + constants.DistributionId + " - Distribution\n" +
+			"\t\t\t" + constants.PipelinesId + " - Pipelines"},
+		{Name: "server-id", Description: "JFrog CLI Artifactory server id"},
+	}
+}
+
+func getBundleFlags() []components.Flag {
+	flags := []components.Flag{
+		components.StringFlag{
+			Name:        constants.OutputFlag,
+			Description: "Path of the created archive [Default: live-logs-<product-id>-<server-id>-<timestamp>.tar.gz]",
+		},
+		components.StringFlag{
+			Name:         constants.ConcurrencyFlag,
+			Description:  "Maximum number of log files downloaded in parallel",
+			DefaultValue: strconv.Itoa(livelog.DefaultBundleConcurrency),
+		},
+	}
+	flags = append(flags, getRateLimitFlag())
+	return append(flags, getRedactionFlags()...)
+}
+
+func getBundleEnvVar() []components.EnvVar {
+	return []components.EnvVar{
+		{
+			Name:        constants.VersionCheckEnv,
+			Default:     "true",
+			Description: "Set this to \"false\" to disable validation on the minimum supported version of the product.",
+		},
+		getRedactionEnvVar(),
+	}
+}
+
+func bundleCmd(c *components.Context) error {
+	if len(c.Arguments) != 2 {
+		return fmt.Errorf("incorrect number of arguments were passed: expected: 2," + " received: " + strconv.Itoa(len(c.Arguments)))
+	}
+	productId := c.Arguments[0]
+	serverId := c.Arguments[1]
+
+	concurrency := livelog.DefaultBundleConcurrency
+	if concurrencyValue := c.GetStringFlagValue(constants.ConcurrencyFlag); concurrencyValue != "" {
+		var err error
+		concurrency, err = strconv.Atoi(concurrencyValue)
+		if err != nil {
+			return fmt.Errorf("invalid %s value [%s], expected a number", constants.ConcurrencyFlag, concurrencyValue)
+		}
+	}
+
+	outputPath := c.GetStringFlagValue(constants.OutputFlag)
+	if outputPath == "" {
+		outputPath = fmt.Sprintf("live-logs-%s-%s-%s.tar.gz", productId, serverId, time.Now().Format("20060102-150405"))
+	}
+
+	mainCtx, mainCtxCancel := context.WithCancel(context.Background())
+	defer mainCtxCancel()
+
+	var liveLogClient livelog.LiveLogs
+	liveLogClient = livelog.NewLiveLogs()
+
+	redactor, err := getRedactor(c)
+	if err != nil {
+		return err
+	}
+	liveLogClient.SetRedactor(redactor)
+
+	err = setRateLimit(c)
+	if err != nil {
+		return err
+	}
+
+	ListenForTermination(mainCtxCancel)
+
+	err = liveLogClient.ExportBundle(mainCtx, productId, serverId, outputPath, concurrency)
+	if err != nil {
+		return err
+	}
+	fmt.Println("Bundle created at " + outputPath)
+	return nil
+}
@@ -0,0 +1,53 @@
+package commands
+
+import (
+	"github.com/jfrog/jfrog-cli-core/v2/plugins/components"
+	"github.com/jfrog/live-logs/internal/color"
+	"github.com/jfrog/live-logs/internal/constants"
+	"os"
+	"strings"
+)
+
+func getColorFlags() []components.Flag {
+	return []components.Flag{
+		components.StringFlag{
+			Name: constants.ColorFlag,
+			Description: "Color the output by log level, request status and source, one of " + color.AlwaysMode + ", " +
+				color.NeverMode + " or " + color.AutoMode + "; in " + color.AutoMode + " mode the output is colored when it is a terminal " +
+				"and " + color.NoColorEnv + " is empty or not set",
+			DefaultValue: color.AutoMode,
+		},
+		components.StringFlag{
+			Name:        constants.HighlightFlag,
+			Description: "Semicolon separated list of regular expressions to highlight in the colored output",
+		},
+	}
+}
+
+func getColorEnvVar() components.EnvVar {
+	return components.EnvVar{
+		Name:        color.NoColorEnv,
+		Description: "Set this to any non empty value to disable the colors, unless --" + constants.ColorFlag + "=" + color.AlwaysMode + " is passed.",
+	}
+}
+
+// Creates the colorizer matching the command flags, nil is returned when the output is not colored.
+func getColorizer(c *components.Context) (*color.Colorizer, error) {
+	enabled, err := color.Enabled(c.GetStringFlagValue(constants.ColorFlag), os.Stdout)
+	if err != nil || !enabled {
+		return nil, err
+	}
+	return color.NewColorizer(parseHighlights(c.GetStringFlagValue(constants.HighlightFlag)))
+}
+
+// Splits the highlighted regular expressions on semicolons, as commas are part of the regular expressions syntax,
+// such as in a{1,3}.
+func parseHighlights(highlightValue string) []string {
+	var highlights []string
+	for _, highlight := range strings.Split(highlightValue, constants.HighlightSeparator) {
+		if highlight != "" {
+			highlights = append(highlights, highlight)
+		}
+	}
+	return highlights
+}
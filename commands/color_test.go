@@ -0,0 +1,25 @@
+package commands
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestParseHighlights(t *testing.T) {
+	tests := []struct {
+		name           string
+		highlightValue string
+		want           []string
+	}{
+		{name: "empty", highlightValue: ""},
+		{name: "single", highlightValue: "ERROR", want: []string{"ERROR"}},
+		{name: "several", highlightValue: "ERROR;repo-[0-9]+", want: []string{"ERROR", "repo-[0-9]+"}},
+		{name: "commas are kept", highlightValue: "a{1,3};b,c", want: []string{"a{1,3}", "b,c"}},
+		{name: "empty expressions are skipped", highlightValue: "ERROR;;WARN;", want: []string{"ERROR", "WARN"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, parseHighlights(tt.highlightValue))
+		})
+	}
+}
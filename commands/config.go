@@ -6,7 +6,9 @@ import (
 	"github.com/jfrog/jfrog-cli-core/v2/plugins/components"
 	"github.com/jfrog/live-logs/internal"
 	"github.com/jfrog/live-logs/internal/constants"
+	"github.com/jfrog/live-logs/internal/util"
 	"strconv"
+	"strings"
 )
 
 func GetConfigCommand() components.Command {
@@ -29,28 +31,36 @@ func getConfigArguments() []components.Argument {
 			"\t\t\t" + constants.McId + " - Mission Control\n" +
 			"\t\t\t" + constants.DistributionId + " - Distribution\n" +
 			"\t\t\t" + constants.PipelinesId + " - Pipelines"},
-		{Name: "server-id", Description: "JFrog CLI Artifactory server id"},
+		{Name: "server-id", Description: "JFrog CLI Artifactory server id, a comma separated list of server ids or patterns such as \"prod-*\", or \"" + constants.AllValues + "\" for all the server ids; " +
+			"omitted when the platform url is passed with --" + constants.UrlFlag},
 	}
 }
 
 func getConfigFlags() []components.Flag {
-	return []components.Flag{
+	flags := []components.Flag{
 		components.BoolFlag{
 			Name:         constants.InteractiveFlag,
 			Description:  "Activate the interactive menu",
 			DefaultValue: false,
 		},
+		components.StringFlag{
+			Name:         constants.FormatFlag,
+			Description:  "Output format, one of: " + strings.Join(livelog.ConfigFormats(), ", "),
+			DefaultValue: constants.JsonFormat,
+		},
 	}
+	return append(flags, getPlatformFlags()...)
 }
 
 func getConfigEnvVar() []components.EnvVar {
-	return []components.EnvVar{
+	envVars := []components.EnvVar{
 		{
 			Name:        constants.VersionCheckEnv,
 			Default:     "true",
 			Description: "Set this to \"false\" to disable validation on minimum supported version of the product.",
 		},
 	}
+	return append(envVars, getPlatformEnvVars()...)
 }
 
 func configCmd(c *components.Context) error {
@@ -62,12 +72,28 @@ func configCmd(c *components.Context) error {
 	var liveLogClient livelog.LiveLogs
 	liveLogClient = livelog.NewLiveLogs()
 
+	format := c.GetStringFlagValue(constants.FormatFlag)
+	if format != "" {
+		err := util.ValidateArgument("format", format, livelog.ConfigFormats())
+		if err != nil {
+			return err
+		}
+		liveLogClient.SetConfigFormat(format)
+	}
+
+	platformServerId, err := getPlatformServerId(c)
+	if err != nil {
+		return err
+	}
+	liveLogClient.SetServiceId(platformServerId)
+
 	if !isInteractive {
-		if len(c.Arguments) != 2 {
-			return fmt.Errorf("incorrect number of arguments were passed: expected: 2," + " received: " + strconv.Itoa(len(c.Arguments)))
+		arguments := withServerId(c.Arguments, platformServerId)
+		if len(arguments) != 2 {
+			return fmt.Errorf("incorrect number of arguments were passed: expected: 2," + " received: " + strconv.Itoa(len(arguments)))
 		}
-		productId := c.Arguments[0]
-		serverId := c.Arguments[1]
+		productId := arguments[0]
+		serverId := arguments[1]
 		return liveLogClient.ConfigNonInteractive(mainCtx, productId, serverId)
 	}
 	return ConfigInteractive(mainCtx, liveLogClient)
@@ -0,0 +1,60 @@
+package commands
+
+import (
+	"fmt"
+	"github.com/jfrog/jfrog-cli-core/v2/plugins/components"
+	"github.com/jfrog/live-logs/internal/constants"
+	"github.com/jfrog/live-logs/internal/dedup"
+	"time"
+)
+
+func getDedupFlags() []components.Flag {
+	return []components.Flag{
+		components.BoolFlag{
+			Name: constants.DedupFlag,
+			Description: "Collapse the consecutive repeats of a line into the line followed by its last repeat with the number of repeats, " +
+				"the timestamps, UUIDs and hexadecimal ids such as the trace ids are ignored when comparing the lines",
+			DefaultValue: false,
+		},
+		components.StringFlag{
+			Name: constants.DedupWindowFlag,
+			Description: "Also collapse the repeats which are not consecutive, within a time of the first occurrence of the line, " +
+				"such as 10s or 1m; implies --" + constants.DedupFlag,
+		},
+		components.StringFlag{
+			Name: constants.DedupRulesFlag,
+			Description: "Path to a JSON file with additional normalization rules, a list of {\"name\", \"pattern\"} objects " +
+				"whose matches are ignored when comparing the lines; implies --" + constants.DedupFlag,
+		},
+	}
+}
+
+// Creates the deduplication config matching the command flags, nil is returned when the repeated lines are not
+// collapsed.
+func getDeduplication(c *components.Context) (*dedup.Config, error) {
+	windowValue := c.GetStringFlagValue(constants.DedupWindowFlag)
+	rulesFilePath := c.GetStringFlagValue(constants.DedupRulesFlag)
+	if !c.GetBoolFlagValue(constants.DedupFlag) && windowValue == "" && rulesFilePath == "" {
+		return nil, nil
+	}
+	return parseDeduplication(windowValue, rulesFilePath)
+}
+
+func parseDeduplication(windowValue, rulesFilePath string) (*dedup.Config, error) {
+	deduplication := &dedup.Config{Rules: dedup.BuiltInRules()}
+	if windowValue != "" {
+		window, err := time.ParseDuration(windowValue)
+		if err != nil || window < 0 {
+			return nil, fmt.Errorf("invalid %s value [%s], expected a duration such as 10s or 1m", constants.DedupWindowFlag, windowValue)
+		}
+		deduplication.Window = window
+	}
+	if rulesFilePath != "" {
+		userRules, err := dedup.LoadRules(rulesFilePath)
+		if err != nil {
+			return nil, err
+		}
+		deduplication.Rules = append(deduplication.Rules, userRules...)
+	}
+	return deduplication, nil
+}
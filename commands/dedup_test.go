@@ -0,0 +1,45 @@
+package commands
+
+import (
+	"github.com/jfrog/live-logs/internal/dedup"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestParseDeduplication(t *testing.T) {
+	rulesFilePath := filepath.Join(t.TempDir(), "rules.json")
+	require.NoError(t, ioutil.WriteFile(rulesFilePath, []byte(`[{"name": "number", "pattern": "[0-9]+"}]`), 0600))
+	tests := []struct {
+		name             string
+		window           string
+		rulesFilePath    string
+		wantWindow       time.Duration
+		wantUserRules    int
+		wantErrMsgPrefix string
+	}{
+		{name: "consecutive repeats"},
+		{name: "window", window: "10s", wantWindow: 10 * time.Second},
+		{name: "rules", rulesFilePath: rulesFilePath, wantUserRules: 1},
+		{name: "invalid window", window: "often", wantErrMsgPrefix: "invalid dedup-window value [often]"},
+		{name: "negative window", window: "-1s", wantErrMsgPrefix: "invalid dedup-window value [-1s]"},
+		{name: "missing rules file", rulesFilePath: filepath.Join(t.TempDir(), "missing.json"), wantErrMsgPrefix: "open "},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			deduplication, err := parseDeduplication(tt.window, tt.rulesFilePath)
+			if tt.wantErrMsgPrefix != "" {
+				require.Error(t, err)
+				assert.True(t, strings.HasPrefix(err.Error(), tt.wantErrMsgPrefix), err.Error())
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantWindow, deduplication.Window)
+			assert.Len(t, deduplication.Rules, len(dedup.BuiltInRules())+tt.wantUserRules)
+		})
+	}
+}
@@ -0,0 +1,85 @@
+package commands
+
+import (
+	"context"
+	"fmt"
+	"github.com/jfrog/jfrog-cli-core/v2/plugins/components"
+	"github.com/jfrog/live-logs/internal"
+	"github.com/jfrog/live-logs/internal/constants"
+	"github.com/jfrog/live-logs/internal/util"
+	"strconv"
+)
+
+func GetDoctorCommand() components.Command {
+	return components.Command{
+		Name: "doctor",
+		Description: "Diagnose why the logs of a product cannot be read; checks the CLI configuration, the product url, " +
+			"DNS and TLS, the authentication type, the product version and the logs endpoints, and suggests how to fix any failure",
+		Aliases:   []string{"d"},
+		Arguments: getDoctorArguments(),
+		Flags:     getDoctorFlags(),
+		EnvVars:   getDoctorEnvVar(),
+		Action:    doctorCmd,
+	}
+}
+
+// The doctor diagnoses the configuration of a single server id, it takes neither the server id lists nor the platform url.
+func getDoctorArguments() []components.Argument {
+	return []components.Argument{
+		{Name: "product-id", Description: "JFrog product id; the value can be one of the following, \n" +
+			"\t\t\t" + constants.ArtifactoryId + " - Artifactory\n" +
+			"\t\t\t" + constants.XrayId + " - Xray\n" +
+			"\t\t\t" + constants.McId + " - Mission Control\n" +
+			"\t\t\t" + constants.DistributionId + " - Distribution\n" +
+			"\t\t\t" + constants.PipelinesId + " - Pipelines"},
+		{Name: "server-id", Description: "JFrog CLI Artifactory server id"},
+	}
+}
+
+func getDoctorFlags() []components.Flag {
+	return []components.Flag{
+		components.BoolFlag{
+			Name:         constants.InteractiveFlag,
+			Description:  "Activate the interactive menu",
+			DefaultValue: false,
+		},
+	}
+}
+
+func getDoctorEnvVar() []components.EnvVar {
+	return []components.EnvVar{
+		{
+			Name:        constants.VersionCheckEnv,
+			Default:     "true",
+			Description: "Set this to \"false\" to disable validation on the minimum supported version of the product.",
+		},
+	}
+}
+
+func doctorCmd(c *components.Context) error {
+	isInteractive := c.GetBoolFlagValue(constants.InteractiveFlag)
+
+	mainCtx, mainCtxCancel := context.WithCancel(context.Background())
+	defer mainCtxCancel()
+
+	var liveLogClient livelog.LiveLogs
+	liveLogClient = livelog.NewLiveLogs()
+
+	if !isInteractive {
+		if len(c.Arguments) != 2 {
+			return fmt.Errorf("incorrect number of arguments were passed: expected: 2," + " received: " + strconv.Itoa(len(c.Arguments)))
+		}
+		return liveLogClient.Doctor(mainCtx, c.Arguments[0], c.Arguments[1])
+	}
+	selectedCliServerId, err := selectCliServerId()
+	if err != nil {
+		return err
+	}
+	// Every product is offered, as the unavailable ones are the ones to diagnose.
+	selectedProductId, err := PromptSelectMenu("Select JFrog CLI product id", "Available product IDs", util.FetchAllProductIds())
+	if err != nil {
+		return err
+	}
+	PromptForAnyKey(constants.NonIntCmdDisplayPrefix + " \n\t jfrog live-logs doctor " + selectedProductId + " " + selectedCliServerId)
+	return liveLogClient.Doctor(mainCtx, selectedProductId, selectedCliServerId)
+}
@@ -0,0 +1,58 @@
+package commands
+
+import (
+	"github.com/jfrog/jfrog-cli-core/v2/plugins/components"
+	"github.com/jfrog/live-logs/internal/constants"
+	"github.com/stretchr/testify/assert"
+	"strings"
+	"testing"
+)
+
+func TestDoctorCmdArguments(t *testing.T) {
+	tests := []struct {
+		name             string
+		ctx              *components.Context
+		wantErrMsgPrefix string
+	}{
+		{
+			name: "zero argument  (without interactive menu)",
+			ctx: &components.Context{
+				Arguments: []string{},
+			},
+			wantErrMsgPrefix: "incorrect number of arguments",
+		},
+		{
+			name: "two argument",
+			ctx: &components.Context{
+				Arguments: []string{"a", "b"},
+			},
+			wantErrMsgPrefix: "check failed",
+		},
+		{
+			name: "three argument",
+			ctx: &components.Context{
+				Arguments: []string{"a", "b", "c"},
+			},
+			wantErrMsgPrefix: "incorrect number of arguments",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := doctorCmd(tt.ctx)
+			assert.NotNil(t, err)
+			assert.True(t, strings.Contains(err.Error(), tt.wantErrMsgPrefix))
+		})
+	}
+}
+
+func TestGetDoctorCommand(t *testing.T) {
+	command := GetDoctorCommand()
+	assert.Len(t, command.Arguments, 2)
+	assert.NotContains(t, command.Arguments[1].Description, constants.AllValues)
+	for _, envVar := range command.EnvVars {
+		assert.Equal(t, constants.VersionCheckEnv, envVar.Name)
+	}
+	for _, flag := range command.Flags {
+		assert.NotEqual(t, constants.UrlFlag, flag.GetName())
+	}
+}
@@ -0,0 +1,25 @@
+package commands
+
+import (
+	"github.com/jfrog/jfrog-cli-core/v2/plugins/components"
+	"github.com/jfrog/live-logs/internal/constants"
+	"github.com/jfrog/live-logs/internal/format"
+	"strings"
+)
+
+func getFormatFlag() components.Flag {
+	return components.StringFlag{
+		Name: constants.FormatFlag,
+		Description: "Format of each log line, one of " + strings.Join(format.NamedFormats(), ", ") + ", or a Go text/template " +
+			"template applied to the parsed line, such as '{{.Time}} {{.Node}} {{.Level}} {{.Message}}'",
+	}
+}
+
+// Creates the line formatter matching the command flags, nil is returned when the lines are written as they are.
+func getLineFormatter(c *components.Context) (*format.Formatter, error) {
+	lineFormat := c.GetStringFlagValue(constants.FormatFlag)
+	if lineFormat == "" {
+		return nil, nil
+	}
+	return format.NewFormatter(lineFormat)
+}
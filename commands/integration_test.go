@@ -0,0 +1,115 @@
+package commands
+
+import (
+	"encoding/json"
+	"github.com/jfrog/jfrog-cli-core/v2/plugins/components"
+	"github.com/jfrog/live-logs/internal/constants"
+	"github.com/jfrog/live-logs/internal/model"
+	"github.com/jfrog/live-logs/internal/testserver"
+	"github.com/stretchr/testify/require"
+	"io/ioutil"
+	"os"
+	"testing"
+)
+
+// Starts a fake platform, passed to the commands with the platform environment variables.
+func newTestPlatform(t *testing.T) *testserver.Server {
+	server := testserver.New(t, "some-token")
+	server.AddProduct(constants.ArtifactoryId, testserver.Product{
+		Version:           "7.41.0",
+		Nodes:             []string{"node-1", "node-2"},
+		LogFileNames:      []string{"one.log", "two.log"},
+		RefreshRateMillis: 1000,
+	})
+	setTestEnv(t, constants.UrlEnv, server.URL)
+	setTestEnv(t, constants.AccessTokenEnv, "some-token")
+	return server
+}
+
+func setTestEnv(t *testing.T, name, value string) {
+	realValue, isSet := os.LookupEnv(name)
+	t.Cleanup(func() {
+		if isSet {
+			_ = os.Setenv(name, realValue)
+			return
+		}
+		_ = os.Unsetenv(name)
+	})
+	require.NoError(t, os.Setenv(name, value))
+}
+
+// Runs the command action and returns what it wrote to the standard output.
+func captureStdout(t *testing.T, action func() error) (string, error) {
+	realStdout := os.Stdout
+	reader, writer, err := os.Pipe()
+	require.NoError(t, err)
+	os.Stdout = writer
+	defer func() { os.Stdout = realStdout }()
+
+	actionErr := action()
+	require.NoError(t, writer.Close())
+	output, err := ioutil.ReadAll(reader)
+	require.NoError(t, err)
+	return string(output), actionErr
+}
+
+func TestLogsCmd_FakeServer(t *testing.T) {
+	server := newTestPlatform(t)
+	server.AppendLog(constants.ArtifactoryId, "node-1", "one.log", "first line\nsecond line\n")
+	server.AppendLog(constants.ArtifactoryId, "node-2", "one.log", "other node\n")
+
+	output, err := captureStdout(t, func() error {
+		return logsCmd(&components.Context{Arguments: []string{constants.ArtifactoryId, "node-1", "one.log"}})
+	})
+	require.NoError(t, err)
+	require.Equal(t, "first line\nsecond line\n", output)
+
+	output, err = captureStdout(t, func() error {
+		return logsCmd(&components.Context{Arguments: []string{constants.ArtifactoryId, "all", "one.log"}})
+	})
+	require.NoError(t, err)
+	require.Equal(t, "[node-1] first line\n[node-1] second line\n[node-2] other node\n", output)
+}
+
+func TestLogsCmd_FakeServer_Failures(t *testing.T) {
+	server := newTestPlatform(t)
+	server.FailRequests(constants.ArtifactoryId, 429, 1)
+	_, err := captureStdout(t, func() error {
+		return logsCmd(&components.Context{Arguments: []string{constants.ArtifactoryId, "node-1", "one.log"}})
+	})
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "status code: 429")
+
+	server.SetAccessToken("other-token")
+	_, err = captureStdout(t, func() error {
+		return logsCmd(&components.Context{Arguments: []string{constants.ArtifactoryId, "node-1", "one.log"}})
+	})
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "status code: 401")
+
+	server.SetAccessToken("some-token")
+	_, err = captureStdout(t, func() error {
+		return logsCmd(&components.Context{Arguments: []string{constants.ArtifactoryId, "node-1", "missing.log"}})
+	})
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "log name")
+}
+
+func TestConfigCmd_FakeServer(t *testing.T) {
+	server := newTestPlatform(t)
+	output, err := captureStdout(t, func() error {
+		return configCmd(&components.Context{Arguments: []string{constants.ArtifactoryId}})
+	})
+	require.NoError(t, err)
+
+	var displayData model.ConfigDisplayData
+	require.NoError(t, json.Unmarshal([]byte(output), &displayData))
+	require.Equal(t, model.ConfigDisplayData{
+		ServerId:          server.URL + "/",
+		ProductId:         constants.ArtifactoryId,
+		ProductVersion:    "7.41.0",
+		RefreshRateMillis: 1000,
+		Logs:              []string{"one.log", "two.log"},
+		Nodes:             []string{"node-1", "node-2"},
+	}, displayData)
+}
@@ -6,7 +6,9 @@ import (
 	"github.com/jfrog/jfrog-cli-core/v2/plugins/components"
 	"github.com/jfrog/live-logs/internal"
 	"github.com/jfrog/live-logs/internal/constants"
+	"github.com/jfrog/live-logs/internal/recording"
 	"strconv"
+	"time"
 )
 
 func GetLogsCommand() components.Command {
@@ -16,8 +18,8 @@ func GetLogsCommand() components.Command {
 		"\n\nNote:" +
 		"\n\t- Xray, Mission Control, Pipelines, and Distribution only support admin access token authentication, while, Artifactory supports all types of authentication." +
 		"\n\t- The scope of the generated access token is limited to the corresponding product." +
-		"\n\t- For every product, a new dedicated entry will need to be added. " +
-		"For example, if you want to stream logs from 3 products, a separate entry will need to be configured for each product in the JFrog CLI (so is 3 entries).",
+		"\n\t- A single JFrog CLI entry with the platform url, or the platform url passed with --" + constants.UrlFlag + ", covers all the products, " +
+		"the url of a product which is not configured is derived from the platform url.",
 		Aliases:     []string{"l"},
 		Arguments:   getLogsArguments(),
 		EnvVars:     getLogsEnvVar(),
@@ -34,14 +36,15 @@ func getLogsArguments() []components.Argument {
 			"\t\t\t" + constants.McId + " - Mission Control\n" +
 			"\t\t\t" + constants.DistributionId + " - Distribution\n" +
 			"\t\t\t" + constants.PipelinesId + " - Pipelines"},
-		{Name: "server-id", Description: "JFrog CLI Artifactory server id"},
-		{Name: "node-id", Description: "Selected node id"},
-		{Name: "log-name", Description: "Selected log name"},
+		{Name: "server-id", Description: "JFrog CLI Artifactory server id, a comma separated list of server ids or patterns such as \"prod-*\", or \"" + constants.AllValues + "\" for all the server ids; " +
+			"omitted when the platform url is passed with --" + constants.UrlFlag},
+		{Name: "node-id", Description: "Selected node id, a comma separated list of node ids or \"" + constants.AllValues + "\" for all the nodes"},
+		{Name: "log-name", Description: "Selected log name, a comma separated list of log names or \"" + constants.AllValues + "\" for all the logs"},
 	}
 }
 
 func getLogsFlags() []components.Flag {
-	return []components.Flag{
+	flags := []components.Flag{
 		components.BoolFlag{
 			Name:         constants.InteractiveFlag,
 			Description:  "Activate the interactive menu",
@@ -52,17 +55,38 @@ func getLogsFlags() []components.Flag {
 			Description:  "Perform a 'tail " + constants.TailFlag + "' on the log",
 			DefaultValue: false,
 		},
+		components.StringFlag{
+			Name: constants.RefreshFlag,
+			Description: "Fixed interval between the log queries in tail mode, such as 500ms or 2s; " +
+				"by default the interval adapts to the log activity, and is never shorter than the refresh rate of the server",
+		},
+		getFormatFlag(),
+		components.StringFlag{
+			Name: constants.RecordFlag,
+			Description: "Path of a file recording all the received log data, to replay it later with the replay command; " +
+				"the data is recorded as received, so it cannot be combined with the redaction flags",
+		},
 	}
+	flags = append(flags, getTimeRangeFlags()...)
+	flags = append(flags, getDedupFlags()...)
+	flags = append(flags, getSyslogFlags()...)
+	flags = append(flags, getRateLimitFlag())
+	flags = append(flags, getPlatformFlags()...)
+	flags = append(flags, getColorFlags()...)
+	return append(flags, getRedactionFlags()...)
 }
 
 func getLogsEnvVar() []components.EnvVar {
-	return []components.EnvVar{
+	envVars := []components.EnvVar{
 		{
 			Name:        constants.VersionCheckEnv,
 			Default:     "true",
 			Description: "Set this to \"false\" to disable validation on the minimum supported version of the product.",
 		},
+		getRedactionEnvVar(),
+		getColorEnvVar(),
 	}
+	return append(envVars, getPlatformEnvVars()...)
 }
 
 func logsCmd(c *components.Context) error {
@@ -75,16 +99,88 @@ func logsCmd(c *components.Context) error {
 	var liveLogClient livelog.LiveLogs
 	liveLogClient = livelog.NewLiveLogs()
 
+	redactor, err := getRedactor(c)
+	if err != nil {
+		return err
+	}
+	liveLogClient.SetRedactor(redactor)
+
+	colorizer, err := getColorizer(c)
+	if err != nil {
+		return err
+	}
+	liveLogClient.SetColorizer(colorizer)
+
+	timeRange, err := getTimeRange(c)
+	if err != nil {
+		return err
+	}
+	liveLogClient.SetTimeRange(timeRange)
+
+	deduplication, err := getDeduplication(c)
+	if err != nil {
+		return err
+	}
+	liveLogClient.SetDeduplication(deduplication)
+
+	lineFormatter, err := getLineFormatter(c)
+	if err != nil {
+		return err
+	}
+	liveLogClient.SetLineFormatter(lineFormatter)
+
+	err = setRateLimit(c)
+	if err != nil {
+		return err
+	}
+
+	if refreshValue := c.GetStringFlagValue(constants.RefreshFlag); refreshValue != "" {
+		refreshRate, err := time.ParseDuration(refreshValue)
+		if err != nil || refreshRate <= 0 {
+			return fmt.Errorf("invalid %s value [%s], expected a positive duration such as 500ms or 2s", constants.RefreshFlag, refreshValue)
+		}
+		liveLogClient.SetRefreshRateOverride(refreshRate)
+	}
+
+	syslogForwarder, err := getSyslogForwarder(c)
+	if err != nil {
+		return err
+	}
+	if syslogForwarder != nil {
+		defer syslogForwarder.Close()
+		liveLogClient.SetSyslogForwarder(syslogForwarder)
+	}
+
+	if recordingPath := c.GetStringFlagValue(constants.RecordFlag); recordingPath != "" {
+		if redactor != nil {
+			return fmt.Errorf("--%s cannot be combined with the redaction flags, the log data is recorded as received: "+
+				"record without redaction and pass the redaction flags to the replay command instead", constants.RecordFlag)
+		}
+		recorder, err := recording.NewRecorder(recordingPath)
+		if err != nil {
+			return err
+		}
+		defer recorder.Close()
+		liveLogClient.SetRecorder(recorder)
+	}
+
+	platformServerId, err := getPlatformServerId(c)
+	if err != nil {
+		return err
+	}
+	liveLogClient.SetServiceId(platformServerId)
+
 	ListenForTermination(mainCtxCancel)
 
 	if !isInteractive {
-		if len(c.Arguments) != 4 {
-			return fmt.Errorf("incorrect number of arguments were passed: expected: 4," + " received: " + strconv.Itoa(len(c.Arguments)))
+		arguments := withServerId(c.Arguments, platformServerId)
+		if len(arguments) != 4 {
+			return fmt.Errorf("incorrect number of arguments were passed: expected: 4," + " received: " + strconv.Itoa(len(arguments)))
 		}
-		productId := c.Arguments[0]
-		serverId := c.Arguments[1]
-		nodeId := c.Arguments[2]
-		logFileName := c.Arguments[3]
+		productId := arguments[0]
+		serverId := arguments[1]
+		nodeId := arguments[2]
+		logFileName := arguments[3]
 		return liveLogClient.LogNonInteractive(mainCtx, productId, serverId, nodeId, logFileName, isStreaming)
 	}
 	return LogInteractiveMenu(mainCtx, isStreaming, liveLogClient)
@@ -0,0 +1,76 @@
+package commands
+
+import (
+	"fmt"
+	"github.com/jfrog/jfrog-cli-core/v2/plugins/components"
+	"github.com/jfrog/live-logs/internal/clientlayer"
+	"github.com/jfrog/live-logs/internal/constants"
+	"os"
+	"strings"
+)
+
+func getPlatformFlags() []components.Flag {
+	return []components.Flag{
+		components.StringFlag{
+			Name: constants.UrlFlag,
+			Description: "JFrog platform url, such as https://acme.jfrog.io, used instead of the server-id argument " +
+				"without a JFrog CLI config entry; the urls of all the products are derived from it",
+		},
+		components.StringFlag{
+			Name:        constants.AccessTokenFlag,
+			Description: "Access token authenticating with the platform url",
+		},
+	}
+}
+
+func getPlatformEnvVars() []components.EnvVar {
+	return []components.EnvVar{
+		{
+			Name:        constants.UrlEnv,
+			Description: "JFrog platform url, used when the --" + constants.UrlFlag + " flag is not passed.",
+		},
+		{
+			Name:        constants.AccessTokenEnv,
+			Description: "Access token authenticating with the platform url, used when the --" + constants.AccessTokenFlag + " flag is not passed.",
+		},
+	}
+}
+
+// Registers the platform url passed with the flags or the environment variables, and returns its server id.
+// An empty server id is returned when no platform url is passed, the server id is then expected as an argument.
+func getPlatformServerId(c *components.Context) (string, error) {
+	platformUrl := getFlagOrEnv(c, constants.UrlFlag, constants.UrlEnv)
+	if platformUrl == "" {
+		return "", nil
+	}
+	accessToken := getFlagOrEnv(c, constants.AccessTokenFlag, constants.AccessTokenEnv)
+	if accessToken == "" {
+		return "", fmt.Errorf("an access token is required with the platform url, pass --%s or set %s", constants.AccessTokenFlag, constants.AccessTokenEnv)
+	}
+	return clientlayer.RegisterPlatform(platformUrl, accessToken), nil
+}
+
+// Inserts the server id of a platform passed with its url after the product id argument, as the server id argument
+// is omitted in that case.
+func withServerId(arguments []string, platformServerId string) []string {
+	if platformServerId == "" || len(arguments) == 0 {
+		return arguments
+	}
+	return append([]string{arguments[0], platformServerId}, arguments[1:]...)
+}
+
+func getFlagOrEnv(c *components.Context, flagName, envName string) string {
+	if value := c.GetStringFlagValue(flagName); value != "" {
+		return value
+	}
+	return os.Getenv(envName)
+}
+
+// Formats the server of a non-interactive equivalent command. A registered platform is passed with the url flag,
+// ahead of the arguments, and its access token is left out.
+func formatServerArgument(serverId string) (flags, argument string) {
+	if clientlayer.IsRegisteredPlatform(serverId) {
+		return "--" + constants.UrlFlag + "=" + strings.TrimSuffix(serverId, "/") + " ", ""
+	}
+	return "", " " + serverId
+}
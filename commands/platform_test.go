@@ -0,0 +1,24 @@
+package commands
+
+import (
+	"github.com/jfrog/live-logs/internal/clientlayer"
+	"github.com/stretchr/testify/require"
+	"testing"
+)
+
+func TestFormatServerArgument(t *testing.T) {
+	flags, argument := formatServerArgument("local-arti")
+	require.Empty(t, flags)
+	require.Equal(t, " local-arti", argument)
+
+	serverId := clientlayer.RegisterPlatform("https://acme.jfrog.io", "some-token")
+	flags, argument = formatServerArgument(serverId)
+	require.Equal(t, "--url=https://acme.jfrog.io ", flags)
+	require.Empty(t, argument)
+}
+
+func TestWithServerId(t *testing.T) {
+	require.Equal(t, []string{"rt", "node-1"}, withServerId([]string{"rt", "node-1"}, ""))
+	require.Equal(t, []string{"rt", "https://acme.jfrog.io/", "node-1"}, withServerId([]string{"rt", "node-1"}, "https://acme.jfrog.io/"))
+	require.Empty(t, withServerId(nil, "https://acme.jfrog.io/"))
+}
@@ -0,0 +1,72 @@
+package commands
+
+import (
+	"context"
+	"fmt"
+	"github.com/jfrog/jfrog-cli-core/v2/plugins/components"
+	"github.com/jfrog/live-logs/internal"
+	"github.com/jfrog/live-logs/internal/constants"
+	"strconv"
+)
+
+func GetProductsCommand() components.Command {
+	return components.Command{
+		Name: "products",
+		Description: "Display which products can be used with a server id; for each product, whether its url is configured, " +
+			"it is reachable, the credentials are authorized to read its logs, and its version is supported",
+		Aliases:   []string{"discover"},
+		Arguments: getProductsArguments(),
+		Flags:     getProductsFlags(),
+		EnvVars:   getProductsEnvVar(),
+		Action:    productsCmd,
+	}
+}
+
+func getProductsArguments() []components.Argument {
+	return []components.Argument{
+		{Name: "server-id", Description: "JFrog CLI Artifactory server id"},
+	}
+}
+
+func getProductsFlags() []components.Flag {
+	return []components.Flag{
+		components.BoolFlag{
+			Name:         constants.InteractiveFlag,
+			Description:  "Activate the interactive menu",
+			DefaultValue: false,
+		},
+	}
+}
+
+func getProductsEnvVar() []components.EnvVar {
+	return []components.EnvVar{
+		{
+			Name:        constants.VersionCheckEnv,
+			Default:     "true",
+			Description: "Set this to \"false\" to disable validation on the minimum supported version of the product.",
+		},
+	}
+}
+
+func productsCmd(c *components.Context) error {
+	isInteractive := c.GetBoolFlagValue(constants.InteractiveFlag)
+
+	mainCtx, mainCtxCancel := context.WithCancel(context.Background())
+	defer mainCtxCancel()
+
+	var liveLogClient livelog.LiveLogs
+	liveLogClient = livelog.NewLiveLogs()
+
+	if !isInteractive {
+		if len(c.Arguments) != 1 {
+			return fmt.Errorf("incorrect number of arguments were passed: expected: 1," + " received: " + strconv.Itoa(len(c.Arguments)))
+		}
+		return liveLogClient.DisplayProducts(mainCtx, c.Arguments[0])
+	}
+	selectedCliServerId, err := selectCliServerId()
+	if err != nil {
+		return err
+	}
+	PromptForAnyKey(constants.NonIntCmdDisplayPrefix + " \n\t jfrog live-logs products " + selectedCliServerId)
+	return liveLogClient.DisplayProducts(mainCtx, selectedCliServerId)
+}
@@ -0,0 +1,40 @@
+package commands
+
+import (
+	"fmt"
+	"github.com/jfrog/jfrog-cli-core/v2/plugins/components"
+	"github.com/jfrog/live-logs/internal/clientlayer"
+	"github.com/jfrog/live-logs/internal/constants"
+	"strconv"
+)
+
+func getRateLimitFlag() components.Flag {
+	return components.StringFlag{
+		Name: constants.RateLimitFlag,
+		Description: "Maximum number of requests per second sent to each server, shared by all the log streams of the command; " +
+			"0 disables the limit",
+		DefaultValue: strconv.Itoa(clientlayer.DefaultRequestsPerSecond),
+	}
+}
+
+// Applies the rate limit flag to all the requests of the command.
+func setRateLimit(c *components.Context) error {
+	rateLimitValue := c.GetStringFlagValue(constants.RateLimitFlag)
+	if rateLimitValue == "" {
+		return nil
+	}
+	requestsPerSecond, err := parseRateLimit(rateLimitValue)
+	if err != nil {
+		return err
+	}
+	clientlayer.SetRequestsPerSecond(requestsPerSecond)
+	return nil
+}
+
+func parseRateLimit(rateLimitValue string) (float64, error) {
+	requestsPerSecond, err := strconv.ParseFloat(rateLimitValue, 64)
+	if err != nil || requestsPerSecond < 0 {
+		return 0, fmt.Errorf("invalid %s value [%s], expected a number of requests per second", constants.RateLimitFlag, rateLimitValue)
+	}
+	return requestsPerSecond, nil
+}
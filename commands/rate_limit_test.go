@@ -0,0 +1,31 @@
+package commands
+
+import (
+	"github.com/stretchr/testify/require"
+	"testing"
+)
+
+func TestParseRateLimit(t *testing.T) {
+	tests := []struct {
+		value     string
+		expected  float64
+		expectErr bool
+	}{
+		{"10", 10, false},
+		{"0.5", 0.5, false},
+		{"0", 0, false},
+		{"-1", 0, true},
+		{"fast", 0, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.value, func(t *testing.T) {
+			requestsPerSecond, err := parseRateLimit(tt.value)
+			if tt.expectErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tt.expected, requestsPerSecond)
+		})
+	}
+}
@@ -0,0 +1,86 @@
+package commands
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"github.com/jfrog/jfrog-cli-core/v2/plugins/components"
+	"github.com/jfrog/live-logs/internal/constants"
+	"github.com/jfrog/live-logs/internal/redact"
+	"io"
+	"os"
+)
+
+// The length of the random salt used when no salt is set.
+const randomSaltLength = 32
+
+func getRedactionFlags() []components.Flag {
+	return []components.Flag{
+		components.BoolFlag{
+			Name: constants.RedactFlag,
+			Description: "Redact secrets and personal data before output: bearer tokens, API keys, access tokens, " +
+				"passwords and tokens in query strings, emails, IP addresses and request log user names",
+			DefaultValue: false,
+		},
+		components.StringFlag{
+			Name: constants.RedactRulesFlag,
+			Description: "Path to a JSON file with additional redaction rules, " +
+				"a list of {\"name\", \"pattern\", \"group\"} objects; implies --" + constants.RedactFlag,
+		},
+		components.BoolFlag{
+			Name: constants.PseudonymizeFlag,
+			Description: "Replace every redacted value with a consistent hash instead of a placeholder, " +
+				"so the same value is always replaced the same way; implies --" + constants.RedactFlag,
+			DefaultValue: false,
+		},
+	}
+}
+
+func getRedactionEnvVar() components.EnvVar {
+	return components.EnvVar{
+		Name:        constants.RedactSaltEnv,
+		Description: "The secret key used to hash the values replaced by --" + constants.PseudonymizeFlag + "; a random key is used for each run when not set.",
+	}
+}
+
+// Creates the redactor matching the command flags, nil is returned when redaction is disabled.
+func getRedactor(c *components.Context) (*redact.Redactor, error) {
+	rulesFilePath := c.GetStringFlagValue(constants.RedactRulesFlag)
+	pseudonymize := c.GetBoolFlagValue(constants.PseudonymizeFlag)
+	if !c.GetBoolFlagValue(constants.RedactFlag) && rulesFilePath == "" && !pseudonymize {
+		return nil, nil
+	}
+
+	rules := redact.BuiltInRules()
+	if rulesFilePath != "" {
+		userRules, err := redact.LoadRules(rulesFilePath)
+		if err != nil {
+			return nil, err
+		}
+		rules = append(rules, userRules...)
+	}
+	salt := ""
+	if pseudonymize {
+		var err error
+		salt, err = getRedactionSalt(os.Stderr)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return redact.NewRedactor(rules, pseudonymize, salt), nil
+}
+
+// Returns the salt of the pseudonyms. Without a salt, the pseudonyms of the IP addresses and emails could be reversed
+// by hashing known values, so a random salt is generated for the run, and a notice tells that the pseudonyms are not
+// stable across runs.
+func getRedactionSalt(notices io.Writer) (string, error) {
+	if salt := os.Getenv(constants.RedactSaltEnv); salt != "" {
+		return salt, nil
+	}
+	randomSalt := make([]byte, randomSaltLength)
+	if _, err := rand.Read(randomSalt); err != nil {
+		return "", fmt.Errorf("failed to generate the pseudonyms salt: %w", err)
+	}
+	fmt.Fprintf(notices, "%s is not set, a random salt is used: the pseudonyms are not the same from one run to another\n", constants.RedactSaltEnv)
+	return hex.EncodeToString(randomSalt), nil
+}
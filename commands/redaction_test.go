@@ -0,0 +1,38 @@
+package commands
+
+import (
+	"bytes"
+	"github.com/jfrog/live-logs/internal/constants"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"os"
+	"testing"
+)
+
+func TestGetRedactionSalt(t *testing.T) {
+	realSalt, isSet := os.LookupEnv(constants.RedactSaltEnv)
+	defer func() {
+		if isSet {
+			_ = os.Setenv(constants.RedactSaltEnv, realSalt)
+		} else {
+			_ = os.Unsetenv(constants.RedactSaltEnv)
+		}
+	}()
+
+	require.NoError(t, os.Setenv(constants.RedactSaltEnv, "secret"))
+	notices := &bytes.Buffer{}
+	salt, err := getRedactionSalt(notices)
+	require.NoError(t, err)
+	assert.Equal(t, "secret", salt)
+	assert.Empty(t, notices.String())
+
+	// Without a salt, a random salt is generated for each run.
+	require.NoError(t, os.Unsetenv(constants.RedactSaltEnv))
+	first, err := getRedactionSalt(notices)
+	require.NoError(t, err)
+	second, err := getRedactionSalt(notices)
+	require.NoError(t, err)
+	assert.Len(t, first, 2*randomSaltLength)
+	assert.NotEqual(t, first, second)
+	assert.Contains(t, notices.String(), constants.RedactSaltEnv+" is not set")
+}
@@ -0,0 +1,99 @@
+package commands
+
+import (
+	"context"
+	"fmt"
+	"github.com/jfrog/jfrog-cli-core/v2/plugins/components"
+	"github.com/jfrog/live-logs/internal"
+	"github.com/jfrog/live-logs/internal/constants"
+	"strconv"
+)
+
+func GetReplayCommand() components.Command {
+	return components.Command{
+		Name: "replay",
+		Description: "Replay a log recording created with the --" + constants.RecordFlag + " option of the logs command, " +
+			"at the original or at an accelerated speed",
+		Aliases:   []string{"r"},
+		Arguments: getReplayArguments(),
+		Flags:     getReplayFlags(),
+		EnvVars:   []components.EnvVar{getRedactionEnvVar(), getColorEnvVar()},
+		Action:    replayCmd,
+	}
+}
+
+func getReplayArguments() []components.Argument {
+	return []components.Argument{
+		{Name: "recording-file", Description: "Path of the recording file"},
+	}
+}
+
+func getReplayFlags() []components.Flag {
+	flags := []components.Flag{
+		components.StringFlag{
+			Name:         constants.SpeedFlag,
+			Description:  "Replay speed, relative to the original speed of the recording; 0 replays without delays",
+			DefaultValue: "1",
+		},
+		getFormatFlag(),
+	}
+	flags = append(flags, getTimeRangeFlags()...)
+	flags = append(flags, getDedupFlags()...)
+	flags = append(flags, getColorFlags()...)
+	return append(flags, getRedactionFlags()...)
+}
+
+func replayCmd(c *components.Context) error {
+	if len(c.Arguments) != 1 {
+		return fmt.Errorf("incorrect number of arguments were passed: expected: 1," + " received: " + strconv.Itoa(len(c.Arguments)))
+	}
+	recordingPath := c.Arguments[0]
+
+	speed := 1.0
+	if speedValue := c.GetStringFlagValue(constants.SpeedFlag); speedValue != "" {
+		var err error
+		speed, err = strconv.ParseFloat(speedValue, 64)
+		if err != nil || speed < 0 {
+			return fmt.Errorf("invalid %s value [%s], expected a positive number such as 10, or 0 for no delays", constants.SpeedFlag, speedValue)
+		}
+	}
+
+	mainCtx, mainCtxCancel := context.WithCancel(context.Background())
+	defer mainCtxCancel()
+
+	var liveLogClient livelog.LiveLogs
+	liveLogClient = livelog.NewLiveLogs()
+
+	redactor, err := getRedactor(c)
+	if err != nil {
+		return err
+	}
+	liveLogClient.SetRedactor(redactor)
+
+	colorizer, err := getColorizer(c)
+	if err != nil {
+		return err
+	}
+	liveLogClient.SetColorizer(colorizer)
+
+	timeRange, err := getTimeRange(c)
+	if err != nil {
+		return err
+	}
+	liveLogClient.SetTimeRange(timeRange)
+
+	deduplication, err := getDeduplication(c)
+	if err != nil {
+		return err
+	}
+	liveLogClient.SetDeduplication(deduplication)
+
+	lineFormatter, err := getLineFormatter(c)
+	if err != nil {
+		return err
+	}
+	liveLogClient.SetLineFormatter(lineFormatter)
+
+	ListenForTermination(mainCtxCancel)
+	return liveLogClient.Replay(mainCtx, recordingPath, speed)
+}
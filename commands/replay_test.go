@@ -0,0 +1,46 @@
+package commands
+
+import (
+	"github.com/jfrog/jfrog-cli-core/v2/plugins/components"
+	"github.com/stretchr/testify/assert"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestReplayCmdArguments(t *testing.T) {
+	tests := []struct {
+		name             string
+		ctx              *components.Context
+		wantErrMsgPrefix string
+	}{
+		{
+			name: "zero argument",
+			ctx: &components.Context{
+				Arguments: []string{},
+			},
+			wantErrMsgPrefix: "incorrect number of arguments",
+		},
+		{
+			name: "two argument",
+			ctx: &components.Context{
+				Arguments: []string{"a", "b"},
+			},
+			wantErrMsgPrefix: "incorrect number of arguments",
+		},
+		{
+			name: "missing recording",
+			ctx: &components.Context{
+				Arguments: []string{filepath.Join(t.TempDir(), "missing.jsonl")},
+			},
+			wantErrMsgPrefix: "open",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := replayCmd(tt.ctx)
+			assert.NotNil(t, err)
+			assert.True(t, strings.Contains(err.Error(), tt.wantErrMsgPrefix))
+		})
+	}
+}
@@ -0,0 +1,134 @@
+package commands
+
+import (
+	"context"
+	"fmt"
+	"github.com/jfrog/jfrog-cli-core/v2/plugins/components"
+	"github.com/jfrog/live-logs/internal"
+	"github.com/jfrog/live-logs/internal/constants"
+	"github.com/jfrog/live-logs/internal/relay"
+	"net"
+	"net/http"
+	"os"
+	"strconv"
+)
+
+func GetServeCommand() components.Command {
+	return components.Command{
+		Name: "serve",
+		Description: "Follow the selected logs once and relay their lines to any number of local clients, over Server-Sent Events " +
+			"on /events and WebSocket on /ws, with a minimal viewer on /, until interrupted",
+		Arguments: getServeArguments(),
+		Flags:     getServeFlags(),
+		EnvVars:   getServeEnvVar(),
+		Action:    serveCmd,
+	}
+}
+
+func getServeArguments() []components.Argument {
+	return []components.Argument{
+		{Name: "product-id", Description: "JFrog product id; the value can be one of the following, \n" +
+			"\t\t\t" + constants.ArtifactoryId + " - Artifactory\n" +
+			"\t\t\t" + constants.XrayId + " - Xray\n" +
+			"\t\t\t" + constants.McId + " - Mission Control\n" +
+			"\t\t\t" + constants.DistributionId + " - Distribution\n" +
+			"\t\t\t" + constants.PipelinesId + " - Pipelines"},
+		{Name: "server-id", Description: "JFrog CLI Artifactory server id; omitted when the platform url is passed with --" + constants.UrlFlag},
+		{Name: "node-id", Description: "Selected node id, a comma separated list of node ids or \"" + constants.AllValues + "\" for all the nodes"},
+		{Name: "log-name", Description: "Selected log name, a comma separated list of log names or \"" + constants.AllValues + "\" for all the logs"},
+	}
+}
+
+func getServeFlags() []components.Flag {
+	flags := []components.Flag{
+		components.StringFlag{
+			Name:         constants.ListenFlag,
+			Description:  "Address the relay listens on; pass :8080 to let other machines connect",
+			DefaultValue: relay.DefaultListenAddress,
+		},
+		components.StringFlag{
+			Name:         constants.HistoryFlag,
+			Description:  "Number of the last lines kept for the clients which connect or reconnect",
+			DefaultValue: strconv.Itoa(relay.DefaultHistorySize),
+		},
+	}
+	flags = append(flags, getRateLimitFlag())
+	flags = append(flags, getPlatformFlags()...)
+	return append(flags, getRedactionFlags()...)
+}
+
+func getServeEnvVar() []components.EnvVar {
+	envVars := []components.EnvVar{
+		{
+			Name:        constants.VersionCheckEnv,
+			Default:     "true",
+			Description: "Set this to \"false\" to disable validation on the minimum supported version of the product.",
+		},
+		getRedactionEnvVar(),
+	}
+	return append(envVars, getPlatformEnvVars()...)
+}
+
+func serveCmd(c *components.Context) error {
+	platformServerId, err := getPlatformServerId(c)
+	if err != nil {
+		return err
+	}
+	arguments := withServerId(c.Arguments, platformServerId)
+	if len(arguments) != 4 {
+		return fmt.Errorf("incorrect number of arguments were passed: expected: 4," + " received: " + strconv.Itoa(len(arguments)))
+	}
+
+	historySize, err := parseHistorySize(c.GetStringFlagValue(constants.HistoryFlag))
+	if err != nil {
+		return err
+	}
+
+	mainCtx, mainCtxCancel := context.WithCancel(context.Background())
+	defer mainCtxCancel()
+
+	var liveLogClient livelog.LiveLogs
+	liveLogClient = livelog.NewLiveLogs()
+
+	redactor, err := getRedactor(c)
+	if err != nil {
+		return err
+	}
+	liveLogClient.SetRedactor(redactor)
+
+	err = setRateLimit(c)
+	if err != nil {
+		return err
+	}
+
+	listenAddress := c.GetStringFlagValue(constants.ListenFlag)
+	if listenAddress == "" {
+		listenAddress = relay.DefaultListenAddress
+	}
+	listener, err := net.Listen("tcp", listenAddress)
+	if err != nil {
+		return err
+	}
+	hub := relay.NewHub(historySize)
+	server := &http.Server{Handler: relay.NewHandler(hub, listenAddress)}
+	go func() {
+		_ = server.Serve(listener)
+	}()
+	defer server.Close()
+	defer hub.Close()
+	fmt.Fprintf(os.Stderr, "Relaying the logs on http://%s\n", listener.Addr())
+
+	ListenForTermination(mainCtxCancel)
+	return liveLogClient.Serve(mainCtx, arguments[0], arguments[1], arguments[2], arguments[3], hub)
+}
+
+func parseHistorySize(value string) (int, error) {
+	if value == "" {
+		return relay.DefaultHistorySize, nil
+	}
+	historySize, err := strconv.Atoi(value)
+	if err != nil || historySize < 0 {
+		return 0, fmt.Errorf("invalid %s value [%s], expected a positive number or 0", constants.HistoryFlag, value)
+	}
+	return historySize, nil
+}
@@ -0,0 +1,52 @@
+package commands
+
+import (
+	"github.com/jfrog/jfrog-cli-core/v2/plugins/components"
+	"github.com/stretchr/testify/assert"
+	"strings"
+	"testing"
+)
+
+func TestServeCmdArguments(t *testing.T) {
+	tests := []struct {
+		name      string
+		arguments []string
+	}{
+		{"zero argument", []string{}},
+		{"three argument", []string{"a", "b", "c"}},
+		{"five argument", []string{"a", "b", "c", "d", "e"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := serveCmd(&components.Context{Arguments: tt.arguments})
+			assert.NotNil(t, err)
+			assert.True(t, strings.HasPrefix(err.Error(), "incorrect number of arguments"), err.Error())
+		})
+	}
+}
+
+func TestParseHistorySize(t *testing.T) {
+	tests := []struct {
+		name    string
+		value   string
+		want    int
+		wantErr bool
+	}{
+		{name: "default", want: 1000},
+		{name: "value", value: "200", want: 200},
+		{name: "disabled", value: "0", want: 0},
+		{name: "negative", value: "-1", wantErr: true},
+		{name: "invalid", value: "many", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			historySize, err := parseHistorySize(tt.value)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, historySize)
+		})
+	}
+}
@@ -0,0 +1,196 @@
+package commands
+
+import (
+	"context"
+	"fmt"
+	"github.com/jfrog/jfrog-cli-core/v2/plugins/components"
+	"github.com/jfrog/live-logs/internal"
+	"github.com/jfrog/live-logs/internal/constants"
+	"github.com/jfrog/live-logs/internal/ship"
+	"strconv"
+	"strings"
+	"time"
+)
+
+func GetShipCommand() components.Command {
+	return components.Command{
+		Name: "ship",
+		Description: "Follow the selected logs and ship their lines to Grafana Loki, Elasticsearch, Splunk HTTP Event Collector, " +
+			"an OpenTelemetry OTLP/HTTP receiver or any HTTP endpoint accepting JSON, until interrupted; the shipping resumes from a checkpoint file when restarted",
+		Arguments: getShipArguments(),
+		Flags:     getShipFlags(),
+		EnvVars:   getShipEnvVar(),
+		Action:    shipCmd,
+	}
+}
+
+func getShipArguments() []components.Argument {
+	return []components.Argument{
+		{Name: "product-id", Description: "JFrog product id; the value can be one of the following, \n" +
+			"\t\t\t" + constants.ArtifactoryId + " - Artifactory\n" +
+			"\t\t\t" + constants.XrayId + " - Xray\n" +
+			"\t\t\t" + constants.McId + " - Mission Control\n" +
+			"\t\t\t" + constants.DistributionId + " - Distribution\n" +
+			"\t\t\t" + constants.PipelinesId + " - Pipelines"},
+		{Name: "server-id", Description: "JFrog CLI Artifactory server id; omitted when the platform url is passed with --" + constants.UrlFlag},
+		{Name: "node-id", Description: "Selected node id, a comma separated list of node ids or \"" + constants.AllValues + "\" for all the nodes"},
+		{Name: "log-name", Description: "Selected log name, a comma separated list of log names or \"" + constants.AllValues + "\" for all the logs"},
+	}
+}
+
+func getShipFlags() []components.Flag {
+	flags := []components.Flag{
+		components.StringFlag{
+			Name:        constants.SinkFlag,
+			Description: "Destination of the log lines, one of " + strings.Join(ship.SinkTypes(), ", "),
+		},
+		components.StringFlag{
+			Name: constants.SinkUrlFlag,
+			Description: "Base url of Loki, Elasticsearch, Splunk or the OTLP receiver, such as http://loki:3100, the push endpoint is appended to it; " +
+				"the full endpoint url for the http sink",
+		},
+		components.StringFlag{
+			Name: constants.SinkTokenFlag,
+			Description: "Token authenticating with the sink: a bearer token for Loki, otlp and http, an API key for Elasticsearch, " +
+				"and the HTTP Event Collector token for Splunk",
+		},
+		components.StringFlag{
+			Name:        constants.SinkIndexFlag,
+			Description: "Elasticsearch index [Default: " + ship.DefaultIndex + "], or Splunk index [Default: the token default index]",
+		},
+		components.StringFlag{
+			Name:        constants.SinkHeadersFlag,
+			Description: "Comma separated list of key=value headers sent to the sink, with URL encoded values, such as x-api-key=abc,x-tenant=acme",
+		},
+		components.StringFlag{
+			Name:         constants.BatchSizeFlag,
+			Description:  "Maximum number of lines sent in a single request",
+			DefaultValue: strconv.Itoa(ship.DefaultBatchSize),
+		},
+		components.StringFlag{
+			Name:         constants.FlushIntervalFlag,
+			Description:  "Maximum time a line waits for its batch to fill up before it is sent",
+			DefaultValue: ship.DefaultFlushInterval.String(),
+		},
+		components.StringFlag{
+			Name:         constants.CheckpointFlag,
+			Description:  "Path of the file keeping the position of the shipped lines in each log file",
+			DefaultValue: constants.DefaultCheckpointFile,
+		},
+		getLogTimezoneFlag(),
+	}
+	flags = append(flags, getRateLimitFlag())
+	flags = append(flags, getPlatformFlags()...)
+	return append(flags, getRedactionFlags()...)
+}
+
+func getShipEnvVar() []components.EnvVar {
+	envVars := []components.EnvVar{
+		{
+			Name:        constants.VersionCheckEnv,
+			Default:     "true",
+			Description: "Set this to \"false\" to disable validation on the minimum supported version of the product.",
+		},
+		{
+			Name:        constants.SinkTokenEnv,
+			Description: "Token authenticating with the sink, used when the --" + constants.SinkTokenFlag + " flag is not passed.",
+		},
+		{
+			Name:        constants.SinkHeadersEnv,
+			Description: "Headers sent to the sink, used when the --" + constants.SinkHeadersFlag + " flag is not passed.",
+		},
+		getRedactionEnvVar(),
+	}
+	return append(envVars, getPlatformEnvVars()...)
+}
+
+func shipCmd(c *components.Context) error {
+	platformServerId, err := getPlatformServerId(c)
+	if err != nil {
+		return err
+	}
+	arguments := withServerId(c.Arguments, platformServerId)
+	if len(arguments) != 4 {
+		return fmt.Errorf("incorrect number of arguments were passed: expected: 4," + " received: " + strconv.Itoa(len(arguments)))
+	}
+
+	shipper, err := getShipper(c)
+	if err != nil {
+		return err
+	}
+
+	mainCtx, mainCtxCancel := context.WithCancel(context.Background())
+	defer mainCtxCancel()
+
+	var liveLogClient livelog.LiveLogs
+	liveLogClient = livelog.NewLiveLogs()
+
+	redactor, err := getRedactor(c)
+	if err != nil {
+		return err
+	}
+	liveLogClient.SetRedactor(redactor)
+
+	err = setRateLimit(c)
+	if err != nil {
+		return err
+	}
+
+	ListenForTermination(mainCtxCancel)
+	return liveLogClient.Ship(mainCtx, arguments[0], arguments[1], arguments[2], arguments[3], shipper)
+}
+
+// Creates the shipper matching the command flags.
+func getShipper(c *components.Context) (*ship.Shipper, error) {
+	headers, err := ship.ParseHeaders(getFlagOrEnv(c, constants.SinkHeadersFlag, constants.SinkHeadersEnv))
+	if err != nil {
+		return nil, err
+	}
+	sink, err := ship.NewSink(ship.SinkConfig{
+		Type:    c.GetStringFlagValue(constants.SinkFlag),
+		Url:     c.GetStringFlagValue(constants.SinkUrlFlag),
+		Token:   getFlagOrEnv(c, constants.SinkTokenFlag, constants.SinkTokenEnv),
+		Index:   c.GetStringFlagValue(constants.SinkIndexFlag),
+		Headers: headers,
+	})
+	if err != nil {
+		return nil, err
+	}
+	batchSize, flushInterval, err := parseBatching(c.GetStringFlagValue(constants.BatchSizeFlag), c.GetStringFlagValue(constants.FlushIntervalFlag))
+	if err != nil {
+		return nil, err
+	}
+	logLocation, err := parseLogTimezone(c.GetStringFlagValue(constants.LogTimezoneFlag))
+	if err != nil {
+		return nil, err
+	}
+	checkpointPath := c.GetStringFlagValue(constants.CheckpointFlag)
+	if checkpointPath == "" {
+		checkpointPath = constants.DefaultCheckpointFile
+	}
+	checkpoints, err := ship.LoadCheckpoints(checkpointPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load the checkpoints of %s: %w", checkpointPath, err)
+	}
+	return ship.NewShipper(sink, checkpoints, batchSize, flushInterval, logLocation), nil
+}
+
+func parseBatching(batchSizeValue, flushIntervalValue string) (int, time.Duration, error) {
+	batchSize := ship.DefaultBatchSize
+	if batchSizeValue != "" {
+		var err error
+		batchSize, err = strconv.Atoi(batchSizeValue)
+		if err != nil || batchSize <= 0 {
+			return 0, 0, fmt.Errorf("invalid %s value [%s], expected a positive number", constants.BatchSizeFlag, batchSizeValue)
+		}
+	}
+	flushInterval := ship.DefaultFlushInterval
+	if flushIntervalValue != "" {
+		var err error
+		flushInterval, err = time.ParseDuration(flushIntervalValue)
+		if err != nil || flushInterval <= 0 {
+			return 0, 0, fmt.Errorf("invalid %s value [%s], expected a positive duration such as 5s", constants.FlushIntervalFlag, flushIntervalValue)
+		}
+	}
+	return batchSize, flushInterval, nil
+}
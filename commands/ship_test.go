@@ -0,0 +1,74 @@
+package commands
+
+import (
+	"github.com/jfrog/jfrog-cli-core/v2/plugins/components"
+	"github.com/stretchr/testify/assert"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestShipCmdArguments(t *testing.T) {
+	tests := []struct {
+		name             string
+		ctx              *components.Context
+		wantErrMsgPrefix string
+	}{
+		{
+			name: "zero argument",
+			ctx: &components.Context{
+				Arguments: []string{},
+			},
+			wantErrMsgPrefix: "incorrect number of arguments",
+		},
+		{
+			name: "three argument",
+			ctx: &components.Context{
+				Arguments: []string{"a", "b", "c"},
+			},
+			wantErrMsgPrefix: "incorrect number of arguments",
+		},
+		{
+			name: "four argument",
+			ctx: &components.Context{
+				Arguments: []string{"a", "b", "c", "d"},
+			},
+			wantErrMsgPrefix: "invalid sink",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := shipCmd(tt.ctx)
+			assert.NotNil(t, err)
+			assert.True(t, strings.HasPrefix(err.Error(), tt.wantErrMsgPrefix), err.Error())
+		})
+	}
+}
+
+func TestParseBatching(t *testing.T) {
+	tests := []struct {
+		name              string
+		batchSize         string
+		flushInterval     string
+		wantBatchSize     int
+		wantFlushInterval time.Duration
+		wantErr           bool
+	}{
+		{name: "defaults", wantBatchSize: 500, wantFlushInterval: 5 * time.Second},
+		{name: "values", batchSize: "100", flushInterval: "1s", wantBatchSize: 100, wantFlushInterval: time.Second},
+		{name: "zero batch size", batchSize: "0", wantErr: true},
+		{name: "invalid interval", flushInterval: "soon", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			batchSize, flushInterval, err := parseBatching(tt.batchSize, tt.flushInterval)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.wantBatchSize, batchSize)
+			assert.Equal(t, tt.wantFlushInterval, flushInterval)
+		})
+	}
+}
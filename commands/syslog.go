@@ -0,0 +1,52 @@
+package commands
+
+import (
+	"github.com/jfrog/jfrog-cli-core/v2/plugins/components"
+	"github.com/jfrog/live-logs/internal/constants"
+	"github.com/jfrog/live-logs/internal/syslog"
+	"strings"
+)
+
+func getSyslogFlags() []components.Flag {
+	return []components.Flag{
+		components.StringFlag{
+			Name: constants.SyslogFlag,
+			Description: "Forward the log lines to a syslog server as RFC 5424 messages, such as udp://siem:514, tcp://siem:514 " +
+				"or tls://siem:6514",
+		},
+		components.StringFlag{
+			Name: constants.SyslogModeFlag,
+			Description: "Content of the syslog messages, one of " + strings.Join(syslog.Modes(), ", ") + "; " + syslog.RawMode +
+				" sends the whole lines, " + syslog.ParsedMode + " sends the severity, the time and the fields parsed from the service and request log lines",
+			DefaultValue: syslog.RawMode,
+		},
+		components.StringFlag{
+			Name:        constants.SyslogAppNameFlag,
+			Description: "APP-NAME of the syslog messages [Default: jfrog- followed by the product id]",
+		},
+		components.StringFlag{
+			Name:         constants.SyslogFacilityFlag,
+			Description:  "Facility of the syslog messages, such as user or local0",
+			DefaultValue: syslog.DefaultFacility,
+		},
+		components.StringFlag{
+			Name:        constants.SyslogCaFlag,
+			Description: "PEM file of the certificate authorities trusted by the tls transport [Default: the system ones]",
+		},
+	}
+}
+
+// Creates the syslog forwarder matching the command flags, nil is returned when the lines are not forwarded.
+func getSyslogForwarder(c *components.Context) (*syslog.Forwarder, error) {
+	syslogUrl := c.GetStringFlagValue(constants.SyslogFlag)
+	if syslogUrl == "" {
+		return nil, nil
+	}
+	return syslog.NewForwarder(syslog.Config{
+		Url:      syslogUrl,
+		Mode:     c.GetStringFlagValue(constants.SyslogModeFlag),
+		AppName:  c.GetStringFlagValue(constants.SyslogAppNameFlag),
+		Facility: c.GetStringFlagValue(constants.SyslogFacilityFlag),
+		CaFile:   c.GetStringFlagValue(constants.SyslogCaFlag),
+	})
+}
@@ -10,12 +10,13 @@ import (
 	"github.com/jfrog/live-logs/internal/util"
 	"os"
 	"os/signal"
+	"strings"
 	"syscall"
-	"time"
 )
 
 var PromptForAnyKey = util.PromptAndWaitForAnyKey
 var PromptSelectMenu = util.RunInteractiveMenu
+var PromptMultiSelectMenu = util.RunInteractiveMultiSelectMenu
 var CliServerIds = cliCommands.GetAllServerIds
 
 func ListenForTermination(cancelCtx context.CancelFunc) {
@@ -30,77 +31,107 @@ func ListenForTermination(cancelCtx context.CancelFunc) {
 }
 
 func ConfigInteractive(ctx context.Context, liveLog livelog.LiveLogs) error {
-	selectedProductId, err := selectProductId()
+	selectedCliServerId, err := selectServerId(liveLog)
 	if err != nil {
 		return err
 	}
+	liveLog.SetServiceId(selectedCliServerId)
 
-	liveLog.SetProductId(selectedProductId)
-	selectedCliServerId, err := selectCliServerId()
+	selectedProductId, err := selectProductId(ctx, liveLog, selectedCliServerId)
 	if err != nil {
 		return err
 	}
+	liveLog.SetProductId(selectedProductId)
+	cmdDisplayPostfix := ""
+	if liveLog.GetConfigFormat() != constants.JsonFormat {
+		cmdDisplayPostfix = " --" + constants.FormatFlag + "=" + liveLog.GetConfigFormat()
+	}
 
-	liveLog.SetServiceId(selectedCliServerId)
+	serverFlags, serverArgument := formatServerArgument(selectedCliServerId)
 	nonInteractiveMessage := constants.NonIntCmdDisplayPrefix + " \n\t jfrog live-logs config " +
-		selectedProductId + " " +
-		selectedCliServerId
+		serverFlags + selectedProductId +
+		serverArgument + cmdDisplayPostfix
 	PromptForAnyKey(nonInteractiveMessage)
 	return liveLog.DisplayConfig(ctx)
 }
 
+// The server id set beforehand, such as a platform passed with its url, is used without prompting.
+func selectServerId(liveLog livelog.LiveLogs) (string, error) {
+	if liveLog.GetServiceId() != "" {
+		return liveLog.GetServiceId(), nil
+	}
+	return selectCliServerId()
+}
+
 func selectCliServerId() (string, error) {
 	serverIds := CliServerIds()
 	return PromptSelectMenu("Select JFrog CLI server id", "Available server IDs", serverIds)
 }
 
-func selectProductId() (string, error) {
-	productIds := util.FetchAllProductIds()
+// Only the products whose logs can be read with the server id are offered.
+func selectProductId(ctx context.Context, liveLog livelog.LiveLogs, serverId string) (string, error) {
+	statuses, err := liveLog.DiscoverProducts(ctx, serverId)
+	if err != nil {
+		return "", err
+	}
+	var productIds, unavailable []string
+	for _, status := range statuses {
+		if status.IsAvailable() {
+			productIds = append(productIds, status.ProductId)
+		} else {
+			unavailable = append(unavailable, status.ProductId+": "+status.Error)
+		}
+	}
+	if len(productIds) == 0 {
+		return "", fmt.Errorf("no available products were found for the server id %s;\n%s", serverId, strings.Join(unavailable, "\n"))
+	}
 	return PromptSelectMenu("Select JFrog CLI product id", "Available product IDs", productIds)
 }
 
 func LogInteractiveMenu(ctx context.Context, isStreaming bool, liveLog livelog.LiveLogs) error {
-	selectedProductId, err := selectProductId()
+	selectedCliServerId, err := selectServerId(liveLog)
 	if err != nil {
 		return err
 	}
-	liveLog.SetProductId(selectedProductId)
+	liveLog.SetServiceId(selectedCliServerId)
 
-	selectedCliServerId, err := selectCliServerId()
+	selectedProductId, err := selectProductId(ctx, liveLog, selectedCliServerId)
 	if err != nil {
 		return err
 	}
-	liveLog.SetServiceId(selectedCliServerId)
+	liveLog.SetProductId(selectedProductId)
 
-	nodeId, logName, logsRefreshRate, err := selectLogDetails(ctx, liveLog)
+	nodeIds, logNames, srvConfig, err := selectLogDetails(ctx, liveLog)
 	if err != nil {
 		return err
 	}
-	liveLog.SetLogsRefreshRate(logsRefreshRate)
+	liveLog.SetLogsRefreshRate(util.MillisToDuration(srvConfig.RefreshRateMillis))
 
 	cmdDisplayPostfix := ""
 	if isStreaming {
 		cmdDisplayPostfix = " -" + constants.TailFlag + cmdDisplayPostfix
 	}
 
+	serverFlags, serverArgument := formatServerArgument(selectedCliServerId)
 	nonInteractiveMessage := constants.NonIntCmdDisplayPrefix + " \n\t jfrog live-logs logs " +
-		selectedProductId + " " +
-		selectedCliServerId + " " +
-		nodeId + " " +
-		logName + cmdDisplayPostfix
+		serverFlags + selectedProductId +
+		serverArgument + " " +
+		util.FormatSelection(nodeIds, srvConfig.Nodes) + " " +
+		util.FormatSelection(logNames, srvConfig.LogFileNames) + cmdDisplayPostfix
 	PromptForAnyKey(nonInteractiveMessage)
-	return liveLog.PrintLogs(ctx, nodeId, logName, isStreaming)
+	return liveLog.PrintMultipleLogs(ctx, nodeIds, logNames, isStreaming)
 }
 
-func selectLogDetails(ctx context.Context, liveLog livelog.LiveLogs) (selectedNodeID string, selectedLogName string, logsRefreshRate time.Duration, err error) {
-	var srvConfig *model.Config
+func selectLogDetails(ctx context.Context, liveLog livelog.LiveLogs) (selectedNodeIds []string, selectedLogNames []string, srvConfig *model.Config, err error) {
 	srvConfig, err = liveLog.GetConfigData(ctx, liveLog.GetProductId(), liveLog.GetServiceId())
 	if err != nil {
 		return
 	}
 
-	logsRefreshRate = util.MillisToDuration(srvConfig.RefreshRateMillis)
-	selectedNodeID, err = PromptSelectMenu("Select Node Id", "Available Node Ids", srvConfig.Nodes)
-	selectedLogName, err = PromptSelectMenu("Select log name", "Available log names", srvConfig.LogFileNames)
+	selectedNodeIds, err = PromptMultiSelectMenu("Select Node Ids", "Available Node Ids", srvConfig.Nodes)
+	if err != nil {
+		return
+	}
+	selectedLogNames, err = PromptMultiSelectMenu("Select log names", "Available log names", srvConfig.LogFileNames)
 	return
 }
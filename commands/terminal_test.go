@@ -3,8 +3,17 @@ package commands
 import (
 	"context"
 	"fmt"
+	"github.com/jfrog/live-logs/internal/client"
+	"github.com/jfrog/live-logs/internal/color"
+	"github.com/jfrog/live-logs/internal/dedup"
+	"github.com/jfrog/live-logs/internal/format"
 	"github.com/jfrog/live-logs/internal/model"
+	"github.com/jfrog/live-logs/internal/redact"
+	"github.com/jfrog/live-logs/internal/relay"
 	"github.com/jfrog/live-logs/internal/servicelayer"
+	"github.com/jfrog/live-logs/internal/ship"
+	"github.com/jfrog/live-logs/internal/syslog"
+	"github.com/jfrog/live-logs/internal/timerange"
 	"io"
 	"io/ioutil"
 	"os"
@@ -109,6 +118,7 @@ func Test_terminal_LogInteractive(t *testing.T) {
 
 			var origPromptForAnyKey=PromptForAnyKey
 			var origPromptSelectMenu=PromptSelectMenu
+			var origPromptMultiSelectMenu=PromptMultiSelectMenu
 
 			PromptForAnyKey = func(promptPrefix string)  {
 				return
@@ -117,11 +127,14 @@ func Test_terminal_LogInteractive(t *testing.T) {
 				if tt.wantErr {
 					return tt.selectedKey, fmt.Errorf("%s",tt.mockGetErr)
 				} else {
-					if strings.Contains(selectionHeader,"Node") {
-						return tt.nodeId,nil
-					} else {
-						return tt.logName,nil
-					}
+					return tt.selectedKey, nil
+				}
+			}
+			PromptMultiSelectMenu = func(selectionHeader string, selectionLabel string, values []string) ([]string, error) {
+				if strings.Contains(selectionHeader,"Node") {
+					return []string{tt.nodeId},nil
+				} else {
+					return []string{tt.logName},nil
 				}
 			}
 			rescueStdout := os.Stdout
@@ -136,6 +149,7 @@ func Test_terminal_LogInteractive(t *testing.T) {
 			os.Stdout = rescueStdout
 			PromptForAnyKey=origPromptForAnyKey
 			PromptSelectMenu=origPromptSelectMenu
+			PromptMultiSelectMenu=origPromptMultiSelectMenu
 
 			if tt.wantErr && !strings.Contains(err.Error(),tt.mockExpectErr) {
 				t.Errorf("LogInteractiveMenu() error = %v, wantErr %v", err, tt.wantErr)
@@ -197,49 +211,57 @@ func Test_terminal_selectCliServerId(t *testing.T) {
 func Test_terminal_selectLogDetails(t *testing.T) {
 	tests := []struct {
 		name            string
-		want            string
 		wantErr         bool
-		nodeId          string
+		nodeIds         []string
 		mockGetErr      string
-		logName         string
-		selectedKey     string
+		logNames        []string
 	}{
 		{
 			name:            "log Interactive",
-			nodeId:          "node1",
-			logName:          "artifactory-service.log",
+			nodeIds:         []string{"node1"},
+			logNames:        []string{"artifactory-service.log"},
+		},
+		{
+			name:            "multiple nodes and logs",
+			nodeIds:         []string{"node1", "node2"},
+			logNames:        []string{"artifactory-service.log", "artifactory-request.log"},
+		},
+		{
+			name:            "selection error",
+			wantErr:         true,
+			mockGetErr:      "some error",
 		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			s := &mockLiveLog{}
-			var origPromptSelectMenu=PromptSelectMenu
+			var origPromptMultiSelectMenu=PromptMultiSelectMenu
 
-			PromptSelectMenu = func(selectionHeader string, selectionLabel string, values []string) (string, error) {
+			PromptMultiSelectMenu = func(selectionHeader string, selectionLabel string, values []string) ([]string, error) {
 				if tt.wantErr {
-					return tt.selectedKey, fmt.Errorf("%s",tt.mockGetErr)
+					return nil, fmt.Errorf("%s",tt.mockGetErr)
 				} else {
 					if strings.Contains(selectionHeader,"Node") {
-						return tt.nodeId,nil
+						return tt.nodeIds,nil
 					} else {
-						return tt.logName,nil
+						return tt.logNames,nil
 					}
 				}
 			}
 
-			nodeId,logName,_,err := selectLogDetails(context.Background(), s)
+			nodeIds,logNames,_,err := selectLogDetails(context.Background(), s)
 
-			PromptSelectMenu=origPromptSelectMenu
+			PromptMultiSelectMenu=origPromptMultiSelectMenu
 
 			if (err != nil) != tt.wantErr {
 				t.Errorf("selectLogDetails() error = %v, wantErr %v", err, tt.wantErr)
 				return
 			}
-			if !reflect.DeepEqual(nodeId, tt.nodeId) {
-				t.Errorf("selectLogDetails() got = %v, want %v", nodeId, tt.nodeId)
+			if !reflect.DeepEqual(nodeIds, tt.nodeIds) {
+				t.Errorf("selectLogDetails() got = %v, want %v", nodeIds, tt.nodeIds)
 			}
-			if !reflect.DeepEqual(logName, tt.logName) {
-				t.Errorf("selectLogDetails() got = %v, want %v", logName, tt.logName)
+			if !reflect.DeepEqual(logNames, tt.logNames) {
+				t.Errorf("selectLogDetails() got = %v, want %v", logNames, tt.logNames)
 			}
 		})
 	}
@@ -248,35 +270,37 @@ func Test_terminal_selectLogDetails(t *testing.T) {
 func Test_terminal_selectProductId(t *testing.T) {
 	tests := []struct {
 		name            string
-		productId       string
-		serviceId       string
-		logsRefreshRate time.Duration
-		lastPageMarker  int64
-		mockConfigResponse string
-		mockGetErr      error
+		statuses        []model.ProductStatus
+		wantOffered     []string
 		want            string
 		wantErr         bool
 		selectedKey     string
 	}{
 		{
 			name:            "selectProductId",
+			statuses:         []model.ProductStatus{availableProduct("rt"), {ProductId: "xr", Error: "not configured"}},
+			wantOffered:      []string{"rt"},
 			selectedKey:      "rt",
 			want:             "rt",
 		},
+		{
+			name:            "no available product",
+			statuses:         []model.ProductStatus{{ProductId: "xr", Error: "not configured"}},
+			wantErr:          true,
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
+			s := &mockLiveLog{productStatuses: tt.statuses}
 			var origPromptSelectMenu=PromptSelectMenu
 
+			var offered []string
 			PromptSelectMenu = func(selectionHeader string, selectionLabel string, values []string) (string, error) {
-				if tt.wantErr {
-					return tt.selectedKey, fmt.Errorf("%s",tt.mockGetErr)
-				} else {
-					return tt.selectedKey, nil
-				}
+				offered = values
+				return tt.selectedKey, nil
 			}
 
-			productId,err := selectProductId()
+			productId,err := selectProductId(context.Background(), s, "local-artifactory")
 
 			PromptSelectMenu=origPromptSelectMenu
 
@@ -284,6 +308,9 @@ func Test_terminal_selectProductId(t *testing.T) {
 				t.Errorf("selectProductId() error = %v, wantErr %v", err, tt.wantErr)
 				return
 			}
+			if !reflect.DeepEqual(offered, tt.wantOffered) {
+				t.Errorf("selectProductId() offered = %v, want %v", offered, tt.wantOffered)
+			}
 			if !reflect.DeepEqual(productId, tt.want) {
 				t.Errorf("selectProductId() got = %v, want %v", productId, tt.want)
 			}
@@ -291,6 +318,10 @@ func Test_terminal_selectProductId(t *testing.T) {
 	}
 }
 
+func availableProduct(productId string) model.ProductStatus {
+	return model.ProductStatus{ProductId: productId, Configured: true, Reachable: true, Authorized: true, SupportedVersion: true}
+}
+
 type mockLiveLog struct {
 	productId       string
 	serviceId       string
@@ -299,6 +330,7 @@ type mockLiveLog struct {
 	mockConfigResponse string
 	LogName         string
 	nodeId          string
+	productStatuses []model.ProductStatus
 }
 
 func (s *mockLiveLog) SetProductId(productId string) {
@@ -358,6 +390,11 @@ func (s *mockLiveLog) PrintLogs (ctx context.Context, nodeId, logName  string, i
 	return nil
 }
 
+func (s *mockLiveLog) PrintMultipleLogs(ctx context.Context, nodeIds, logNames []string, isStreaming bool) error {
+	fmt.Printf("nodeId is %s, logName is %s", strings.Join(nodeIds, ","), strings.Join(logNames, ","))
+	return nil
+}
+
 func (s *mockLiveLog) DisplayConfig(ctx context.Context)  error {
 	fmt.Println(s.mockConfigResponse)
 	return nil
@@ -366,3 +403,73 @@ func (s *mockLiveLog) DisplayConfig(ctx context.Context)  error {
 func (s *mockLiveLog)  ConfigNonInteractive(ctx context.Context, cliProductId, cliServerId string) error {
 	return nil
 }
+
+func (s *mockLiveLog) ExportBundle(ctx context.Context, cliProductId, cliServerId, outputPath string, concurrency int) error {
+	return nil
+}
+
+func (s *mockLiveLog) SetConfigFormat(configFormat string) {
+}
+
+func (s *mockLiveLog) GetConfigFormat() string {
+	return "json"
+}
+
+func (s *mockLiveLog) SetRedactor(redactor *redact.Redactor) {
+}
+
+func (s *mockLiveLog) StreamLog(ctx context.Context, nodeId, logName string, output io.Writer) error {
+	return nil
+}
+
+func (s *mockLiveLog) DiscoverProducts(ctx context.Context, cliServerId string) ([]model.ProductStatus, error) {
+	if s.productStatuses == nil {
+		return []model.ProductStatus{availableProduct("rt")}, nil
+	}
+	return s.productStatuses, nil
+}
+
+func (s *mockLiveLog) DisplayProducts(ctx context.Context, cliServerId string) error {
+	return nil
+}
+
+func (s *mockLiveLog) Doctor(ctx context.Context, cliProductId, cliServerId string) error {
+	return nil
+}
+
+func (s *mockLiveLog) Trace(ctx context.Context, cliServerId, traceId string, productIds []string) error {
+	return nil
+}
+
+func (s *mockLiveLog) SetRefreshRateOverride(refreshRate time.Duration) {
+}
+
+func (s *mockLiveLog) SetRecorder(recorder client.Recorder) {
+}
+
+func (s *mockLiveLog) SetColorizer(colorizer *color.Colorizer) {
+}
+
+func (s *mockLiveLog) SetLineFormatter(lineFormatter *format.Formatter) {
+}
+
+func (s *mockLiveLog) SetTimeRange(timeRange *timerange.Range) {
+}
+
+func (s *mockLiveLog) SetSyslogForwarder(syslogForwarder *syslog.Forwarder) {
+}
+
+func (s *mockLiveLog) SetDeduplication(deduplication *dedup.Config) {
+}
+
+func (s *mockLiveLog) Ship(ctx context.Context, cliProductId, cliServerId, nodeId, logName string, shipper *ship.Shipper) error {
+	return nil
+}
+
+func (s *mockLiveLog) Serve(ctx context.Context, cliProductId, cliServerId, nodeId, logName string, hub *relay.Hub) error {
+	return nil
+}
+
+func (s *mockLiveLog) Replay(ctx context.Context, recordingPath string, speed float64) error {
+	return nil
+}
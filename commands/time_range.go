@@ -0,0 +1,82 @@
+package commands
+
+import (
+	"fmt"
+	"github.com/jfrog/jfrog-cli-core/v2/plugins/components"
+	"github.com/jfrog/live-logs/internal/constants"
+	"github.com/jfrog/live-logs/internal/timerange"
+	"time"
+)
+
+// Overridden by the tests.
+var now = time.Now
+
+func getTimeRangeFlags() []components.Flag {
+	return []components.Flag{
+		components.StringFlag{
+			Name: constants.SinceFlag,
+			Description: "Only show the lines logged since a time, either a duration before now such as 20m, " +
+				"or a timestamp such as 2021-03-25T04:30:00Z, \"2021-03-25 04:30\" or 04:30 in the local time zone",
+		},
+		components.StringFlag{
+			Name:        constants.UntilFlag,
+			Description: "Only show the lines logged until a time, in the same formats as --" + constants.SinceFlag,
+		},
+		getLogTimezoneFlag(),
+	}
+}
+
+func getLogTimezoneFlag() components.Flag {
+	return components.StringFlag{
+		Name:         constants.LogTimezoneFlag,
+		Description:  "Time zone of the log timestamps which do not specify one, such as UTC, Local or Europe/Paris",
+		DefaultValue: "UTC",
+	}
+}
+
+// Creates the time range matching the command flags, nil is returned when no bound is set.
+func getTimeRange(c *components.Context) (*timerange.Range, error) {
+	sinceValue := c.GetStringFlagValue(constants.SinceFlag)
+	untilValue := c.GetStringFlagValue(constants.UntilFlag)
+	if sinceValue == "" && untilValue == "" {
+		return nil, nil
+	}
+	return parseTimeRange(sinceValue, untilValue, c.GetStringFlagValue(constants.LogTimezoneFlag))
+}
+
+func parseTimeRange(sinceValue, untilValue, logTimezone string) (*timerange.Range, error) {
+	logLocation, err := parseLogTimezone(logTimezone)
+	if err != nil {
+		return nil, err
+	}
+	timeRange := &timerange.Range{LogLocation: logLocation}
+	currentTime := now()
+	if sinceValue != "" {
+		timeRange.Since, err = timerange.ParseTime(sinceValue, currentTime, time.Local)
+		if err != nil {
+			return nil, fmt.Errorf("invalid %s value: %w", constants.SinceFlag, err)
+		}
+	}
+	if untilValue != "" {
+		timeRange.Until, err = timerange.ParseTime(untilValue, currentTime, time.Local)
+		if err != nil {
+			return nil, fmt.Errorf("invalid %s value: %w", constants.UntilFlag, err)
+		}
+	}
+	if !timeRange.Since.IsZero() && !timeRange.Until.IsZero() && timeRange.Until.Before(timeRange.Since) {
+		return nil, fmt.Errorf("the %s time must not be before the %s time", constants.UntilFlag, constants.SinceFlag)
+	}
+	return timeRange, nil
+}
+
+// Returns the location of the log timestamps which do not specify a time zone, UTC by default.
+func parseLogTimezone(logTimezone string) (*time.Location, error) {
+	if logTimezone == "" {
+		return time.UTC, nil
+	}
+	location, err := time.LoadLocation(logTimezone)
+	if err != nil {
+		return nil, fmt.Errorf("invalid %s value [%s]: %w", constants.LogTimezoneFlag, logTimezone, err)
+	}
+	return location, nil
+}
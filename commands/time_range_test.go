@@ -0,0 +1,47 @@
+package commands
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+	"time"
+)
+
+func TestParseTimeRange(t *testing.T) {
+	realNow := now
+	defer func() { now = realNow }()
+	currentTime := time.Date(2021, 3, 25, 12, 0, 0, 0, time.UTC)
+	now = func() time.Time { return currentTime }
+
+	tests := []struct {
+		name             string
+		since            string
+		until            string
+		logTimezone      string
+		wantSince        time.Time
+		wantUntil        time.Time
+		wantErrMsgPrefix string
+	}{
+		{name: "relative since", since: "20m", wantSince: currentTime.Add(-20 * time.Minute)},
+		{name: "absolute range", since: "2021-03-25T04:00:00Z", until: "2021-03-25T05:00:00Z", logTimezone: "UTC",
+			wantSince: time.Date(2021, 3, 25, 4, 0, 0, 0, time.UTC), wantUntil: time.Date(2021, 3, 25, 5, 0, 0, 0, time.UTC)},
+		{name: "reversed range", since: "10m", until: "20m", wantErrMsgPrefix: "the until time must not be before the since time"},
+		{name: "invalid since", since: "yesterday", wantErrMsgPrefix: "invalid since value"},
+		{name: "invalid time zone", since: "10m", logTimezone: "Mars/Olympus", wantErrMsgPrefix: "invalid log-timezone value"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			timeRange, err := parseTimeRange(tt.since, tt.until, tt.logTimezone)
+			if tt.wantErrMsgPrefix != "" {
+				assert.Error(t, err)
+				if err != nil {
+					assert.Contains(t, err.Error(), tt.wantErrMsgPrefix)
+				}
+				return
+			}
+			assert.NoError(t, err)
+			assert.True(t, tt.wantSince.Equal(timeRange.Since))
+			assert.True(t, tt.wantUntil.Equal(timeRange.Until))
+			assert.Equal(t, time.UTC, timeRange.LogLocation)
+		})
+	}
+}
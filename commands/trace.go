@@ -0,0 +1,92 @@
+package commands
+
+import (
+	"context"
+	"fmt"
+	"github.com/jfrog/jfrog-cli-core/v2/plugins/components"
+	"github.com/jfrog/live-logs/internal"
+	"github.com/jfrog/live-logs/internal/constants"
+	"github.com/jfrog/live-logs/internal/util"
+	"strconv"
+)
+
+func GetTraceCommand() components.Command {
+	return components.Command{
+		Name: "trace",
+		Description: "Search all the logs of all the nodes for the lines containing a trace id, " +
+			"and display them grouped by service, in time order",
+		Aliases:   []string{"tr"},
+		Arguments: getTraceArguments(),
+		Flags:     getTraceFlags(),
+		EnvVars:   getTraceEnvVar(),
+		Action:    traceCmd,
+	}
+}
+
+func getTraceArguments() []components.Argument {
+	return []components.Argument{
+		{Name: "server-id", Description: "JFrog CLI Artifactory server id"},
+		{Name: "trace-id", Description: "The trace id to search for"},
+	}
+}
+
+func getTraceFlags() []components.Flag {
+	flags := []components.Flag{
+		components.StringFlag{
+			Name: constants.ProductsFlag,
+			Description: "Comma separated list of the product ids to search, or \"" + constants.AllValues + "\" for all the products; " +
+				"the values can be " + util.SliceToCsv(util.FetchAllProductIds()),
+			DefaultValue: constants.ArtifactoryId,
+		},
+	}
+	flags = append(flags, getRateLimitFlag())
+	return append(flags, getRedactionFlags()...)
+}
+
+func getTraceEnvVar() []components.EnvVar {
+	return []components.EnvVar{
+		{
+			Name:        constants.VersionCheckEnv,
+			Default:     "true",
+			Description: "Set this to \"false\" to disable validation on the minimum supported version of the product.",
+		},
+		getRedactionEnvVar(),
+	}
+}
+
+func traceCmd(c *components.Context) error {
+	if len(c.Arguments) != 2 {
+		return fmt.Errorf("incorrect number of arguments were passed: expected: 2," + " received: " + strconv.Itoa(len(c.Arguments)))
+	}
+	serverId := c.Arguments[0]
+	traceId := c.Arguments[1]
+
+	products := c.GetStringFlagValue(constants.ProductsFlag)
+	if products == "" {
+		products = constants.ArtifactoryId
+	}
+	productIds, err := util.ParseSelection("product id", products, util.FetchAllProductIds())
+	if err != nil {
+		return err
+	}
+
+	mainCtx, mainCtxCancel := context.WithCancel(context.Background())
+	defer mainCtxCancel()
+
+	var liveLogClient livelog.LiveLogs
+	liveLogClient = livelog.NewLiveLogs()
+
+	redactor, err := getRedactor(c)
+	if err != nil {
+		return err
+	}
+	liveLogClient.SetRedactor(redactor)
+
+	err = setRateLimit(c)
+	if err != nil {
+		return err
+	}
+
+	ListenForTermination(mainCtxCancel)
+	return liveLogClient.Trace(mainCtx, serverId, traceId, productIds)
+}
@@ -0,0 +1,45 @@
+package commands
+
+import (
+	"github.com/jfrog/jfrog-cli-core/v2/plugins/components"
+	"github.com/stretchr/testify/assert"
+	"strings"
+	"testing"
+)
+
+func TestTraceCmdArguments(t *testing.T) {
+	tests := []struct {
+		name             string
+		ctx              *components.Context
+		wantErrMsgPrefix string
+	}{
+		{
+			name: "zero argument",
+			ctx: &components.Context{
+				Arguments: []string{},
+			},
+			wantErrMsgPrefix: "incorrect number of arguments",
+		},
+		{
+			name: "one argument",
+			ctx: &components.Context{
+				Arguments: []string{"a"},
+			},
+			wantErrMsgPrefix: "incorrect number of arguments",
+		},
+		{
+			name: "two argument",
+			ctx: &components.Context{
+				Arguments: []string{"a", "b"},
+			},
+			wantErrMsgPrefix: "server id",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := traceCmd(tt.ctx)
+			assert.NotNil(t, err)
+			assert.True(t, strings.Contains(err.Error(), tt.wantErrMsgPrefix))
+		})
+	}
+}
@@ -0,0 +1,117 @@
+package commands
+
+import (
+	"context"
+	"fmt"
+	"github.com/jfrog/jfrog-cli-core/v2/plugins/components"
+	"github.com/jfrog/live-logs/internal"
+	"github.com/jfrog/live-logs/internal/constants"
+	"github.com/jfrog/live-logs/internal/model"
+	"github.com/jfrog/live-logs/internal/tui"
+	"github.com/jfrog/live-logs/internal/util"
+	"os"
+	"strconv"
+)
+
+func GetTuiCommand() components.Command {
+	return components.Command{
+		Name: "tui",
+		Description: "Open a full-screen terminal UI, following several node and log file pairs side by side" +
+			"\n\nKeys:" +
+			"\n\to: open a pane, s: switch the node of the focused pane, x: close the focused pane, tab: focus the next pane" +
+			"\n\tp: pause or resume the focused pane, up/down/page up/page down/home/end: scroll back" +
+			"\n\t/: search and highlight, n/N: previous/next match, r: reload the nodes and log files, q: quit",
+		Aliases:   []string{"t"},
+		Arguments: getTuiArguments(),
+		Flags:     append([]components.Flag{getRateLimitFlag()}, getRedactionFlags()...),
+		EnvVars:   getTuiEnvVar(),
+		Action:    tuiCmd,
+	}
+}
+
+func getTuiArguments() []components.Argument {
+	return []components.Argument{
+		{Name: "product-id", Description: "JFrog product id, selected from a menu when omitted; the value can be one of the following, \n" +
+			"\t\t\t" + constants.ArtifactoryId + " - Artifactory\n" +
+			"\t\t\t" + constants.XrayId + " - Xray\n" +
+			"\t\t\t" + constants.McId + " - Mission Control\n" +
+			"\t\t\t" + constants.DistributionId + " - Distribution\n" +
+			"\t\t\t" + constants.PipelinesId + " - Pipelines"},
+		{Name: "server-id", Description: "JFrog CLI Artifactory server id, selected from a menu when omitted"},
+	}
+}
+
+func getTuiEnvVar() []components.EnvVar {
+	return []components.EnvVar{
+		{
+			Name:        constants.VersionCheckEnv,
+			Default:     "true",
+			Description: "Set this to \"false\" to disable validation on the minimum supported version of the product.",
+		},
+		getRedactionEnvVar(),
+	}
+}
+
+func tuiCmd(c *components.Context) error {
+	if len(c.Arguments) != 0 && len(c.Arguments) != 2 {
+		return fmt.Errorf("incorrect number of arguments were passed: expected: 0 or 2," + " received: " + strconv.Itoa(len(c.Arguments)))
+	}
+
+	mainCtx, mainCtxCancel := context.WithCancel(context.Background())
+	defer mainCtxCancel()
+
+	var liveLogClient livelog.LiveLogs
+	liveLogClient = livelog.NewLiveLogs()
+
+	redactor, err := getRedactor(c)
+	if err != nil {
+		return err
+	}
+	liveLogClient.SetRedactor(redactor)
+
+	err = setRateLimit(c)
+	if err != nil {
+		return err
+	}
+
+	productId, serverId, err := selectTuiTarget(mainCtx, liveLogClient, c.Arguments)
+	if err != nil {
+		return err
+	}
+	liveLogClient.SetProductId(productId)
+	liveLogClient.SetServiceId(serverId)
+	return RunTui(mainCtx, liveLogClient)
+}
+
+// Returns the product id and server id passed as arguments, or selected from the interactive menus when not passed.
+func selectTuiTarget(ctx context.Context, liveLog livelog.LiveLogs, arguments []string) (productId, serverId string, err error) {
+	if len(arguments) == 0 {
+		serverId, err = selectCliServerId()
+		if err != nil {
+			return
+		}
+		productId, err = selectProductId(ctx, liveLog, serverId)
+		return
+	}
+	productId, serverId = arguments[0], arguments[1]
+	err = util.ValidateArgument("product id", productId, util.FetchAllProductIds())
+	if err != nil {
+		return
+	}
+	err = util.ValidateArgument("server id", serverId, CliServerIds())
+	return
+}
+
+// Opens the terminal UI, discovering the nodes and log files with the configured product id and server id.
+func RunTui(ctx context.Context, liveLog livelog.LiveLogs) error {
+	discover := func(ctx context.Context) (*model.Config, error) {
+		srvConfig, err := liveLog.GetConfigData(ctx, liveLog.GetProductId(), liveLog.GetServiceId())
+		if err != nil {
+			return nil, err
+		}
+		liveLog.SetLogsRefreshRate(util.MillisToDuration(srvConfig.RefreshRateMillis))
+		return srvConfig, nil
+	}
+	title := "live-logs " + liveLog.GetProductId() + " " + liveLog.GetServiceId()
+	return tui.NewApp(ctx, title, discover, liveLog.StreamLog).Run(os.Stdin, os.Stdout)
+}
@@ -8,4 +8,7 @@ require (
 	github.com/jfrog/jfrog-client-go v1.18.1
 	github.com/manifoldco/promptui v0.9.0
 	github.com/stretchr/testify v1.8.0
+	golang.org/x/net v0.0.0-20220520000938-2e3eb7b945c2
+	golang.org/x/term v0.0.0-20220526004731-065cf7ba2467
+	gopkg.in/yaml.v3 v3.0.1
 )
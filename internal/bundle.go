@@ -0,0 +1,172 @@
+package livelog
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"github.com/jfrog/live-logs/internal/model"
+	"github.com/jfrog/live-logs/internal/util"
+	"os"
+	"path"
+	"sync"
+	"time"
+)
+
+const (
+	DefaultBundleConcurrency = 4
+	bundleManifestName       = "manifest.json"
+)
+
+// Downloads every log file of every node into a single tar.gz archive, alongside a manifest describing its content.
+// A failure to download a single log file is recorded in the manifest and does not abort the export.
+func (s *Data) ExportBundle(ctx context.Context, cliProductId, cliServerId, outputPath string, concurrency int) error {
+	productIds := util.FetchAllProductIds()
+	err := util.ValidateArgument("product id", cliProductId, productIds)
+	if err != nil {
+		return err
+	}
+	s.SetProductId(cliProductId)
+
+	serverIds := getAllServiceIds()
+	err = util.ValidateArgument("server id", cliServerId, serverIds)
+	if err != nil {
+		return err
+	}
+	s.SetServiceId(cliServerId)
+
+	if concurrency < 1 {
+		return fmt.Errorf("concurrency must be a positive number, received: %d", concurrency)
+	}
+
+	err = s.SetServiceLayer(s.GetProductId())
+	if err != nil {
+		return err
+	}
+	srvConfig, err := s.GetServiceLayer().GetConfig(ctx, s.GetServiceId())
+	if err != nil {
+		return err
+	}
+
+	manifest := model.BundleManifest{
+		ServerId:  s.GetServiceId(),
+		ProductId: s.GetProductId(),
+		Nodes:     srvConfig.Nodes,
+		CreatedAt: time.Now().UTC(),
+	}
+	// The version is informative only, not every product exposes it.
+	manifest.ProductVersion, _ = s.GetServiceLayer().GetVersion(ctx, s.GetServiceId())
+
+	bundleFile, err := os.Create(outputPath)
+	if err != nil {
+		return err
+	}
+	defer bundleFile.Close()
+
+	gzipWriter := gzip.NewWriter(bundleFile)
+	tarWriter := tar.NewWriter(gzipWriter)
+
+	manifest.Files, err = s.downloadBundleFiles(ctx, srvConfig, concurrency, tarWriter)
+	if err != nil {
+		return err
+	}
+
+	manifestData, err := json.MarshalIndent(manifest, "", "  ")
+	if err != nil {
+		return err
+	}
+	err = writeTarEntry(tarWriter, bundleManifestName, manifestData, manifest.CreatedAt)
+	if err != nil {
+		return err
+	}
+	err = tarWriter.Close()
+	if err != nil {
+		return err
+	}
+	err = gzipWriter.Close()
+	if err != nil {
+		return err
+	}
+	return bundleFile.Close()
+}
+
+// Downloads the log files using a bounded number of workers and writes each one to the archive as soon as it is complete.
+// Only a failure to write to the archive is returned, download failures are reported in the returned file entries.
+func (s *Data) downloadBundleFiles(ctx context.Context, srvConfig *model.Config, concurrency int, tarWriter *tar.Writer) ([]model.BundleFile, error) {
+	var files []model.BundleFile
+	for _, nodeId := range srvConfig.Nodes {
+		for _, logName := range srvConfig.LogFileNames {
+			files = append(files, model.BundleFile{NodeId: nodeId, LogName: logName})
+		}
+	}
+
+	jobs := make(chan int)
+	var tarLock sync.Mutex
+	var writeErr error
+	var wg sync.WaitGroup
+	for i := 0; i < concurrency; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for fileIndex := range jobs {
+				file := &files[fileIndex]
+				content, err := s.downloadFullLog(ctx, file.NodeId, file.LogName)
+				file.DownloadedAt = time.Now().UTC()
+				if err != nil {
+					file.Error = err.Error()
+					continue
+				}
+				checksum := sha256.Sum256(content)
+				file.Size = int64(len(content))
+				file.Sha256 = hex.EncodeToString(checksum[:])
+				file.Path = path.Join(file.NodeId, file.LogName)
+
+				tarLock.Lock()
+				if writeErr == nil {
+					writeErr = writeTarEntry(tarWriter, file.Path, content, file.DownloadedAt)
+				}
+				tarLock.Unlock()
+			}
+		}()
+	}
+	for fileIndex := range files {
+		jobs <- fileIndex
+	}
+	close(jobs)
+	wg.Wait()
+	return files, writeErr
+}
+
+// Reads a log file from its beginning, until the remote service has no more content to return.
+func (s *Data) downloadFullLog(ctx context.Context, nodeId, logName string) ([]byte, error) {
+	logClient := s.client()
+	serviceLayer, err := logClient.NewStreamService(nodeId, logName)
+	if err != nil {
+		return nil, err
+	}
+	content, err := logClient.ReadFull(ctx, serviceLayer)
+	if err != nil {
+		return nil, err
+	}
+	if s.redactor != nil {
+		return []byte(s.redactor.Redact(string(content))), nil
+	}
+	return content, nil
+}
+
+func writeTarEntry(tarWriter *tar.Writer, name string, content []byte, modTime time.Time) error {
+	err := tarWriter.WriteHeader(&tar.Header{
+		Name:    name,
+		Mode:    0644,
+		Size:    int64(len(content)),
+		ModTime: modTime,
+	})
+	if err != nil {
+		return err
+	}
+	_, err = tarWriter.Write(content)
+	return err
+}
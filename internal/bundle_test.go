@@ -0,0 +1,116 @@
+package livelog
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"fmt"
+	"github.com/jfrog/live-logs/internal/constants"
+	"github.com/jfrog/live-logs/internal/model"
+	"github.com/jfrog/live-logs/internal/servicelayer"
+	"github.com/stretchr/testify/require"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+type bundleMockServiceLayer struct {
+	mockServiceLayer
+	failedLogName string
+}
+
+func (s *bundleMockServiceLayer) GetLogData(_ context.Context, _ string) (model.Data, error) {
+	if s.logFileName == s.failedLogName {
+		return model.Data{}, fmt.Errorf("some-error")
+	}
+	if s.lastPageMarker > 0 {
+		return model.Data{PageMarker: s.lastPageMarker}, nil
+	}
+	content := s.expectNodeId + "/" + s.logFileName
+	return model.Data{Content: content, PageMarker: int64(len(content))}, nil
+}
+
+func Test_LiveLogs_ExportBundle(t *testing.T) {
+	realServiceLayer := newServiceLayer
+	realGetAllServiceIds := getAllServiceIds
+	defer func() {
+		newServiceLayer = realServiceLayer
+		getAllServiceIds = realGetAllServiceIds
+	}()
+	getAllServiceIds = func() []string {
+		return []string{"test-rt"}
+	}
+	newServiceLayer = func(productId string) (servicelayer.ServiceLayer, error) {
+		return &bundleMockServiceLayer{
+			mockServiceLayer: mockServiceLayer{
+				t:                  t,
+				getConfigResponse:  &model.Config{Nodes: []string{"node1", "node2"}, LogFileNames: []string{"log1", "log2"}},
+				getVersionResponse: "7.41.0",
+			},
+			failedLogName: "log2",
+		}, nil
+	}
+
+	outputPath := filepath.Join(t.TempDir(), "bundle.tar.gz")
+	s := &Data{}
+	err := s.ExportBundle(context.Background(), constants.ArtifactoryId, "test-rt", outputPath, 2)
+	require.NoError(t, err)
+
+	entries := readBundle(t, outputPath)
+	require.Equal(t, "node1/log1", entries["node1/log1"])
+	require.Equal(t, "node2/log1", entries["node2/log1"])
+	require.NotContains(t, entries, "node1/log2")
+
+	var manifest model.BundleManifest
+	require.NoError(t, json.Unmarshal([]byte(entries[bundleManifestName]), &manifest))
+	require.Equal(t, "test-rt", manifest.ServerId)
+	require.Equal(t, constants.ArtifactoryId, manifest.ProductId)
+	require.Equal(t, "7.41.0", manifest.ProductVersion)
+	require.Equal(t, []string{"node1", "node2"}, manifest.Nodes)
+	require.Len(t, manifest.Files, 4)
+	for _, file := range manifest.Files {
+		if file.LogName == "log2" {
+			require.Equal(t, "some-error", file.Error)
+			require.Empty(t, file.Sha256)
+			continue
+		}
+		require.Empty(t, file.Error)
+		require.Equal(t, int64(len(file.NodeId+"/"+file.LogName)), file.Size)
+		require.Len(t, file.Sha256, 64)
+	}
+}
+
+func Test_LiveLogs_ExportBundle_InvalidConcurrency(t *testing.T) {
+	realGetAllServiceIds := getAllServiceIds
+	defer func() { getAllServiceIds = realGetAllServiceIds }()
+	getAllServiceIds = func() []string {
+		return []string{"test-rt"}
+	}
+
+	s := &Data{}
+	err := s.ExportBundle(context.Background(), constants.ArtifactoryId, "test-rt", filepath.Join(t.TempDir(), "bundle.tar.gz"), 0)
+	require.Error(t, err)
+}
+
+func readBundle(t *testing.T, bundlePath string) map[string]string {
+	bundleFile, err := os.Open(bundlePath)
+	require.NoError(t, err)
+	defer bundleFile.Close()
+	gzipReader, err := gzip.NewReader(bundleFile)
+	require.NoError(t, err)
+	tarReader := tar.NewReader(gzipReader)
+
+	entries := map[string]string{}
+	for {
+		header, err := tarReader.Next()
+		if err != nil {
+			break
+		}
+		content, err := ioutil.ReadAll(tarReader)
+		require.NoError(t, err)
+		entries[header.Name] = string(content)
+	}
+	return entries
+}
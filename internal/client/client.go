@@ -0,0 +1,306 @@
+package client
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"github.com/jfrog/live-logs/internal/model"
+	"github.com/jfrog/live-logs/internal/servicelayer"
+	"github.com/jfrog/live-logs/internal/stream"
+	"io"
+	"time"
+)
+
+const (
+	defaultRefreshRate  = time.Second
+	lineFlushPolls      = 3
+	minLineFlushTimeout = 2 * time.Second
+	streamBufferSize    = 64
+)
+
+// Creates the service layer of a product.
+type ServiceFactory func(productId string) (servicelayer.ServiceLayer, error)
+
+// Reads the live logs of a product on a server. The client keeps no state between the calls, several logs can be
+// read and streamed concurrently.
+type Client struct {
+	productId  string
+	serverId   string
+	newService ServiceFactory
+	// The interval between the polls of a stream, which grows while the log is quiet.
+	refreshRate time.Duration
+	// A fixed interval between the polls of a stream, replacing the adaptive interval when set.
+	fixedRefreshRate time.Duration
+	recorder         Recorder
+}
+
+// Receives every chunk of log data read by the client, such as a recording.Recorder.
+type Recorder interface {
+	Record(source stream.Source, logData model.Data) error
+}
+
+func New(productId, serverId string, newService ServiceFactory) *Client {
+	return &Client{
+		productId:   productId,
+		serverId:    serverId,
+		newService:  newService,
+		refreshRate: defaultRefreshRate,
+	}
+}
+
+func (c *Client) ProductId() string {
+	return c.productId
+}
+
+func (c *Client) ServerId() string {
+	return c.serverId
+}
+
+// Sets the interval between the polls of a stream, usually the refresh rate returned with the config. The interval
+// grows while the log is quiet, and is back to the refresh rate as soon as new log data arrives.
+func (c *Client) SetRefreshRate(refreshRate time.Duration) {
+	c.refreshRate = refreshRate
+}
+
+// Sets a fixed interval between the polls of a stream, the interval no longer adapts to the log activity.
+func (c *Client) SetFixedRefreshRate(refreshRate time.Duration) {
+	c.fixedRefreshRate = refreshRate
+}
+
+// Sets the recorder receiving every chunk of log data, recording is disabled when nil.
+func (c *Client) SetRecorder(recorder Recorder) {
+	c.recorder = recorder
+}
+
+// Returns the nodes and log files of the product, along with the refresh rate advised by the server.
+func (c *Client) Config(ctx context.Context) (*model.Config, error) {
+	serviceLayer, err := c.newService(c.productId)
+	if err != nil {
+		return nil, err
+	}
+	return serviceLayer.GetConfig(ctx, c.serverId)
+}
+
+// Returns the version of the product.
+func (c *Client) Version(ctx context.Context) (string, error) {
+	serviceLayer, err := c.newService(c.productId)
+	if err != nil {
+		return "", err
+	}
+	return serviceLayer.GetVersion(ctx, c.serverId)
+}
+
+// Returns the whole content of a log file at the time of the call.
+func (c *Client) Snapshot(ctx context.Context, nodeId, logName string) (io.Reader, error) {
+	serviceLayer, err := c.NewStreamService(nodeId, logName)
+	if err != nil {
+		return nil, err
+	}
+	content, err := c.ReadFull(ctx, serviceLayer)
+	if err != nil {
+		return nil, err
+	}
+	return bytes.NewReader(content), nil
+}
+
+// The lines of a log file, read while the stream is running.
+type LogStream struct {
+	lines chan stream.Line
+	err   error
+}
+
+// Returns the lines of the stream, the channel is closed once the stream ended. The lines must be read until then.
+func (s *LogStream) Lines() <-chan stream.Line {
+	return s.lines
+}
+
+// Returns the error which ended the stream once the lines channel is closed, nil when the context ended it.
+func (s *LogStream) Err() error {
+	return s.err
+}
+
+// Follows a log file from its current end until the context is done. The polls of the stream adapt to the log
+// activity, unless a fixed refresh rate is set.
+func (c *Client) Stream(ctx context.Context, nodeId, logName string) *LogStream {
+	logStream := &LogStream{lines: make(chan stream.Line, streamBufferSize)}
+	go func() {
+		defer close(logStream.lines)
+		serviceLayer, err := c.NewStreamService(nodeId, logName)
+		if err != nil {
+			logStream.err = err
+			return
+		}
+		logStream.err = c.Tail(ctx, serviceLayer, func(lines ...stream.Line) error {
+			for _, line := range lines {
+				// The lines are sent while there is room for them, such as the last partial line once the context is
+				// done, but the reader may stop reading the lines once the context is done.
+				select {
+				case logStream.lines <- line:
+					continue
+				default:
+				}
+				select {
+				case logStream.lines <- line:
+				case <-ctx.Done():
+					return stream.ErrEnded
+				}
+			}
+			return nil
+		})
+	}()
+	return logStream
+}
+
+// Creates a service layer dedicated to a single node and log file, as the service layer keeps the node,
+// the log file name and the page marker state.
+func (c *Client) NewStreamService(nodeId, logName string) (servicelayer.ServiceLayer, error) {
+	serviceLayer, err := c.newService(c.productId)
+	if err != nil {
+		return nil, err
+	}
+	serviceLayer.SetNodeId(nodeId)
+	serviceLayer.SetLogFileName(logName)
+	return serviceLayer, nil
+}
+
+func (c *Client) sourceOf(serviceLayer servicelayer.ServiceLayer) stream.Source {
+	return stream.Source{
+		ServerId:  c.serverId,
+		ProductId: c.productId,
+		NodeId:    serviceLayer.GetNodeId(),
+		LogName:   serviceLayer.GetLogFileName(),
+	}
+}
+
+// Reads the log data once and writes its lines. The writing stops without error once write returns stream.ErrEnded.
+func (c *Client) Cat(ctx context.Context, serviceLayer servicelayer.ServiceLayer, write func(lines ...stream.Line) error) error {
+	serviceLayer.SetLastPageMarker(0)
+	content, err := c.ReadPage(ctx, serviceLayer)
+	if err != nil {
+		return err
+	}
+	lineBuffer := stream.NewLineBuffer(c.sourceOf(serviceLayer))
+	err = write(lineBuffer.Write(content)...)
+	if err == nil {
+		err = write(lineBuffer.Flush()...)
+	}
+	if errors.Is(err, stream.ErrEnded) {
+		return nil
+	}
+	return err
+}
+
+// Polls the log data and writes only complete lines, a trailing partial line is held back until a following poll
+// completes it. It is written as is when no poll completes it within the line flush timeout, or once the context is done.
+// Write is called after every poll, without lines when no line is new. The polls stop without error once write returns
+// stream.ErrEnded.
+func (c *Client) Tail(ctx context.Context, serviceLayer servicelayer.ServiceLayer, write func(lines ...stream.Line) error) error {
+	lineBuffer := stream.NewLineBuffer(c.sourceOf(serviceLayer))
+	serviceLayer.SetLastPageMarker(0)
+	err := c.Follow(ctx, serviceLayer, func(content []byte) error {
+		err := write(lineBuffer.Write(content)...)
+		if err != nil {
+			return err
+		}
+		return write(lineBuffer.FlushStale(c.LineFlushTimeout())...)
+	})
+	if err == nil {
+		err = write(lineBuffer.Flush()...)
+	}
+	if errors.Is(err, stream.ErrEnded) {
+		return nil
+	}
+	return err
+}
+
+// Polls the log data following the last page marker of the service layer until the context is done, and passes the
+// content of every poll to handle, including the empty polls. The page marker of the service layer is past the
+// content when handle is called. The polls adapt to the log activity, unless a fixed refresh rate is set.
+// The polls stop with the first error of handle.
+func (c *Client) Follow(ctx context.Context, serviceLayer servicelayer.ServiceLayer, handle func(content []byte) error) error {
+	scheduler := c.newPollScheduler()
+	curLogRefreshRate := time.Duration(0)
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-time.After(curLogRefreshRate):
+			content, err := c.ReadPage(ctx, serviceLayer)
+			if err != nil {
+				if ctx.Err() != nil {
+					return nil
+				}
+				return err
+			}
+			curLogRefreshRate = scheduler.next(len(content) > 0)
+			err = handle(content)
+			if err != nil {
+				return err
+			}
+		}
+	}
+}
+
+// Reads the log data following the last page marker of the service layer, and moves the page marker past it.
+func (c *Client) ReadPage(ctx context.Context, serviceLayer servicelayer.ServiceLayer) ([]byte, error) {
+	if serviceLayer.GetNodeId() == "" {
+		return nil, fmt.Errorf("node id must be set")
+	}
+	if serviceLayer.GetLogFileName() == "" {
+		return nil, fmt.Errorf("log file name must be set")
+	}
+	logData, err := c.getLogData(ctx, serviceLayer)
+	if err != nil {
+		return nil, err
+	}
+	serviceLayer.SetLastPageMarker(logData.PageMarker)
+	return []byte(logData.Content), nil
+}
+
+func (c *Client) getLogData(ctx context.Context, serviceLayer servicelayer.ServiceLayer) (model.Data, error) {
+	logData, err := serviceLayer.GetLogData(ctx, c.serverId)
+	if err != nil || c.recorder == nil {
+		return logData, err
+	}
+	return logData, c.recorder.Record(c.sourceOf(serviceLayer), logData)
+}
+
+// Reads a log file from its beginning, until the remote service has no more content to return.
+func (c *Client) ReadFull(ctx context.Context, serviceLayer servicelayer.ServiceLayer) ([]byte, error) {
+	serviceLayer.SetLastPageMarker(0)
+
+	var content bytes.Buffer
+	for {
+		logData, err := c.getLogData(ctx, serviceLayer)
+		if err != nil {
+			return nil, err
+		}
+		if logData.Content == "" || logData.PageMarker <= serviceLayer.GetLastPageMarker() {
+			return content.Bytes(), nil
+		}
+		content.WriteString(logData.Content)
+		serviceLayer.SetLastPageMarker(logData.PageMarker)
+	}
+}
+
+// The polls adapt to the log activity, starting from the refresh rate, unless a fixed refresh rate is set.
+func (c *Client) newPollScheduler() *pollScheduler {
+	if c.fixedRefreshRate > 0 {
+		return newPollScheduler(c.fixedRefreshRate, false)
+	}
+	return newPollScheduler(c.refreshRate, true)
+}
+
+// A partial line is flushed after it was held back for a few polls at the minimum interval.
+func (c *Client) LineFlushTimeout() time.Duration {
+	refreshRate := c.refreshRate
+	if c.fixedRefreshRate > 0 {
+		refreshRate = c.fixedRefreshRate
+	}
+	flushTimeout := lineFlushPolls * refreshRate
+	if flushTimeout < minLineFlushTimeout {
+		return minLineFlushTimeout
+	}
+	return flushTimeout
+}
@@ -0,0 +1,145 @@
+package client
+
+import (
+	"context"
+	"fmt"
+	"github.com/jfrog/live-logs/internal/model"
+	"github.com/jfrog/live-logs/internal/servicelayer"
+	"github.com/jfrog/live-logs/internal/stream"
+	"github.com/stretchr/testify/require"
+	"io/ioutil"
+	"testing"
+	"time"
+)
+
+// Returns the pages of a log file in order, each page is returned once the page marker reached its start.
+type pagesServiceLayer struct {
+	servicelayer.ArtifactoryData
+	pages  []string
+	getErr error
+}
+
+func (s *pagesServiceLayer) GetConfig(_ context.Context, _ string) (*model.Config, error) {
+	return &model.Config{Nodes: []string{"node-1"}, LogFileNames: []string{"one.log"}, RefreshRateMillis: 500}, s.getErr
+}
+
+func (s *pagesServiceLayer) GetLogData(_ context.Context, _ string) (model.Data, error) {
+	if s.getErr != nil {
+		return model.Data{}, s.getErr
+	}
+	var pageMarker int64
+	for _, page := range s.pages {
+		if pageMarker == s.GetLastPageMarker() {
+			return model.Data{Content: page, PageMarker: pageMarker + int64(len(page))}, nil
+		}
+		pageMarker += int64(len(page))
+	}
+	return model.Data{PageMarker: pageMarker}, nil
+}
+
+func newPagesClient(pages []string, getErr error) *Client {
+	logClient := New("rt", "test-rt", func(productId string) (servicelayer.ServiceLayer, error) {
+		return &pagesServiceLayer{pages: pages, getErr: getErr}, nil
+	})
+	logClient.SetRefreshRate(time.Millisecond)
+	return logClient
+}
+
+func TestClient_Config(t *testing.T) {
+	srvConfig, err := newPagesClient(nil, nil).Config(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, []string{"node-1"}, srvConfig.Nodes)
+	require.Equal(t, int64(500), srvConfig.RefreshRateMillis)
+}
+
+func TestClient_Snapshot(t *testing.T) {
+	logReader, err := newPagesClient([]string{"first line\n", "second ", "line\n"}, nil).Snapshot(context.Background(), "node-1", "one.log")
+	require.NoError(t, err)
+	content, err := ioutil.ReadAll(logReader)
+	require.NoError(t, err)
+	require.Equal(t, "first line\nsecond line\n", string(content))
+
+	_, err = newPagesClient(nil, fmt.Errorf("some-error")).Snapshot(context.Background(), "node-1", "one.log")
+	require.EqualError(t, err, "some-error")
+}
+
+func TestClient_Stream(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	logStream := newPagesClient([]string{"first li", "ne\nsecond"}, nil).Stream(ctx, "node-1", "one.log")
+
+	var lines []stream.Line
+	for line := range logStream.Lines() {
+		lines = append(lines, line)
+	}
+	require.NoError(t, logStream.Err())
+	source := stream.Source{ServerId: "test-rt", ProductId: "rt", NodeId: "node-1", LogName: "one.log"}
+	require.Equal(t, []stream.Line{
+		{Source: source, Text: "first line"},
+		{Source: source, Text: "second", Partial: true},
+	}, lines)
+}
+
+func TestClient_Stream_Error(t *testing.T) {
+	logStream := newPagesClient(nil, fmt.Errorf("some-error")).Stream(context.Background(), "node-1", "one.log")
+	for range logStream.Lines() {
+	}
+	require.EqualError(t, logStream.Err(), "some-error")
+}
+
+func TestClient_ReadPage_MissingNode(t *testing.T) {
+	logClient := newPagesClient(nil, nil)
+	serviceLayer, err := logClient.NewStreamService("", "one.log")
+	require.NoError(t, err)
+	_, err = logClient.ReadPage(context.Background(), serviceLayer)
+	require.EqualError(t, err, "node id must be set")
+}
+
+type chunksRecorder struct {
+	chunks []string
+}
+
+func (r *chunksRecorder) Record(source stream.Source, logData model.Data) error {
+	r.chunks = append(r.chunks, source.NodeId+" "+logData.Content)
+	return nil
+}
+
+func TestClient_SetRecorder(t *testing.T) {
+	logClient := newPagesClient([]string{"first line\n", "second line\n"}, nil)
+	recorder := &chunksRecorder{}
+	logClient.SetRecorder(recorder)
+	_, err := logClient.Snapshot(context.Background(), "node-1", "one.log")
+	require.NoError(t, err)
+	require.Equal(t, []string{"node-1 first line\n", "node-1 second line\n", "node-1 "}, recorder.chunks)
+}
+
+func TestClient_Cat_Ended(t *testing.T) {
+	logClient := newPagesClient([]string{"first line\nsecond line\nthird line\n"}, nil)
+	serviceLayer, err := logClient.NewStreamService("node-1", "one.log")
+	require.NoError(t, err)
+	var written []string
+	err = logClient.Cat(context.Background(), serviceLayer, func(lines ...stream.Line) error {
+		for _, line := range lines {
+			if line.Text == "second line" {
+				return stream.ErrEnded
+			}
+			written = append(written, line.Text)
+		}
+		return nil
+	})
+	require.NoError(t, err)
+	require.Equal(t, []string{"first line"}, written)
+}
+
+func TestClient_Tail_Ended(t *testing.T) {
+	logClient := newPagesClient([]string{"first line\n"}, nil)
+	serviceLayer, err := logClient.NewStreamService("node-1", "one.log")
+	require.NoError(t, err)
+	err = logClient.Tail(context.Background(), serviceLayer, func(lines ...stream.Line) error {
+		if len(lines) > 0 {
+			return stream.ErrEnded
+		}
+		return nil
+	})
+	require.NoError(t, err)
+}
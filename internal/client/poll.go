@@ -0,0 +1,35 @@
+package client
+
+import "time"
+
+// The interval between the polls of a quiet log grows up to this factor of the minimum interval.
+const maxPollBackoff = 8
+
+// Chooses the interval between the polls of a log. The interval is back to the minimum as soon as a poll returns
+// log data, and doubles after each empty poll, up to a cap. A fixed scheduler always uses the minimum interval.
+type pollScheduler struct {
+	minInterval time.Duration
+	maxInterval time.Duration
+	interval    time.Duration
+}
+
+func newPollScheduler(minInterval time.Duration, isAdaptive bool) *pollScheduler {
+	maxInterval := minInterval
+	if isAdaptive {
+		maxInterval = minInterval * maxPollBackoff
+	}
+	return &pollScheduler{minInterval: minInterval, maxInterval: maxInterval, interval: minInterval}
+}
+
+// Returns the interval until the following poll, given whether the last poll returned log data.
+func (p *pollScheduler) next(hasData bool) time.Duration {
+	if hasData {
+		p.interval = p.minInterval
+		return p.interval
+	}
+	p.interval *= 2
+	if p.interval > p.maxInterval {
+		p.interval = p.maxInterval
+	}
+	return p.interval
+}
@@ -0,0 +1,21 @@
+package client
+
+import (
+	"github.com/stretchr/testify/require"
+	"testing"
+	"time"
+)
+
+func Test_pollScheduler(t *testing.T) {
+	adaptive := newPollScheduler(time.Second, true)
+	var intervals []time.Duration
+	for _, hasData := range []bool{false, false, false, false, false, true, false} {
+		intervals = append(intervals, adaptive.next(hasData))
+	}
+	require.Equal(t, []time.Duration{2 * time.Second, 4 * time.Second, 8 * time.Second, 8 * time.Second, 8 * time.Second,
+		time.Second, 2 * time.Second}, intervals)
+
+	fixed := newPollScheduler(time.Second, false)
+	require.Equal(t, time.Second, fixed.next(false))
+	require.Equal(t, time.Second, fixed.next(true))
+}
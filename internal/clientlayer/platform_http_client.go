@@ -2,19 +2,34 @@ package clientlayer
 
 import (
 	"context"
-	"github.com/jfrog/jfrog-cli-core/v2/artifactory/utils"
-	cliCommands "github.com/jfrog/jfrog-cli-core/v2/common/commands"
+	"github.com/jfrog/jfrog-cli-core/v2/utils/config"
+	"github.com/jfrog/jfrog-cli-core/v2/utils/coreutils"
 	"github.com/jfrog/jfrog-client-go/artifactory"
+	clientConfig "github.com/jfrog/jfrog-client-go/config"
 	"github.com/jfrog/live-logs/internal/constants"
 	"net/http"
 )
 
-func newPlatformHttpClient(cliServerId string) (*platformHttpClient, error) {
-	platformDetails, err := cliCommands.GetConfig(cliServerId, false)
+// Creates a client whose requests are cancelled once the context is done.
+func newPlatformHttpClient(ctx context.Context, platformDetails *config.ServerDetails) (*platformHttpClient, error) {
+	certsPath, err := coreutils.GetJfrogCertsDir()
 	if err != nil {
 		return nil, err
 	}
-	platform, err := utils.CreateServiceManager(platformDetails, -1, 0, false)
+	artAuth, err := platformDetails.CreateArtAuthConfig()
+	if err != nil {
+		return nil, err
+	}
+	serviceConfig, err := clientConfig.NewConfigBuilder().
+		SetServiceDetails(artAuth).
+		SetCertificatesPath(certsPath).
+		SetInsecureTls(platformDetails.InsecureTls).
+		SetContext(ctx).
+		Build()
+	if err != nil {
+		return nil, err
+	}
+	platform, err := artifactory.New(serviceConfig)
 	if err != nil {
 		return nil, err
 	}
@@ -28,16 +43,29 @@ type platformHttpClient struct {
 	platform artifactory.ArtifactoryServicesManager
 }
 
-func SendGet(_ context.Context, cliServerId, endpoint, nodeId, baseUrl string, extraHeaders map[string]string) (*http.Response, []byte, error) {
+// Sends a GET request to the server, once the rate limiter of the server id grants it.
+func SendGet(ctx context.Context, cliServerId, endpoint, nodeId, baseUrl string, extraHeaders map[string]string) (*http.Response, []byte, error) {
+	if limiter := getLimiter(cliServerId); limiter != nil {
+		err := limiter.wait(ctx, streamKey(endpoint, nodeId, baseUrl))
+		if err != nil {
+			return nil, nil, err
+		}
+	}
 
-	platformClient, err := newPlatformHttpClient(cliServerId)
+	platformDetails, err := GetServerDetails(cliServerId, false)
+	if err != nil {
+		return nil, nil, err
+	}
+	platformClient, err := newPlatformHttpClient(ctx, platformDetails)
 	if err != nil {
 		return nil, nil, err
 	}
 	client := platformClient.platform.Client()
 
-	platformDetails, err := cliCommands.GetConfig(cliServerId, false)
 	artAuth, err := platformDetails.CreateArtAuthConfig()
+	if err != nil {
+		return nil, nil, err
+	}
 	httpClientDetails := artAuth.CreateHttpClientDetails()
 
 	if nodeId != constants.EmptyNodeId && nodeId != "" {
@@ -49,7 +77,6 @@ func SendGet(_ context.Context, cliServerId, endpoint, nodeId, baseUrl string, e
 	}
 
 	res, resBody, _, err := client.SendGet(baseUrl+endpoint, true, &httpClientDetails)
-
 	if err != nil {
 		return nil, nil, err
 	}
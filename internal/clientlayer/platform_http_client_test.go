@@ -0,0 +1,36 @@
+package clientlayer
+
+import (
+	"context"
+	"github.com/stretchr/testify/require"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestSendGet_Cancel(t *testing.T) {
+	defer SetRequestsPerSecond(DefaultRequestsPerSecond)
+	SetRequestsPerSecond(0)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	cancelled := make(chan struct{}, 1)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// The server never responds, the request ends once the context is cancelled.
+		cancel()
+		<-r.Context().Done()
+		cancelled <- struct{}{}
+	}))
+	defer server.Close()
+	serverId := RegisterPlatform(server.URL, "some-token")
+
+	_, _, err := SendGet(ctx, serverId, "api/system/version", "", server.URL+"/artifactory/", nil)
+	require.Error(t, err)
+
+	select {
+	case <-cancelled:
+	case <-time.After(5 * time.Second):
+		require.Fail(t, "the request was not cancelled along with its context")
+	}
+}
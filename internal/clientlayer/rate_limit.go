@@ -0,0 +1,162 @@
+package clientlayer
+
+import (
+	"context"
+	"net/url"
+	"sync"
+	"time"
+)
+
+const (
+	DefaultRequestsPerSecond = 10
+	// The query parameter changing between the requests of the same log stream.
+	pageMarkerParam = "file_size"
+)
+
+var (
+	limitersLock      sync.Mutex
+	requestsPerSecond float64 = DefaultRequestsPerSecond
+	limiters                  = map[string]*requestLimiter{}
+)
+
+// Sets the maximum number of requests per second sent to each server id during the session, zero disables the limit.
+func SetRequestsPerSecond(rate float64) {
+	limitersLock.Lock()
+	defer limitersLock.Unlock()
+	requestsPerSecond = rate
+	limiters = map[string]*requestLimiter{}
+}
+
+func GetRequestsPerSecond() float64 {
+	limitersLock.Lock()
+	defer limitersLock.Unlock()
+	return requestsPerSecond
+}
+
+// Returns the limiter shared by all the requests sent to the server id, nil when the requests are not limited.
+func getLimiter(cliServerId string) *requestLimiter {
+	limitersLock.Lock()
+	defer limitersLock.Unlock()
+	if requestsPerSecond <= 0 {
+		return nil
+	}
+	limiter, ok := limiters[cliServerId]
+	if !ok {
+		limiter = newRequestLimiter(time.Duration(float64(time.Second) / requestsPerSecond))
+		limiters[cliServerId] = limiter
+	}
+	return limiter
+}
+
+// Identifies the stream a request belongs to, the successive requests of a log stream only differ by their page marker.
+func streamKey(endpoint, nodeId, baseUrl string) string {
+	endpointUrl, err := url.Parse(endpoint)
+	if err != nil {
+		return nodeId + " " + baseUrl + endpoint
+	}
+	query := endpointUrl.Query()
+	query.Del(pageMarkerParam)
+	return nodeId + " " + baseUrl + endpointUrl.Path + "?" + query.Encode()
+}
+
+// Spaces the requests sent to a server by a fixed interval. When several streams are waiting, the requests are
+// granted to the streams in turn, so a stream sending many requests cannot starve the others.
+type requestLimiter struct {
+	interval time.Duration
+
+	lock sync.Mutex
+	// The earliest time of the next grant.
+	next time.Time
+	// The waiting requests of each stream, in their arrival order.
+	waiters map[string][]chan struct{}
+	// The streams having waiting requests, the first one is granted next.
+	turns       []string
+	dispatching bool
+}
+
+func newRequestLimiter(interval time.Duration) *requestLimiter {
+	return &requestLimiter{
+		interval: interval,
+		waiters:  map[string][]chan struct{}{},
+	}
+}
+
+// Blocks until the request of the stream is granted, or the context is done.
+func (l *requestLimiter) wait(ctx context.Context, stream string) error {
+	granted := make(chan struct{})
+	l.lock.Lock()
+	if len(l.waiters[stream]) == 0 {
+		l.turns = append(l.turns, stream)
+	}
+	l.waiters[stream] = append(l.waiters[stream], granted)
+	if !l.dispatching {
+		l.dispatching = true
+		go l.dispatch()
+	}
+	l.lock.Unlock()
+
+	select {
+	case <-granted:
+		return nil
+	case <-ctx.Done():
+		l.cancel(stream, granted)
+		return ctx.Err()
+	}
+}
+
+func (l *requestLimiter) dispatch() {
+	for {
+		l.lock.Lock()
+		if len(l.turns) == 0 {
+			l.dispatching = false
+			l.lock.Unlock()
+			return
+		}
+		delay := time.Until(l.next)
+		l.lock.Unlock()
+		if delay > 0 {
+			time.Sleep(delay)
+		}
+
+		l.lock.Lock()
+		if len(l.turns) > 0 {
+			stream := l.turns[0]
+			l.turns = l.turns[1:]
+			streamWaiters := l.waiters[stream]
+			close(streamWaiters[0])
+			if len(streamWaiters) > 1 {
+				l.waiters[stream] = streamWaiters[1:]
+				l.turns = append(l.turns, stream)
+			} else {
+				delete(l.waiters, stream)
+			}
+			l.next = time.Now().Add(l.interval)
+		}
+		l.lock.Unlock()
+	}
+}
+
+// Removes a request which is no longer waiting, a request granted in the meantime is simply ignored.
+func (l *requestLimiter) cancel(stream string, granted chan struct{}) {
+	l.lock.Lock()
+	defer l.lock.Unlock()
+	streamWaiters := l.waiters[stream]
+	for i, waiter := range streamWaiters {
+		if waiter != granted {
+			continue
+		}
+		streamWaiters = append(streamWaiters[:i:i], streamWaiters[i+1:]...)
+		if len(streamWaiters) > 0 {
+			l.waiters[stream] = streamWaiters
+			return
+		}
+		delete(l.waiters, stream)
+		for j, turn := range l.turns {
+			if turn == stream {
+				l.turns = append(l.turns[:j:j], l.turns[j+1:]...)
+				break
+			}
+		}
+		return
+	}
+}
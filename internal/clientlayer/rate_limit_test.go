@@ -0,0 +1,95 @@
+package clientlayer
+
+import (
+	"context"
+	"github.com/stretchr/testify/require"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestStreamKey(t *testing.T) {
+	tests := []struct {
+		name      string
+		endpoint1 string
+		endpoint2 string
+		sameKey   bool
+	}{
+		{"same log, other page marker", "api/system/logs/data?file_size=0&id=log1", "api/system/logs/data?file_size=120&id=log1", true},
+		{"other log", "api/system/logs/data?file_size=0&id=log1", "api/system/logs/data?file_size=0&id=log2", false},
+		{"other endpoint", "api/system/logs/config", "api/system/version", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			key1 := streamKey(tt.endpoint1, "node1", "http://server/")
+			key2 := streamKey(tt.endpoint2, "node1", "http://server/")
+			require.Equal(t, tt.sameKey, key1 == key2)
+		})
+	}
+	require.NotEqual(t, streamKey("api/system/version", "node1", "http://server/"), streamKey("api/system/version", "node2", "http://server/"))
+}
+
+func TestRequestLimiter_Interval(t *testing.T) {
+	limiter := newRequestLimiter(20 * time.Millisecond)
+	start := time.Now()
+	for i := 0; i < 4; i++ {
+		require.NoError(t, limiter.wait(context.Background(), "stream"))
+	}
+	require.GreaterOrEqual(t, int64(time.Since(start)), int64(60*time.Millisecond))
+}
+
+func TestRequestLimiter_Fairness(t *testing.T) {
+	limiter := newRequestLimiter(5 * time.Millisecond)
+	// Holds the dispatching until all the requests are queued.
+	require.NoError(t, limiter.wait(context.Background(), "warmup"))
+
+	var lock sync.Mutex
+	var granted []string
+	var wg sync.WaitGroup
+	request := func(stream string) {
+		defer wg.Done()
+		require.NoError(t, limiter.wait(context.Background(), stream))
+		lock.Lock()
+		granted = append(granted, stream)
+		lock.Unlock()
+	}
+	for i := 0; i < 3; i++ {
+		wg.Add(1)
+		go request("busy")
+	}
+	// Lets the busy stream queue its requests first.
+	time.Sleep(time.Millisecond)
+	wg.Add(1)
+	go request("quiet")
+	wg.Wait()
+
+	require.Len(t, granted, 4)
+	require.Contains(t, granted[:2], "quiet")
+}
+
+func TestRequestLimiter_Cancel(t *testing.T) {
+	limiter := newRequestLimiter(time.Hour)
+	require.NoError(t, limiter.wait(context.Background(), "stream"))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	require.Error(t, limiter.wait(ctx, "stream"))
+
+	limiter.lock.Lock()
+	defer limiter.lock.Unlock()
+	require.Empty(t, limiter.waiters)
+	require.Empty(t, limiter.turns)
+}
+
+func TestGetLimiter(t *testing.T) {
+	defer SetRequestsPerSecond(DefaultRequestsPerSecond)
+
+	SetRequestsPerSecond(0)
+	require.Nil(t, getLimiter("server1"))
+
+	SetRequestsPerSecond(4)
+	limiter := getLimiter("server1")
+	require.Equal(t, 250*time.Millisecond, limiter.interval)
+	require.Same(t, limiter, getLimiter("server1"))
+	require.NotSame(t, limiter, getLimiter("server2"))
+}
@@ -0,0 +1,88 @@
+package clientlayer
+
+import (
+	cliCommands "github.com/jfrog/jfrog-cli-core/v2/common/commands"
+	"github.com/jfrog/jfrog-cli-core/v2/utils/config"
+	"sort"
+	"strings"
+	"sync"
+)
+
+var (
+	serversLock sync.Mutex
+	// The servers registered with an explicit url and access token, by server id.
+	servers = map[string]*config.ServerDetails{}
+)
+
+// Registers a JFrog platform by its url and access token, the product urls are derived from the platform url.
+// Returns the server id identifying the platform in the following requests.
+func RegisterPlatform(platformUrl, accessToken string) string {
+	if !strings.HasSuffix(platformUrl, "/") {
+		platformUrl += "/"
+	}
+	serversLock.Lock()
+	defer serversLock.Unlock()
+	servers[platformUrl] = withProductUrls(&config.ServerDetails{
+		Url:         platformUrl,
+		AccessToken: accessToken,
+		ServerId:    platformUrl,
+	})
+	return platformUrl
+}
+
+// Returns whether the server id identifies a platform registered by its url, rather than a JFrog CLI server id.
+func IsRegisteredPlatform(serverId string) bool {
+	serversLock.Lock()
+	defer serversLock.Unlock()
+	_, ok := servers[serverId]
+	return ok
+}
+
+// Returns the ids of the registered platforms, followed by the server ids of the JFrog CLI config.
+func GetAllServerIds() []string {
+	serversLock.Lock()
+	var serverIds []string
+	for serverId := range servers {
+		serverIds = append(serverIds, serverId)
+	}
+	serversLock.Unlock()
+	sort.Strings(serverIds)
+	return append(serverIds, cliCommands.GetAllServerIds()...)
+}
+
+// Returns the details of a registered platform, or of a server id of the JFrog CLI config. The product urls missing
+// from the JFrog CLI config are derived from the platform url, so a single server id covers all the products.
+func GetServerDetails(serverId string, excludeRefreshableTokens bool) (*config.ServerDetails, error) {
+	serversLock.Lock()
+	serverDetails, ok := servers[serverId]
+	serversLock.Unlock()
+	if ok {
+		return serverDetails, nil
+	}
+	serverDetails, err := cliCommands.GetConfig(serverId, excludeRefreshableTokens)
+	if err != nil {
+		return nil, err
+	}
+	return withProductUrls(serverDetails), nil
+}
+
+func withProductUrls(serverDetails *config.ServerDetails) *config.ServerDetails {
+	platformUrl := serverDetails.Url
+	if platformUrl == "" {
+		return serverDetails
+	}
+	if !strings.HasSuffix(platformUrl, "/") {
+		platformUrl += "/"
+	}
+	setDefault := func(productUrl *string, productPath string) {
+		if *productUrl == "" {
+			*productUrl = platformUrl + productPath
+		}
+	}
+	setDefault(&serverDetails.ArtifactoryUrl, "artifactory/")
+	setDefault(&serverDetails.XrayUrl, "xray/")
+	setDefault(&serverDetails.MissionControlUrl, "mc/")
+	setDefault(&serverDetails.PipelinesUrl, "pipelines/")
+	setDefault(&serverDetails.DistributionUrl, "distribution/")
+	return serverDetails
+}
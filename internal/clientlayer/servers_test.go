@@ -0,0 +1,35 @@
+package clientlayer
+
+import (
+	"github.com/jfrog/jfrog-cli-core/v2/utils/config"
+	"github.com/stretchr/testify/require"
+	"testing"
+)
+
+func TestRegisterPlatform(t *testing.T) {
+	serverId := RegisterPlatform("https://acme.jfrog.io", "some-token")
+	require.Equal(t, "https://acme.jfrog.io/", serverId)
+
+	serverDetails, err := GetServerDetails(serverId, false)
+	require.NoError(t, err)
+	require.Equal(t, "https://acme.jfrog.io/artifactory/", serverDetails.ArtifactoryUrl)
+	require.Equal(t, "https://acme.jfrog.io/xray/", serverDetails.XrayUrl)
+	require.Equal(t, "https://acme.jfrog.io/mc/", serverDetails.MissionControlUrl)
+	require.Equal(t, "https://acme.jfrog.io/pipelines/", serverDetails.PipelinesUrl)
+	require.Equal(t, "https://acme.jfrog.io/distribution/", serverDetails.DistributionUrl)
+	require.Equal(t, "some-token", serverDetails.AccessToken)
+	require.True(t, IsRegisteredPlatform(serverId))
+	require.Contains(t, GetAllServerIds(), serverId)
+}
+
+func TestWithProductUrls(t *testing.T) {
+	serverDetails := withProductUrls(&config.ServerDetails{
+		Url:            "https://acme.jfrog.io",
+		ArtifactoryUrl: "https://rt.acme.io/artifactory/",
+	})
+	require.Equal(t, "https://rt.acme.io/artifactory/", serverDetails.ArtifactoryUrl)
+	require.Equal(t, "https://acme.jfrog.io/xray/", serverDetails.XrayUrl)
+
+	serverDetails = withProductUrls(&config.ServerDetails{ArtifactoryUrl: "https://rt.acme.io/artifactory/"})
+	require.Empty(t, serverDetails.XrayUrl)
+}
@@ -0,0 +1,189 @@
+// Package color colors the log lines written to a terminal, by log level for the service logs, by status class for
+// the request logs, with a distinct color for the prefix of each source, and highlights the user patterns.
+package color
+
+import (
+	"fmt"
+	"github.com/jfrog/live-logs/internal/stream"
+	"golang.org/x/term"
+	"hash/fnv"
+	"os"
+	"regexp"
+	"strings"
+)
+
+const (
+	AlwaysMode = "always"
+	NeverMode  = "never"
+	AutoMode   = "auto"
+	// Disables the colors in auto mode when set to any value, see https://no-color.org.
+	NoColorEnv = "NO_COLOR"
+)
+
+const (
+	reset     = "\x1b[0m"
+	red       = "\x1b[31m"
+	boldRed   = "\x1b[1;31m"
+	green     = "\x1b[32m"
+	yellow    = "\x1b[33m"
+	cyan      = "\x1b[36m"
+	faint     = "\x1b[2m"
+	highlight = "\x1b[1;7m"
+)
+
+// The colors of the source prefixes, a source always gets the same color.
+var sourceColors = []string{"\x1b[36m", "\x1b[35m", "\x1b[34m", "\x1b[32m", "\x1b[33m", "\x1b[96m", "\x1b[95m", "\x1b[94m"}
+
+var (
+	levelPattern = regexp.MustCompile(`\b(TRACE|DEBUG|INFO|WARN|WARNING|ERROR|FATAL)\b`)
+	levelColors  = map[string]string{
+		"TRACE":   faint,
+		"DEBUG":   faint,
+		"INFO":    green,
+		"WARN":    yellow,
+		"WARNING": yellow,
+		"ERROR":   red,
+		"FATAL":   boldRed,
+	}
+	statusPattern = regexp.MustCompile(`^[1-5][0-9][0-9]$`)
+	statusColors  = map[byte]string{'1': faint, '2': green, '3': cyan, '4': yellow, '5': red}
+	httpMethods   = map[string]bool{"GET": true, "HEAD": true, "POST": true, "PUT": true, "PATCH": true, "DELETE": true, "OPTIONS": true}
+)
+
+// Returns whether the output is colored in the given mode. In auto mode, the output is colored when it is a
+// terminal, unless the NO_COLOR environment variable is set to a non empty value or the terminal is dumb.
+func Enabled(mode string, output *os.File) (bool, error) {
+	switch mode {
+	case AlwaysMode:
+		return true, nil
+	case NeverMode:
+		return false, nil
+	case AutoMode, "":
+		if disabledByEnv() {
+			return false, nil
+		}
+		return term.IsTerminal(int(output.Fd())), nil
+	default:
+		return false, fmt.Errorf("invalid color mode [%s], expected one of %s, %s or %s", mode, AlwaysMode, NeverMode, AutoMode)
+	}
+}
+
+// Returns whether the environment disables the colors, an empty NO_COLOR does not, see https://no-color.org.
+func disabledByEnv() bool {
+	return os.Getenv(NoColorEnv) != "" || os.Getenv("TERM") == "dumb"
+}
+
+// A pipeline stage coloring the lines, it must follow the other stages as it adds the terminal escape sequences.
+type Colorizer struct {
+	highlights []*regexp.Regexp
+	labeler    stream.Labeler
+}
+
+// Creates a colorizer highlighting the matches of the given patterns.
+func NewColorizer(highlightPatterns []string) (*Colorizer, error) {
+	colorizer := &Colorizer{}
+	for _, pattern := range highlightPatterns {
+		highlightRegexp, err := regexp.Compile(pattern)
+		if err != nil {
+			return nil, fmt.Errorf("invalid highlight pattern [%s]: %w", pattern, err)
+		}
+		colorizer.highlights = append(colorizer.highlights, highlightRegexp)
+	}
+	return colorizer, nil
+}
+
+// Returns a copy of the colorizer for lines prefixed by the labeler, the prefix gets the color of its source.
+func (c *Colorizer) WithLabeler(labeler stream.Labeler) *Colorizer {
+	return &Colorizer{highlights: c.highlights, labeler: labeler}
+}
+
+func (c *Colorizer) Process(line *stream.Line) bool {
+	text := line.Text
+	label := c.labeler.Label(line.Source)
+	if label == "" || !strings.HasPrefix(text, label) {
+		label = ""
+	}
+	text = text[len(label):]
+
+	styles := make([]string, len(text))
+	if isRequestLog(line.Source.LogName) {
+		colorStatus(text, styles)
+	} else {
+		colorLevel(text, styles)
+	}
+	for _, highlightRegexp := range c.highlights {
+		for _, match := range highlightRegexp.FindAllStringIndex(text, -1) {
+			fill(styles, match[0], match[1], highlight)
+		}
+	}
+
+	var colored strings.Builder
+	if label != "" {
+		trimmedLabel := strings.TrimSuffix(label, " ")
+		colored.WriteString(sourceColor(trimmedLabel) + trimmedLabel + reset + label[len(trimmedLabel):])
+	}
+	colored.WriteString(render(text, styles))
+	line.Text = colored.String()
+	return true
+}
+
+// The request logs, such as artifactory-request.log, hold a line per HTTP request.
+func isRequestLog(logName string) bool {
+	return strings.Contains(logName, "request")
+}
+
+// Colors the log level, the first level word of the line.
+func colorLevel(text string, styles []string) {
+	match := levelPattern.FindStringIndex(text)
+	if match == nil {
+		return
+	}
+	fill(styles, match[0], match[1], levelColors[text[match[0]:match[1]]])
+}
+
+// Colors the status code of a request log line, the first status code following the HTTP method in the '|'
+// separated fields.
+func colorStatus(text string, styles []string) {
+	start := 0
+	foundMethod := false
+	for _, field := range strings.Split(text, "|") {
+		if foundMethod && statusPattern.MatchString(field) {
+			fill(styles, start, start+len(field), statusColors[field[0]])
+			return
+		}
+		foundMethod = foundMethod || httpMethods[field]
+		start += len(field) + 1
+	}
+}
+
+func fill(styles []string, start, end int, style string) {
+	for i := start; i < end; i++ {
+		styles[i] = style
+	}
+}
+
+func sourceColor(label string) string {
+	hash := fnv.New32a()
+	_, _ = hash.Write([]byte(label))
+	return sourceColors[hash.Sum32()%uint32(len(sourceColors))]
+}
+
+// Writes the text with the escape sequence of its style before each styled part, and a reset after it.
+func render(text string, styles []string) string {
+	var rendered strings.Builder
+	current := ""
+	for i := 0; i < len(text); i++ {
+		if styles[i] != current {
+			if current != "" {
+				rendered.WriteString(reset)
+			}
+			rendered.WriteString(styles[i])
+			current = styles[i]
+		}
+		rendered.WriteByte(text[i])
+	}
+	if current != "" {
+		rendered.WriteString(reset)
+	}
+	return rendered.String()
+}
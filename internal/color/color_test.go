@@ -0,0 +1,130 @@
+package color
+
+import (
+	"github.com/jfrog/live-logs/internal/stream"
+	"github.com/stretchr/testify/require"
+	"os"
+	"testing"
+)
+
+func TestColorizer(t *testing.T) {
+	serviceLog := stream.Source{NodeId: "node1", LogName: "artifactory-service.log"}
+	requestLog := stream.Source{NodeId: "node1", LogName: "artifactory-request.log"}
+	tests := []struct {
+		name       string
+		source     stream.Source
+		text       string
+		highlights []string
+		want       string
+	}{
+		{
+			name:   "error level",
+			source: serviceLog,
+			text:   "2021-03-25T04:00:00.006Z [jfrt ] [ERROR] [d76675e362ffbd6a] - GC failed with INFO",
+			want:   "2021-03-25T04:00:00.006Z [jfrt ] [" + red + "ERROR" + reset + "] [d76675e362ffbd6a] - GC failed with INFO",
+		},
+		{
+			name:   "no level",
+			source: serviceLog,
+			text:   "    at org.artifactory.Main",
+			want:   "    at org.artifactory.Main",
+		},
+		{
+			name:   "client error status",
+			source: requestLog,
+			text:   "2021-03-25T04:30:34.199Z|94109ae150da76e|127.0.0.1|admin|GET|/api/404|404|-1|0|3",
+			want:   "2021-03-25T04:30:34.199Z|94109ae150da76e|127.0.0.1|admin|GET|/api/404|" + yellow + "404" + reset + "|-1|0|3",
+		},
+		{
+			name:   "request without method",
+			source: requestLog,
+			text:   "200|200",
+			want:   "200|200",
+		},
+		{
+			name:       "highlight over level",
+			source:     serviceLog,
+			text:       "[WARN ] slow query on repo-1",
+			highlights: []string{`WA`, `repo-\d`},
+			want:       "[" + highlight + "WA" + reset + yellow + "RN" + reset + " ] slow query on " + highlight + "repo-1" + reset,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			colorizer, err := NewColorizer(tt.highlights)
+			require.NoError(t, err)
+			line := stream.Line{Source: tt.source, Text: tt.text}
+			require.True(t, colorizer.Process(&line))
+			require.Equal(t, tt.want, line.Text)
+		})
+	}
+}
+
+func TestColorizer_Label(t *testing.T) {
+	labeler := stream.Labeler{Node: true}
+	colorizer, err := NewColorizer(nil)
+	require.NoError(t, err)
+	colorizer = colorizer.WithLabeler(labeler)
+
+	line := stream.Line{Source: stream.Source{NodeId: "node1", LogName: "one.log"}, Text: "text"}
+	labeler.Process(&line)
+	colorizer.Process(&line)
+	require.Equal(t, sourceColor("[node1]")+"[node1]"+reset+" text", line.Text)
+}
+
+func TestNewColorizer_InvalidPattern(t *testing.T) {
+	_, err := NewColorizer([]string{"("})
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "invalid highlight pattern [(]")
+}
+
+func TestEnabled(t *testing.T) {
+	output, err := os.Create(t.TempDir() + "/output")
+	require.NoError(t, err)
+	defer output.Close()
+
+	enabled, err := Enabled(AlwaysMode, output)
+	require.NoError(t, err)
+	require.True(t, enabled)
+
+	enabled, err = Enabled(NeverMode, output)
+	require.NoError(t, err)
+	require.False(t, enabled)
+
+	// A file is not a terminal.
+	enabled, err = Enabled(AutoMode, output)
+	require.NoError(t, err)
+	require.False(t, enabled)
+
+	_, err = Enabled("sometimes", output)
+	require.EqualError(t, err, "invalid color mode [sometimes], expected one of always, never or auto")
+}
+
+func TestDisabledByEnv(t *testing.T) {
+	for _, name := range []string{NoColorEnv, "TERM"} {
+		value, isSet := os.LookupEnv(name)
+		if isSet {
+			defer os.Setenv(name, value)
+		} else {
+			defer os.Unsetenv(name)
+		}
+	}
+	tests := []struct {
+		name     string
+		noColor  string
+		term     string
+		disabled bool
+	}{
+		{name: "not set", term: "xterm"},
+		{name: "empty NO_COLOR", noColor: "", term: "xterm"},
+		{name: "NO_COLOR set", noColor: "1", term: "xterm", disabled: true},
+		{name: "dumb terminal", term: "dumb", disabled: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			require.NoError(t, os.Setenv(NoColorEnv, tt.noColor))
+			require.NoError(t, os.Setenv("TERM", tt.term))
+			require.Equal(t, tt.disabled, disabledByEnv())
+		})
+	}
+}
@@ -0,0 +1,72 @@
+package livelog
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"github.com/jfrog/live-logs/internal/constants"
+	"github.com/jfrog/live-logs/internal/model"
+	"github.com/jfrog/live-logs/internal/util"
+	"gopkg.in/yaml.v3"
+	"io"
+	"strings"
+	"text/tabwriter"
+)
+
+var configTableHeader = []string{"SERVER", "NODE", "PRODUCT", "VERSION", "LOGS"}
+
+func ConfigFormats() []string {
+	return []string{constants.JsonFormat, constants.YamlFormat, constants.TableFormat, constants.CsvFormat}
+}
+
+// Writes the config display data of one or more servers in the given format, the table and csv formats have a row
+// for each node. The json and yaml formats have a single object for a single server, and a list for several servers.
+func writeConfig(output io.Writer, format string, displayData ...model.ConfigDisplayData) error {
+	err := util.ValidateArgument("format", format, ConfigFormats())
+	if err != nil {
+		return err
+	}
+	var document interface{} = displayData
+	if len(displayData) == 1 {
+		document = displayData[0]
+	}
+	switch format {
+	case constants.YamlFormat:
+		data, err := yaml.Marshal(document)
+		if err != nil {
+			return err
+		}
+		_, err = output.Write(data)
+		return err
+	case constants.TableFormat:
+		tableWriter := tabwriter.NewWriter(output, 0, 0, 2, ' ', 0)
+		for _, row := range configRows(displayData) {
+			_, err = fmt.Fprintln(tableWriter, strings.Join(row, "\t"))
+			if err != nil {
+				return err
+			}
+		}
+		return tableWriter.Flush()
+	case constants.CsvFormat:
+		csvWriter := csv.NewWriter(output)
+		return csvWriter.WriteAll(configRows(displayData))
+	default:
+		data, err := json.MarshalIndent(document, "", "  ")
+		if err != nil {
+			return err
+		}
+		_, err = fmt.Fprintln(output, string(data))
+		return err
+	}
+}
+
+func configRows(displayData []model.ConfigDisplayData) [][]string {
+	rows := [][]string{configTableHeader}
+	for _, serverData := range displayData {
+		logs := strings.Join(serverData.Logs, constants.ValuesSeparator)
+		for _, nodeId := range serverData.Nodes {
+			rows = append(rows, []string{serverData.ServerId, nodeId, serverData.ProductId, serverData.ProductVersion, logs})
+		}
+	}
+	return rows
+}
@@ -0,0 +1,109 @@
+package livelog
+
+import (
+	"bytes"
+	"encoding/json"
+	"github.com/jfrog/live-logs/internal/constants"
+	"github.com/jfrog/live-logs/internal/model"
+	"github.com/stretchr/testify/require"
+	"testing"
+)
+
+func Test_writeConfig(t *testing.T) {
+	displayData := model.ConfigDisplayData{
+		ServerId:          "eu-rt",
+		ProductId:         constants.ArtifactoryId,
+		ProductVersion:    "7.41.0",
+		RefreshRateMillis: 1000,
+		Logs:              []string{"log1", "log2"},
+		Nodes:             []string{"node1", "node2"},
+	}
+	tests := []struct {
+		name    string
+		format  string
+		want    string
+		wantErr bool
+	}{
+		{
+			name:   "json",
+			format: constants.JsonFormat,
+			want: `{
+  "server_id": "eu-rt",
+  "product_id": "rt",
+  "product_version": "7.41.0",
+  "refresh_rate_millis": 1000,
+  "logs": [
+    "log1",
+    "log2"
+  ],
+  "nodes": [
+    "node1",
+    "node2"
+  ]
+}
+`,
+		},
+		{
+			name:   "yaml",
+			format: constants.YamlFormat,
+			want: `server_id: eu-rt
+product_id: rt
+product_version: 7.41.0
+refresh_rate_millis: 1000
+logs:
+    - log1
+    - log2
+nodes:
+    - node1
+    - node2
+`,
+		},
+		{
+			name:   "table",
+			format: constants.TableFormat,
+			want: "SERVER  NODE   PRODUCT  VERSION  LOGS\n" +
+				"eu-rt   node1  rt       7.41.0   log1,log2\n" +
+				"eu-rt   node2  rt       7.41.0   log1,log2\n",
+		},
+		{
+			name:   "csv",
+			format: constants.CsvFormat,
+			want: "SERVER,NODE,PRODUCT,VERSION,LOGS\n" +
+				"eu-rt,node1,rt,7.41.0,\"log1,log2\"\n" +
+				"eu-rt,node2,rt,7.41.0,\"log1,log2\"\n",
+		},
+		{
+			name:    "unsupported format",
+			format:  "xml",
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			out := &bytes.Buffer{}
+			err := writeConfig(out, tt.format, displayData)
+			if tt.wantErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tt.want, out.String())
+		})
+	}
+}
+
+func Test_writeConfig_MultipleServers(t *testing.T) {
+	displayData := []model.ConfigDisplayData{
+		{ServerId: "eu-rt", ProductId: constants.ArtifactoryId, Nodes: []string{"node1"}, Logs: []string{"log1"}},
+		{ServerId: "us-rt", ProductId: constants.ArtifactoryId, Nodes: []string{"node1"}, Logs: []string{"log1"}},
+	}
+	out := &bytes.Buffer{}
+	require.NoError(t, writeConfig(out, constants.CsvFormat, displayData...))
+	require.Equal(t, "SERVER,NODE,PRODUCT,VERSION,LOGS\neu-rt,node1,rt,,log1\nus-rt,node1,rt,,log1\n", out.String())
+
+	out.Reset()
+	require.NoError(t, writeConfig(out, constants.JsonFormat, displayData...))
+	var actual []model.ConfigDisplayData
+	require.NoError(t, json.Unmarshal(out.Bytes(), &actual))
+	require.Equal(t, displayData, actual)
+}
@@ -12,4 +12,53 @@ const (
 	NonIntCmdDisplayPrefix = "You can also use the following non-interactive equivalent command,"
 	TailFlag = "f"
 	InteractiveFlag = "i"
+	AllValues = "all"
+	ValuesSeparator = ","
+	OutputFlag = "output"
+	ConcurrencyFlag = "concurrency"
+	RedactFlag = "redact"
+	RedactRulesFlag = "redact-rules"
+	PseudonymizeFlag = "pseudonymize"
+	RedactSaltEnv = "JFROG_CLI_LIVE_LOG_REDACT_SALT"
+	FormatFlag = "format"
+	JsonFormat = "json"
+	YamlFormat = "yaml"
+	TableFormat = "table"
+	CsvFormat = "csv"
+	ProductsFlag = "products"
+	RefreshFlag = "refresh"
+	RateLimitFlag = "rate-limit"
+	UrlFlag = "url"
+	AccessTokenFlag = "access-token"
+	RecordFlag = "record"
+	SpeedFlag = "speed"
+	ColorFlag = "color"
+	HighlightFlag = "highlight"
+	HighlightSeparator = ";"
+	SinceFlag = "since"
+	UntilFlag = "until"
+	LogTimezoneFlag = "log-timezone"
+	SinkFlag = "sink"
+	SinkUrlFlag = "sink-url"
+	SinkTokenFlag = "sink-token"
+	SinkIndexFlag = "sink-index"
+	SinkHeadersFlag = "sink-headers"
+	BatchSizeFlag = "batch-size"
+	FlushIntervalFlag = "flush-interval"
+	CheckpointFlag = "checkpoint"
+	SyslogFlag = "syslog"
+	SyslogModeFlag = "syslog-mode"
+	SyslogAppNameFlag = "syslog-app-name"
+	SyslogFacilityFlag = "syslog-facility"
+	SyslogCaFlag = "syslog-ca"
+	ListenFlag = "listen"
+	HistoryFlag = "history"
+	DedupFlag = "dedup"
+	DedupWindowFlag = "dedup-window"
+	DedupRulesFlag = "dedup-rules"
+	SinkTokenEnv = "JFROG_CLI_LIVE_LOG_SINK_TOKEN"
+	SinkHeadersEnv = "JFROG_CLI_LIVE_LOG_SINK_HEADERS"
+	DefaultCheckpointFile = "live-logs-checkpoints.json"
+	UrlEnv = "JFROG_CLI_LIVE_LOG_URL"
+	AccessTokenEnv = "JFROG_CLI_LIVE_LOG_ACCESS_TOKEN"
 )
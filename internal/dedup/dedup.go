@@ -0,0 +1,266 @@
+// Package dedup collapses the repeated log lines, such as a warning logged hundreds of times a second, into their
+// first occurrence followed by a single line with the number of repeats.
+package dedup
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/jfrog/live-logs/internal/stream"
+	"github.com/jfrog/live-logs/internal/timerange"
+	"io/ioutil"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Appended to the last repeat of a line, with the number of repeats.
+const repeatsSuffix = " [repeated %d times]"
+
+// The time the lines are read, overridden by the tests.
+var now = time.Now
+
+// A normalization rule, the matches of the pattern are ignored when comparing the lines.
+type Rule struct {
+	Name    string
+	Pattern *regexp.Regexp
+	// When set, only the matches it accepts are ignored.
+	Accept func(match string) bool
+}
+
+// The format of a user defined rule, as read from a rules file.
+type ruleDefinition struct {
+	Name    string `json:"name"`
+	Pattern string `json:"pattern"`
+}
+
+// Returns the rules ignoring the timestamps and the ids, which differ between the repeats of the same line.
+func BuiltInRules() []Rule {
+	return []Rule{
+		{Name: "timestamp", Pattern: regexp.MustCompile(`\d{4}-\d{2}-\d{2}[T ]\d{2}:\d{2}:\d{2}(?:[.,]\d+)?(?:Z|[+-]\d{2}:?\d{2})?`)},
+		{Name: "uuid", Pattern: regexp.MustCompile(`(?i)\b[0-9a-f]{8}-[0-9a-f]{4}-[0-9a-f]{4}-[0-9a-f]{4}-[0-9a-f]{12}\b`)},
+		// The trace ids, which may have a leading zero trimmed, and the other long hexadecimal ids such as checksums.
+		// The decimal numbers, such as the sizes or the epoch times in milliseconds, are not ids.
+		{Name: "hex-id", Pattern: regexp.MustCompile(`(?i)\b[0-9a-f]{12,}\b`), Accept: hasHexLetter},
+	}
+}
+
+func hasHexLetter(match string) bool {
+	return strings.ContainsAny(match, "abcdefABCDEF")
+}
+
+// Reads user defined rules from a JSON file, containing a list of objects with a name and a pattern.
+func LoadRules(rulesFilePath string) ([]Rule, error) {
+	content, err := ioutil.ReadFile(rulesFilePath)
+	if err != nil {
+		return nil, err
+	}
+	var definitions []ruleDefinition
+	err = json.Unmarshal(content, &definitions)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse the deduplication rules file %s: %w", rulesFilePath, err)
+	}
+
+	rules := make([]Rule, 0, len(definitions))
+	for _, definition := range definitions {
+		if definition.Name == "" {
+			return nil, fmt.Errorf("a deduplication rule without a name was found in %s", rulesFilePath)
+		}
+		pattern, err := regexp.Compile(definition.Pattern)
+		if err != nil {
+			return nil, fmt.Errorf("invalid pattern for deduplication rule '%s': %w", definition.Name, err)
+		}
+		rules = append(rules, Rule{Name: definition.Name, Pattern: pattern})
+	}
+	return rules, nil
+}
+
+// Selects the repeated lines which are collapsed.
+type Config struct {
+	// The repeats of a line within this time of its first occurrence are collapsed, even when other lines come in
+	// between. Only the consecutive repeats are collapsed when zero.
+	Window time.Duration
+	// The lines which are identical once the matches of the rules are ignored are repeats of each other.
+	Rules []Rule
+}
+
+// Creates a pipeline stage collapsing the repeated lines.
+func (c Config) NewDeduplicator() *Deduplicator {
+	return &Deduplicator{config: c, sources: map[stream.Source]*sourceLines{}}
+}
+
+// Collapses the repeated lines of each source. The first occurrence of a line is passed on and its repeats are held
+// back, the last repeat is released with the number of repeats once the window of the line ends, or once a different
+// line follows when only the consecutive repeats are collapsed. The time of a line is its timestamp, or the time it
+// is read for the logs without timestamps. A line without a timestamp following a line with one, such as a stack
+// trace line, belongs to the line before it and is dropped along with it.
+type Deduplicator struct {
+	config Config
+
+	lock     sync.Mutex
+	sources  map[stream.Source]*sourceLines
+	order    []stream.Source
+	released []stream.Line
+}
+
+// The lines of a source within the window.
+type sourceLines struct {
+	// The time of the last line with a timestamp, zero until a timestamp is read.
+	lastTime time.Time
+	// The time the last line was read.
+	lastRead time.Time
+	// Whether the last line with a timestamp was dropped, along with the lines which belong to it.
+	dropping bool
+	repeats  map[string]*repeats
+	// The keys of the repeats in the order of their first occurrence, so that they are released in order.
+	keys []string
+}
+
+// A line and its held back repeats.
+type repeats struct {
+	firstTime time.Time
+	// The time the first occurrence was read.
+	firstRead time.Time
+	count     int
+	last      stream.Line
+}
+
+func (d *Deduplicator) Process(line *stream.Line) bool {
+	d.lock.Lock()
+	defer d.lock.Unlock()
+	lines := d.sources[line.Source]
+	if lines == nil {
+		lines = &sourceLines{repeats: map[string]*repeats{}}
+		d.sources[line.Source] = lines
+		d.order = append(d.order, line.Source)
+	}
+	readTime := now()
+	lines.lastRead = readTime
+	lineTime, found := timerange.ParseLineTime(line.Text, time.UTC)
+	if found {
+		lines.lastTime = lineTime
+	} else if !lines.lastTime.IsZero() {
+		return !lines.dropping
+	} else {
+		lineTime = readTime
+	}
+
+	key := d.key(line.Text)
+	d.releaseEnded(lines, key, lineTime)
+	if seen := lines.repeats[key]; seen != nil {
+		seen.count++
+		seen.last = *line
+		lines.dropping = true
+		return false
+	}
+	lines.repeats[key] = &repeats{firstTime: lineTime, firstRead: readTime}
+	lines.keys = append(lines.keys, key)
+	lines.dropping = false
+	return true
+}
+
+// Returns the line with the matches of the rules replaced by the rule names.
+func (d *Deduplicator) key(text string) string {
+	for _, rule := range d.config.Rules {
+		replacement := "<" + rule.Name + ">"
+		if rule.Accept == nil {
+			text = rule.Pattern.ReplaceAllLiteralString(text, replacement)
+			continue
+		}
+		accept := rule.Accept
+		text = rule.Pattern.ReplaceAllStringFunc(text, func(match string) string {
+			if !accept(match) {
+				return match
+			}
+			return replacement
+		})
+	}
+	return text
+}
+
+// Releases the repeats of the lines whose window ended before the line time, or of all the lines but the given one
+// when only the consecutive repeats are collapsed.
+func (d *Deduplicator) releaseEnded(lines *sourceLines, key string, lineTime time.Time) {
+	kept := lines.keys[:0]
+	for _, seenKey := range lines.keys {
+		seen := lines.repeats[seenKey]
+		ended := seenKey != key
+		if d.config.Window > 0 {
+			ended = lineTime.Sub(seen.firstTime) > d.config.Window
+		}
+		if !ended {
+			kept = append(kept, seenKey)
+			continue
+		}
+		d.release(seen)
+		delete(lines.repeats, seenKey)
+	}
+	lines.keys = kept
+}
+
+// Releases the last repeat of a line, with the number of repeats when there are several.
+func (d *Deduplicator) release(seen *repeats) {
+	if seen.count == 0 {
+		return
+	}
+	last := seen.last
+	if seen.count > 1 {
+		last.Text += fmt.Sprintf(repeatsSuffix, seen.count)
+		last.Partial = false
+	}
+	d.released = append(d.released, last)
+}
+
+func (d *Deduplicator) Released() []stream.Line {
+	d.lock.Lock()
+	defer d.lock.Unlock()
+	released := d.released
+	d.released = nil
+	return released
+}
+
+// Releases the repeats whose window ended while no line was read, or when only the consecutive repeats are collapsed,
+// the repeats of the sources from which no line was read for at least the timeout. So the number of repeats of a
+// burst of lines followed by silence is shown while tailing.
+func (d *Deduplicator) FlushStale(timeout time.Duration) []stream.Line {
+	d.lock.Lock()
+	defer d.lock.Unlock()
+	currentTime := now()
+	for _, source := range d.order {
+		lines := d.sources[source]
+		kept := lines.keys[:0]
+		for _, key := range lines.keys {
+			seen := lines.repeats[key]
+			ended := currentTime.Sub(lines.lastRead) >= timeout
+			if d.config.Window > 0 {
+				ended = currentTime.Sub(seen.firstRead) > d.config.Window
+			}
+			if !ended {
+				kept = append(kept, key)
+				continue
+			}
+			d.release(seen)
+			delete(lines.repeats, key)
+		}
+		lines.keys = kept
+	}
+	released := d.released
+	d.released = nil
+	return released
+}
+
+func (d *Deduplicator) Flush() []stream.Line {
+	d.lock.Lock()
+	defer d.lock.Unlock()
+	for _, source := range d.order {
+		lines := d.sources[source]
+		for _, key := range lines.keys {
+			d.release(lines.repeats[key])
+		}
+	}
+	d.sources = map[stream.Source]*sourceLines{}
+	d.order = nil
+	released := d.released
+	d.released = nil
+	return released
+}
@@ -0,0 +1,233 @@
+package dedup
+
+import (
+	"bytes"
+	"github.com/jfrog/live-logs/internal/stream"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"io/ioutil"
+	"path/filepath"
+	"regexp"
+	"testing"
+	"time"
+)
+
+var (
+	node1Source = stream.Source{ServerId: "local-rt", ProductId: "rt", NodeId: "node1", LogName: "service.log"}
+	node2Source = stream.Source{ServerId: "local-rt", ProductId: "rt", NodeId: "node2", LogName: "service.log"}
+)
+
+// Writes the lines through a pipeline collapsing the repeats, and returns the output once flushed.
+func collapse(t *testing.T, config Config, lines ...stream.Line) string {
+	out := &bytes.Buffer{}
+	pipeline := stream.NewPipeline(out, config.NewDeduplicator())
+	require.NoError(t, pipeline.Write(lines...))
+	require.NoError(t, pipeline.Flush())
+	return out.String()
+}
+
+func texts(source stream.Source, texts ...string) []stream.Line {
+	var lines []stream.Line
+	for _, text := range texts {
+		lines = append(lines, stream.Line{Source: source, Text: text})
+	}
+	return lines
+}
+
+func TestDeduplicator(t *testing.T) {
+	tests := []struct {
+		name   string
+		window time.Duration
+		lines  []stream.Line
+		want   string
+	}{
+		{
+			name: "consecutive repeats",
+			lines: texts(node1Source,
+				"2021-03-25T04:00:00.001Z [jfrt ] [WARN ] [d76675e362ffbd6a] [Cache:12] [exec-1] - cache full",
+				"2021-03-25T04:00:00.002Z [jfrt ] [WARN ] [a1b2c3d4e5f60718] [Cache:12] [exec-1] - cache full",
+				"2021-03-25T04:00:00.003Z [jfrt ] [WARN ] [0f1e2d3c4b5a6978] [Cache:12] [exec-1] - cache full",
+				"2021-03-25T04:00:01.000Z [jfrt ] [INFO ] [d76675e362ffbd6a] [Gc:66] [exec-2] - gc done",
+				"2021-03-25T04:00:02.000Z [jfrt ] [INFO ] [d76675e362ffbd6a] [Gc:66] [exec-2] - gc done",
+			),
+			want: "2021-03-25T04:00:00.001Z [jfrt ] [WARN ] [d76675e362ffbd6a] [Cache:12] [exec-1] - cache full\n" +
+				"2021-03-25T04:00:00.003Z [jfrt ] [WARN ] [0f1e2d3c4b5a6978] [Cache:12] [exec-1] - cache full [repeated 2 times]\n" +
+				"2021-03-25T04:00:01.000Z [jfrt ] [INFO ] [d76675e362ffbd6a] [Gc:66] [exec-2] - gc done\n" +
+				"2021-03-25T04:00:02.000Z [jfrt ] [INFO ] [d76675e362ffbd6a] [Gc:66] [exec-2] - gc done\n",
+		},
+		{
+			name: "interleaved repeats are not consecutive",
+			lines: texts(node1Source,
+				"2021-03-25T04:00:00Z cache full",
+				"2021-03-25T04:00:01Z gc done",
+				"2021-03-25T04:00:02Z cache full",
+			),
+			want: "2021-03-25T04:00:00Z cache full\n2021-03-25T04:00:01Z gc done\n2021-03-25T04:00:02Z cache full\n",
+		},
+		{
+			name:   "repeats within the window",
+			window: 10 * time.Second,
+			lines: texts(node1Source,
+				"2021-03-25T04:00:00Z cache full",
+				"2021-03-25T04:00:01Z gc done",
+				"2021-03-25T04:00:02Z cache full",
+				"2021-03-25T04:00:05Z cache full",
+				"2021-03-25T04:00:11Z cache full",
+				"2021-03-25T04:00:12Z cache full",
+			),
+			want: "2021-03-25T04:00:00Z cache full\n" +
+				"2021-03-25T04:00:01Z gc done\n" +
+				"2021-03-25T04:00:05Z cache full [repeated 2 times]\n" +
+				"2021-03-25T04:00:11Z cache full\n" +
+				"2021-03-25T04:00:12Z cache full\n",
+		},
+		{
+			name: "stack trace lines belong to their line",
+			lines: texts(node1Source,
+				"2021-03-25 04:00:00,001 [ERROR] request failed",
+				"java.io.IOException: closed",
+				"\tat Stream.read(Stream.java:12)",
+				"2021-03-25 04:00:00,002 [ERROR] request failed",
+				"java.io.IOException: closed",
+				"\tat Stream.read(Stream.java:12)",
+				"2021-03-25 04:00:00,003 [ERROR] request failed",
+				"java.io.IOException: closed",
+				"\tat Stream.read(Stream.java:12)",
+				"2021-03-25 04:00:01,000 [INFO] started",
+			),
+			want: "2021-03-25 04:00:00,001 [ERROR] request failed\n" +
+				"java.io.IOException: closed\n" +
+				"\tat Stream.read(Stream.java:12)\n" +
+				"2021-03-25 04:00:00,003 [ERROR] request failed [repeated 2 times]\n" +
+				"2021-03-25 04:00:01,000 [INFO] started\n",
+		},
+		{
+			name:  "logs without timestamps",
+			lines: texts(node1Source, "retrying", "retrying", "retrying", "connected"),
+			want:  "retrying\nretrying [repeated 2 times]\nconnected\n",
+		},
+		{
+			name:  "repeats released when flushed",
+			lines: texts(node1Source, "retrying", "retrying", "retrying"),
+			want:  "retrying\nretrying [repeated 2 times]\n",
+		},
+		{
+			name: "sources are collapsed separately",
+			lines: []stream.Line{
+				{Source: node1Source, Text: "retrying"},
+				{Source: node2Source, Text: "retrying"},
+				{Source: node1Source, Text: "retrying"},
+				{Source: node2Source, Text: "connected"},
+				{Source: node1Source, Text: "retrying"},
+			},
+			want: "retrying\nretrying\nconnected\nretrying [repeated 2 times]\n",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config := Config{Window: tt.window, Rules: BuiltInRules()}
+			assert.Equal(t, tt.want, collapse(t, config, tt.lines...))
+		})
+	}
+}
+
+func TestDeduplicator_Rules(t *testing.T) {
+	lines := texts(node1Source, "took 12ms", "took 15ms", "took 9ms")
+	assert.Equal(t, "took 12ms\ntook 15ms\ntook 9ms\n", collapse(t, Config{Rules: BuiltInRules()}, lines...))
+
+	rules := append(BuiltInRules(), Rule{Name: "number", Pattern: regexp.MustCompile(`\d+`)})
+	assert.Equal(t, "took 12ms\ntook 9ms [repeated 2 times]\n", collapse(t, Config{Rules: rules}, lines...))
+}
+
+func TestBuiltInRules_HexId(t *testing.T) {
+	deduplicator := Config{Rules: BuiltInRules()}.NewDeduplicator()
+	assert.Equal(t, "trace <hex-id> done", deduplicator.key("trace 94109ae150da76e done"))
+	assert.Equal(t, "sha1 <hex-id>", deduplicator.key("sha1 DA39A3EE5E6B4B0D3255BFEF95601890AFD80709"))
+	// The long decimal numbers, such as the epoch times in milliseconds, are not ids.
+	assert.Equal(t, "expires at 1616644800006", deduplicator.key("expires at 1616644800006"))
+
+	lines := texts(node1Source, "uploaded 104857600000 bytes", "uploaded 209715200000 bytes")
+	assert.Equal(t, "uploaded 104857600000 bytes\nuploaded 209715200000 bytes\n", collapse(t, Config{Rules: BuiltInRules()}, lines...))
+}
+
+func TestDeduplicator_WindowWithoutTimestamps(t *testing.T) {
+	realNow := now
+	defer func() { now = realNow }()
+	currentTime := time.Date(2021, 3, 25, 4, 0, 0, 0, time.UTC)
+	now = func() time.Time { return currentTime }
+
+	out := &bytes.Buffer{}
+	pipeline := stream.NewPipeline(out, Config{Window: time.Minute}.NewDeduplicator())
+	require.NoError(t, pipeline.Write(texts(node1Source, "retrying", "connected", "retrying", "retrying")...))
+	assert.Equal(t, "retrying\nconnected\n", out.String())
+
+	currentTime = currentTime.Add(2 * time.Minute)
+	require.NoError(t, pipeline.Write(texts(node1Source, "retrying")...))
+	assert.Equal(t, "retrying\nconnected\nretrying [repeated 2 times]\nretrying\n", out.String())
+}
+
+func TestDeduplicator_FlushStale(t *testing.T) {
+	realNow := now
+	defer func() { now = realNow }()
+	currentTime := time.Date(2021, 3, 25, 4, 0, 0, 0, time.UTC)
+	now = func() time.Time { return currentTime }
+
+	tests := []struct {
+		name   string
+		window time.Duration
+		// The time after the burst when the repeats are not released yet, and when they are.
+		held, released time.Duration
+	}{
+		{name: "consecutive repeats", held: time.Second, released: 2 * time.Second},
+		{name: "repeats within the window", window: time.Minute, held: 30 * time.Second, released: 2 * time.Minute},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			burstTime := currentTime
+			out := &bytes.Buffer{}
+			pipeline := stream.NewPipeline(out, Config{Window: tt.window, Rules: BuiltInRules()}.NewDeduplicator())
+			require.NoError(t, pipeline.Write(texts(node1Source,
+				"2021-03-25T04:00:00Z cache full", "2021-03-25T04:00:01Z cache full", "2021-03-25T04:00:02Z cache full")...))
+
+			currentTime = burstTime.Add(tt.held)
+			require.NoError(t, pipeline.FlushStale(2*time.Second))
+			assert.Equal(t, "2021-03-25T04:00:00Z cache full\n", out.String())
+
+			currentTime = burstTime.Add(tt.released)
+			require.NoError(t, pipeline.FlushStale(2*time.Second))
+			assert.Equal(t, "2021-03-25T04:00:00Z cache full\n2021-03-25T04:00:02Z cache full [repeated 2 times]\n", out.String())
+
+			// The following repeat starts a new burst.
+			require.NoError(t, pipeline.Write(texts(node1Source, "2021-03-25T04:00:03Z cache full")...))
+			require.NoError(t, pipeline.Flush())
+			assert.Equal(t, "2021-03-25T04:00:00Z cache full\n2021-03-25T04:00:02Z cache full [repeated 2 times]\n"+
+				"2021-03-25T04:00:03Z cache full\n", out.String())
+		})
+	}
+}
+
+func TestLoadRules(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		wantErr bool
+	}{
+		{name: "valid rules", content: `[{"name": "number", "pattern": "[0-9]+"}]`},
+		{name: "invalid pattern", content: `[{"name": "bad", "pattern": "("}]`, wantErr: true},
+		{name: "missing name", content: `[{"pattern": "a"}]`, wantErr: true},
+		{name: "invalid json", content: `{`, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rulesFilePath := filepath.Join(t.TempDir(), "rules.json")
+			require.NoError(t, ioutil.WriteFile(rulesFilePath, []byte(tt.content), 0600))
+			rules, err := LoadRules(rulesFilePath)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, "took <number>ms", Config{Rules: rules}.NewDeduplicator().key("took 12ms"))
+		})
+	}
+}
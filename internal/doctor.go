@@ -0,0 +1,47 @@
+package livelog
+
+import (
+	"context"
+	"fmt"
+	"github.com/jfrog/live-logs/internal/model"
+	"github.com/jfrog/live-logs/internal/servicelayer"
+	"io"
+	"os"
+	"strings"
+)
+
+// Method initialised as a variable to improved unit test coverage
+var diagnose = servicelayer.Diagnose
+
+// Runs the diagnosis checks of the product with the server id and displays a report.
+// An error is returned when any of the checks failed.
+func (s *Data) Doctor(ctx context.Context, cliProductId, cliServerId string) error {
+	checks := diagnose(ctx, cliProductId, cliServerId)
+	err := writeDoctorReport(os.Stdout, checks)
+	if err != nil {
+		return err
+	}
+	for _, check := range checks {
+		if check.Status == model.CheckFailed {
+			return fmt.Errorf("the %s check failed", strings.ToLower(check.Name))
+		}
+	}
+	return nil
+}
+
+func writeDoctorReport(output io.Writer, checks []model.DoctorCheck) error {
+	for _, check := range checks {
+		line := fmt.Sprintf("[%s] %s", strings.ToUpper(check.Status), check.Name)
+		if check.Details != "" {
+			line += ": " + check.Details
+		}
+		if check.Hint != "" {
+			line += "\n       hint: " + check.Hint
+		}
+		_, err := fmt.Fprintln(output, line)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,36 @@
+package livelog
+
+import (
+	"bytes"
+	"context"
+	"github.com/jfrog/live-logs/internal/model"
+	"github.com/stretchr/testify/require"
+	"testing"
+)
+
+func Test_LiveLogs_Doctor(t *testing.T) {
+	realDiagnose := diagnose
+	defer func() { diagnose = realDiagnose }()
+
+	checks := []model.DoctorCheck{
+		{Name: "CLI config", Status: model.CheckPassed, Details: "server id test-rt found"},
+	}
+	diagnose = func(_ context.Context, productId, serverId string) []model.DoctorCheck {
+		return checks
+	}
+	s := &Data{}
+	require.NoError(t, s.Doctor(context.Background(), "rt", "test-rt"))
+
+	checks = append(checks,
+		model.DoctorCheck{Name: "Product url", Status: model.CheckFailed, Details: "the Xray url is not configured", Hint: "set the Xray url"},
+		model.DoctorCheck{Name: "DNS", Status: model.CheckSkipped})
+	err := s.Doctor(context.Background(), "xr", "test-rt")
+	require.EqualError(t, err, "the product url check failed")
+
+	out := &bytes.Buffer{}
+	require.NoError(t, writeDoctorReport(out, checks))
+	require.Equal(t, "[PASS] CLI config: server id test-rt found\n"+
+		"[FAIL] Product url: the Xray url is not configured\n"+
+		"       hint: set the Xray url\n"+
+		"[SKIP] DNS\n", out.String())
+}
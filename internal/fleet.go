@@ -0,0 +1,86 @@
+package livelog
+
+import (
+	"context"
+	"fmt"
+	"github.com/jfrog/live-logs/internal/constants"
+	"github.com/jfrog/live-logs/internal/model"
+	"github.com/jfrog/live-logs/internal/stream"
+	"github.com/jfrog/live-logs/internal/util"
+	"os"
+)
+
+// Returns a copy of the settings for another server, with its own service layer.
+func (s *Data) forServer(serverId string) (*Data, error) {
+	server := &Data{
+		productId:           s.productId,
+		serviceId:           serverId,
+		logsRefreshRate:     s.logsRefreshRate,
+		configFormat:        s.configFormat,
+		refreshRateOverride: s.refreshRateOverride,
+		recorder:            s.recorder,
+		pipelineOptions:     s.pipelineOptions,
+	}
+	return server, server.SetServiceLayer(server.GetProductId())
+}
+
+// Writes the logs of the same product on several servers, each line labeled with its server id.
+// The node ids and log file names are resolved on each server, and each server is polled at its own refresh rate.
+// When all the nodes are tailed, the nodes of each server are followed while they join and leave.
+func (s *Data) printFleetLogs(ctx context.Context, serverIds []string, nodeSelection, logSelection string, isStreaming bool) error {
+	isWatchingNodes := isStreaming && nodeSelection == constants.AllValues
+	labeler := stream.Labeler{Server: true, Node: isWatchingNodes}
+	var targets []logTarget
+	var watched []watchedServer
+	for _, serverId := range serverIds {
+		server, err := s.forServer(serverId)
+		if err != nil {
+			return err
+		}
+		srvConfig, err := server.GetServiceLayer().GetConfig(ctx, serverId)
+		if err != nil {
+			return fmt.Errorf("%s: %w", serverId, err)
+		}
+		server.SetLogsRefreshRate(util.MillisToDuration(srvConfig.RefreshRateMillis))
+		logNames, err := util.ParseSelection("log name", logSelection, srvConfig.LogFileNames)
+		if err != nil {
+			return fmt.Errorf("%s: %w", serverId, err)
+		}
+		nodeIds, err := util.ParseSelection("node id", nodeSelection, srvConfig.Nodes)
+		if err != nil {
+			return fmt.Errorf("%s: %w", serverId, err)
+		}
+		labeler.Node = labeler.Node || len(nodeIds) > 1
+		labeler.Log = labeler.Log || len(logNames) > 1
+		if isWatchingNodes {
+			watched = append(watched, watchedServer{server: server, nodeIds: nodeIds, logNames: logNames})
+			continue
+		}
+		for _, nodeId := range nodeIds {
+			for _, logName := range logNames {
+				targets = append(targets, logTarget{server: server, nodeId: nodeId, logName: logName})
+			}
+		}
+	}
+	if isWatchingNodes {
+		return watchAllNodes(ctx, watched, labeler)
+	}
+	return printTargets(ctx, targets, labeler, isStreaming)
+}
+
+// Displays the nodes and log files of the same product on several servers.
+func (s *Data) displayFleetConfig(ctx context.Context, serverIds []string) error {
+	var displayData []model.ConfigDisplayData
+	for _, serverId := range serverIds {
+		server, err := s.forServer(serverId)
+		if err != nil {
+			return err
+		}
+		serverData, err := server.getConfigDisplayData(ctx)
+		if err != nil {
+			return fmt.Errorf("%s: %w", serverId, err)
+		}
+		displayData = append(displayData, serverData)
+	}
+	return writeConfig(os.Stdout, s.GetConfigFormat(), displayData...)
+}
@@ -0,0 +1,131 @@
+package livelog
+
+import (
+	"context"
+	"github.com/jfrog/live-logs/internal/color"
+	"github.com/jfrog/live-logs/internal/constants"
+	"github.com/jfrog/live-logs/internal/dedup"
+	"github.com/jfrog/live-logs/internal/model"
+	"github.com/jfrog/live-logs/internal/redact"
+	"github.com/jfrog/live-logs/internal/servicelayer"
+	"github.com/jfrog/live-logs/internal/timerange"
+	"github.com/stretchr/testify/require"
+	"io/ioutil"
+	"os"
+	"strings"
+	"testing"
+	"time"
+)
+
+// Returns a single line naming its server, node and log file, and the nodes of each server.
+type fleetMockServiceLayer struct {
+	multiLogMockServiceLayer
+	nodes map[string][]string
+}
+
+func (s *fleetMockServiceLayer) GetConfig(_ context.Context, serverId string) (*model.Config, error) {
+	return &model.Config{Nodes: s.nodes[serverId], LogFileNames: []string{"one.log", "two.log"}, RefreshRateMillis: 1}, nil
+}
+
+func (s *fleetMockServiceLayer) GetLogData(_ context.Context, serverId string) (model.Data, error) {
+	if s.lastPageMarker > 0 {
+		return model.Data{PageMarker: s.lastPageMarker}, nil
+	}
+	content := serverId + "/" + s.expectNodeId + "/" + s.expectLogFileName + "\n"
+	return model.Data{Content: content, PageMarker: int64(len(content))}, nil
+}
+
+func Test_LiveLogs_LogNonInteractive_Fleet(t *testing.T) {
+	realServiceLayer := newServiceLayer
+	realGetAllServiceIds := getAllServiceIds
+	defer func() {
+		newServiceLayer = realServiceLayer
+		getAllServiceIds = realGetAllServiceIds
+	}()
+	getAllServiceIds = func() []string {
+		return []string{"eu-rt", "us-rt", "dev-rt"}
+	}
+	newServiceLayer = func(productId string) (servicelayer.ServiceLayer, error) {
+		return &fleetMockServiceLayer{
+			multiLogMockServiceLayer: multiLogMockServiceLayer{mockServiceLayer: mockServiceLayer{t: t}},
+			nodes:                    map[string][]string{"eu-rt": {"eu-node"}, "us-rt": {"us-node1", "us-node2"}},
+		}, nil
+	}
+
+	tests := []struct {
+		name      string
+		serverIds string
+		nodeId    string
+		logName   string
+		want      []string
+		wantErr   string
+	}{
+		{
+			name:      "node ids resolved per server",
+			serverIds: "eu-rt,us-rt",
+			nodeId:    constants.AllValues,
+			logName:   "one.log",
+			want: []string{"[eu-rt eu-node] eu-rt/eu-node/one.log", "[us-rt us-node1] us-rt/us-node1/one.log",
+				"[us-rt us-node2] us-rt/us-node2/one.log"},
+		},
+		{
+			name:      "server id wildcard",
+			serverIds: "eu-*",
+			nodeId:    "eu-node",
+			logName:   "one.log,two.log",
+			want:      []string{"[one.log] eu-rt/eu-node/one.log", "[two.log] eu-rt/eu-node/two.log"},
+		},
+		{
+			name:      "node id missing on a server",
+			serverIds: "eu-rt,us-rt",
+			nodeId:    "eu-node",
+			logName:   "one.log",
+			wantErr:   "us-rt: node id not found",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rescueStdout := os.Stdout
+			r, w, _ := os.Pipe()
+			os.Stdout = w
+
+			s := &Data{}
+			err := s.LogNonInteractive(context.Background(), constants.ArtifactoryId, tt.serverIds, tt.nodeId, tt.logName, false)
+
+			w.Close()
+			out, _ := ioutil.ReadAll(r)
+			os.Stdout = rescueStdout
+
+			if tt.wantErr != "" {
+				require.Error(t, err)
+				require.Contains(t, err.Error(), tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			require.ElementsMatch(t, tt.want, strings.Split(strings.TrimSuffix(string(out), "\n"), "\n"))
+		})
+	}
+}
+
+func Test_forServer(t *testing.T) {
+	realServiceLayer := newServiceLayer
+	defer func() { newServiceLayer = realServiceLayer }()
+	newServiceLayer = func(productId string) (servicelayer.ServiceLayer, error) {
+		return &fleetMockServiceLayer{}, nil
+	}
+
+	options := pipelineOptions{
+		timeRange:     &timerange.Range{},
+		deduplication: &dedup.Config{Rules: dedup.BuiltInRules()},
+		redactor:      redact.NewRedactor(redact.BuiltInRules(), false, ""),
+		colorizer:     &color.Colorizer{},
+	}
+	s := &Data{productId: constants.ArtifactoryId, serviceId: "first", logsRefreshRate: time.Second, pipelineOptions: options}
+	server, err := s.forServer("second")
+	require.NoError(t, err)
+	require.Equal(t, "second", server.GetServiceId())
+	require.Equal(t, constants.ArtifactoryId, server.GetProductId())
+	require.Equal(t, time.Second, server.GetLogsRefreshRate())
+	require.Equal(t, options, server.pipelineOptions)
+	require.NotNil(t, server.GetServiceLayer())
+}
@@ -0,0 +1,227 @@
+// Package format renders the log lines with Go text/template templates. Each line is parsed into a Record, holding
+// the fields of the service and request logs along with the origin of the line.
+package format
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"github.com/jfrog/live-logs/internal/stream"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"text/template"
+	"time"
+)
+
+const (
+	ShortFormat  = "short"
+	WideFormat   = "wide"
+	LogfmtFormat = "logfmt"
+)
+
+// The built-in formats, a format which is not one of them is parsed as a template.
+var namedFormats = map[string]string{
+	ShortFormat: `{{if .IsRequest}}{{.Time}} {{.Method}} {{.Url}} {{.Status}} {{.Duration}}ms` +
+		`{{else if .IsService}}{{.Time}} {{.Level}} {{.Message}}{{else}}{{.Line}}{{end}}`,
+	WideFormat: `{{.Server}} {{.Product}} {{.Node}} {{.Log}} ` +
+		`{{if .IsRequest}}{{.Time}} {{.TraceId}} {{.RemoteAddress}} {{.Username}} {{.Method}} {{.Url}} {{.Status}} {{.RequestSize}} {{.ResponseSize}} {{.Duration}}ms{{with .UserAgent}} {{.}}{{end}}` +
+		`{{else if .IsService}}{{.Time}} {{.Level}} {{.Service}} {{.TraceId}} {{.Class}} {{.Thread}} {{.Message}}{{else}}{{.Line}}{{end}}`,
+	LogfmtFormat: `{{logfmt .}}`,
+}
+
+var templateFuncs = template.FuncMap{
+	"logfmt": logfmt,
+	"quote":  strconv.Quote,
+	"json":   toJson,
+}
+
+// Returns the names of the built-in formats.
+func NamedFormats() []string {
+	var names []string
+	for name := range namedFormats {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// A pipeline stage replacing each line with the rendering of its record.
+type Formatter struct {
+	template *template.Template
+}
+
+// Creates a formatter from a built-in format name, or from a template applied to a Record.
+func NewFormatter(format string) (*Formatter, error) {
+	text, ok := namedFormats[format]
+	if !ok {
+		text = format
+	}
+	lineTemplate, err := template.New("line").Funcs(templateFuncs).Parse(text)
+	if err != nil {
+		return nil, fmt.Errorf("invalid format template: %w", err)
+	}
+	return &Formatter{template: lineTemplate}, nil
+}
+
+// Renders the line, a line which fails to render is written as is, along with the template error.
+func (f *Formatter) Process(line *stream.Line) bool {
+	var rendered bytes.Buffer
+	err := f.template.Execute(&rendered, Parse(*line))
+	if err != nil {
+		line.Text += " [format error: " + err.Error() + "]"
+		return true
+	}
+	line.Text = rendered.String()
+	return true
+}
+
+// The fields of a log line. The fields which do not apply to the kind of the line are empty, and the unparsed
+// lines only have the Line, Message and origin fields.
+type Record struct {
+	Server  string
+	Product string
+	Node    string
+	Log     string
+	// The whole line, as it was read.
+	Line string
+
+	IsService bool
+	IsRequest bool
+
+	Time    string
+	TraceId string
+
+	// The service log fields.
+	Level   string
+	Service string
+	Class   string
+	Thread  string
+	Message string
+
+	// The request log fields.
+	RemoteAddress string
+	Username      string
+	Method        string
+	Url           string
+	Status        string
+	RequestSize   string
+	ResponseSize  string
+	// The request duration in milliseconds.
+	Duration  string
+	UserAgent string
+}
+
+// The service log lines look like
+// 2021-03-25T04:00:00.006Z [jfrt ] [INFO ] [d76675e362ffbd6a] [.s.d.b.s.g.GarbageCollector:66] [art-exec-11 ] - Starting GC
+var servicePattern = regexp.MustCompile(`^(\S+) \[([^\]]*)\] \[([^\]]*)\] \[([^\]]*)\] \[([^\]]*)\] \[([^\]]*)\]\s*(?:- )?(.*)$`)
+
+// The number of '|' separated fields of the request log lines, such as
+// 2021-03-25T04:30:34.199Z|94109ae150da76e|127.0.0.1|admin|GET|/api/release/bundles|200|-1|0|3|JFrog-Router/7.17.0
+// The last field, the user agent, is missing from the lines of the older versions, and is kept whole when it
+// contains a '|'.
+const requestFields = 11
+
+// Parses a service or request log line, the lines of other formats are kept whole in the Message field.
+func Parse(line stream.Line) Record {
+	record := Record{
+		Server:  line.Source.ServerId,
+		Product: line.Source.ProductId,
+		Node:    line.Source.NodeId,
+		Log:     line.Source.LogName,
+		Line:    line.Text,
+		Message: line.Text,
+	}
+	if match := servicePattern.FindStringSubmatch(line.Text); match != nil && isTimestamp(match[1]) {
+		record.IsService = true
+		record.Time = match[1]
+		record.Service = strings.TrimSpace(match[2])
+		record.Level = strings.TrimSpace(match[3])
+		record.TraceId = strings.TrimSpace(match[4])
+		record.Class = strings.TrimSpace(match[5])
+		record.Thread = strings.TrimSpace(match[6])
+		record.Message = match[7]
+		return record
+	}
+	if fields := strings.SplitN(line.Text, "|", requestFields); len(fields) >= requestFields-1 && isTimestamp(fields[0]) {
+		record.IsRequest = true
+		record.Time = fields[0]
+		record.TraceId = fields[1]
+		record.RemoteAddress = fields[2]
+		record.Username = fields[3]
+		record.Method = fields[4]
+		record.Url = fields[5]
+		record.Status = fields[6]
+		record.RequestSize = fields[7]
+		record.ResponseSize = fields[8]
+		record.Duration = fields[9]
+		if len(fields) == requestFields {
+			record.UserAgent = fields[10]
+		}
+		record.Message = ""
+	}
+	return record
+}
+
+func isTimestamp(value string) bool {
+	_, err := time.Parse(time.RFC3339Nano, value)
+	return err == nil
+}
+
+// A named field of a record.
+type Field struct {
+	Key   string
+	Value string
+}
+
+// Returns the non empty fields of the record in a stable order, with snake case keys such as trace_id.
+func (r Record) Fields() []Field {
+	fields := []Field{
+		{"time", r.Time},
+		{"server", r.Server},
+		{"product", r.Product},
+		{"node", r.Node},
+		{"log", r.Log},
+		{"level", r.Level},
+		{"service", r.Service},
+		{"trace_id", r.TraceId},
+		{"class", r.Class},
+		{"thread", r.Thread},
+		{"remote_address", r.RemoteAddress},
+		{"username", r.Username},
+		{"method", r.Method},
+		{"url", r.Url},
+		{"status", r.Status},
+		{"request_size", r.RequestSize},
+		{"response_size", r.ResponseSize},
+		{"duration_ms", r.Duration},
+		{"user_agent", r.UserAgent},
+		{"msg", r.Message},
+	}
+	nonEmpty := fields[:0]
+	for _, field := range fields {
+		if field.Value != "" {
+			nonEmpty = append(nonEmpty, field)
+		}
+	}
+	return nonEmpty
+}
+
+// Renders the non empty fields of the record as logfmt key=value pairs.
+func logfmt(record Record) string {
+	var rendered []string
+	for _, field := range record.Fields() {
+		value := field.Value
+		if strings.ContainsAny(value, " =\"\t") {
+			value = strconv.Quote(value)
+		}
+		rendered = append(rendered, field.Key+"="+value)
+	}
+	return strings.Join(rendered, " ")
+}
+
+func toJson(value interface{}) (string, error) {
+	data, err := json.Marshal(value)
+	return string(data), err
+}
@@ -0,0 +1,112 @@
+package format
+
+import (
+	"github.com/jfrog/live-logs/internal/stream"
+	"github.com/stretchr/testify/require"
+	"testing"
+)
+
+const (
+	serviceLine = "2021-03-25T04:00:00.006Z [jfrt ] [INFO ] [d76675e362ffbd6a] [.s.d.b.s.g.GarbageCollector:66] [art-exec-11         ] - Starting GC strategy"
+	requestLine = "2021-03-25T04:30:34.199Z|94109ae150da76e|127.0.0.1|admin|GET|/api/release/bundles|200|-1|0|3"
+	// The request log lines of the recent versions end with the user agent.
+	userAgentRequestLine = "2021-03-25T04:00:00.006Z|d76675e362ffbd6a|10.0.0.1|admin|GET|/api/system/ping|200|-1|2|3|JFrog-Router/7.17.0"
+)
+
+var source = stream.Source{ServerId: "local-rt", ProductId: "rt", NodeId: "node1", LogName: "artifactory-service.log"}
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		name string
+		text string
+		want Record
+	}{
+		{
+			name: "service",
+			text: serviceLine,
+			want: Record{Server: "local-rt", Product: "rt", Node: "node1", Log: "artifactory-service.log", Line: serviceLine,
+				IsService: true, Time: "2021-03-25T04:00:00.006Z", TraceId: "d76675e362ffbd6a", Level: "INFO", Service: "jfrt",
+				Class: ".s.d.b.s.g.GarbageCollector:66", Thread: "art-exec-11", Message: "Starting GC strategy"},
+		},
+		{
+			name: "request",
+			text: requestLine,
+			want: Record{Server: "local-rt", Product: "rt", Node: "node1", Log: "artifactory-service.log", Line: requestLine,
+				IsRequest: true, Time: "2021-03-25T04:30:34.199Z", TraceId: "94109ae150da76e", RemoteAddress: "127.0.0.1",
+				Username: "admin", Method: "GET", Url: "/api/release/bundles", Status: "200", RequestSize: "-1", ResponseSize: "0", Duration: "3"},
+		},
+		{
+			name: "request with user agent",
+			text: userAgentRequestLine,
+			want: Record{Server: "local-rt", Product: "rt", Node: "node1", Log: "artifactory-service.log", Line: userAgentRequestLine,
+				IsRequest: true, Time: "2021-03-25T04:00:00.006Z", TraceId: "d76675e362ffbd6a", RemoteAddress: "10.0.0.1",
+				Username: "admin", Method: "GET", Url: "/api/system/ping", Status: "200", RequestSize: "-1", ResponseSize: "2", Duration: "3",
+				UserAgent: "JFrog-Router/7.17.0"},
+		},
+		{
+			name: "user agent with a separator",
+			text: requestLine + "|curl|1.0",
+			want: Record{Server: "local-rt", Product: "rt", Node: "node1", Log: "artifactory-service.log", Line: requestLine + "|curl|1.0",
+				IsRequest: true, Time: "2021-03-25T04:30:34.199Z", TraceId: "94109ae150da76e", RemoteAddress: "127.0.0.1",
+				Username: "admin", Method: "GET", Url: "/api/release/bundles", Status: "200", RequestSize: "-1", ResponseSize: "0", Duration: "3",
+				UserAgent: "curl|1.0"},
+		},
+		{
+			name: "other",
+			text: "    at org.artifactory.Main",
+			want: Record{Server: "local-rt", Product: "rt", Node: "node1", Log: "artifactory-service.log",
+				Line: "    at org.artifactory.Main", Message: "    at org.artifactory.Main"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			require.Equal(t, tt.want, Parse(stream.Line{Source: source, Text: tt.text}))
+		})
+	}
+}
+
+func TestFormatter(t *testing.T) {
+	tests := []struct {
+		name   string
+		format string
+		text   string
+		want   string
+	}{
+		{"short service", ShortFormat, serviceLine, "2021-03-25T04:00:00.006Z INFO Starting GC strategy"},
+		{"short request", ShortFormat, requestLine, "2021-03-25T04:30:34.199Z GET /api/release/bundles 200 3ms"},
+		{"short request with user agent", ShortFormat, userAgentRequestLine, "2021-03-25T04:00:00.006Z GET /api/system/ping 200 3ms"},
+		{"short other", ShortFormat, "plain", "plain"},
+		{"wide service", WideFormat, serviceLine, "local-rt rt node1 artifactory-service.log 2021-03-25T04:00:00.006Z INFO jfrt d76675e362ffbd6a .s.d.b.s.g.GarbageCollector:66 art-exec-11 Starting GC strategy"},
+		{"wide request", WideFormat, userAgentRequestLine, "local-rt rt node1 artifactory-service.log 2021-03-25T04:00:00.006Z d76675e362ffbd6a 10.0.0.1 admin GET /api/system/ping 200 -1 2 3ms JFrog-Router/7.17.0"},
+		{"logfmt request", LogfmtFormat, requestLine, "time=2021-03-25T04:30:34.199Z server=local-rt product=rt node=node1 log=artifactory-service.log trace_id=94109ae150da76e " +
+			"remote_address=127.0.0.1 username=admin method=GET url=/api/release/bundles status=200 request_size=-1 response_size=0 duration_ms=3"},
+		{"logfmt quoting", LogfmtFormat, "a \"quoted\" text", `server=local-rt product=rt node=node1 log=artifactory-service.log msg="a \"quoted\" text"`},
+		{"template", "{{.Node}}: {{.Level | printf \"%-5s\"}}|{{quote .Message}}", serviceLine, `node1: INFO |"Starting GC strategy"`},
+		{"json", "{{json .Level}}", serviceLine, `"INFO"`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			formatter, err := NewFormatter(tt.format)
+			require.NoError(t, err)
+			line := stream.Line{Source: source, Text: tt.text}
+			require.True(t, formatter.Process(&line))
+			require.Equal(t, tt.want, line.Text)
+		})
+	}
+}
+
+func TestFormatter_Errors(t *testing.T) {
+	_, err := NewFormatter("{{.Node")
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "invalid format template")
+
+	formatter, err := NewFormatter("{{.Missing}}")
+	require.NoError(t, err)
+	line := stream.Line{Source: source, Text: "text"}
+	require.True(t, formatter.Process(&line))
+	require.Contains(t, line.Text, "text [format error: ")
+}
+
+func TestNamedFormats(t *testing.T) {
+	require.Equal(t, []string{"logfmt", "short", "wide"}, NamedFormats())
+}
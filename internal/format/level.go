@@ -0,0 +1,32 @@
+package format
+
+import "strings"
+
+// The log levels, shared by the outputs mapping the levels to their own severities.
+const (
+	TraceLevel = "TRACE"
+	DebugLevel = "DEBUG"
+	InfoLevel  = "INFO"
+	WarnLevel  = "WARN"
+	ErrorLevel = "ERROR"
+	FatalLevel = "FATAL"
+)
+
+// The levels logged under another name, such as the java.util.logging levels of some services.
+var levelAliases = map[string]string{
+	"WARNING": WarnLevel,
+	"SEVERE":  ErrorLevel,
+}
+
+// Returns the log level of a level name, ignoring its case, empty when the name is not a known level.
+func Level(name string) string {
+	name = strings.ToUpper(strings.TrimSpace(name))
+	if level, found := levelAliases[name]; found {
+		return level
+	}
+	switch name {
+	case TraceLevel, DebugLevel, InfoLevel, WarnLevel, ErrorLevel, FatalLevel:
+		return name
+	}
+	return ""
+}
@@ -0,0 +1,26 @@
+package format
+
+import (
+	"github.com/stretchr/testify/require"
+	"testing"
+)
+
+func TestLevel(t *testing.T) {
+	tests := []struct {
+		name string
+		want string
+	}{
+		{"INFO", InfoLevel},
+		{"debug", DebugLevel},
+		{"WARNING", WarnLevel},
+		{"SEVERE", ErrorLevel},
+		{" FATAL ", FatalLevel},
+		{"NOTICE", ""},
+		{"", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			require.Equal(t, tt.want, Level(tt.name))
+		})
+	}
+}
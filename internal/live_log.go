@@ -1,13 +1,22 @@
 package livelog
 
 import (
-	"bytes"
 	"context"
-	"encoding/json"
 	"fmt"
-	cliCommands "github.com/jfrog/jfrog-cli-core/v2/common/commands"
+	"github.com/jfrog/live-logs/internal/client"
+	"github.com/jfrog/live-logs/internal/color"
+	"github.com/jfrog/live-logs/internal/clientlayer"
+	"github.com/jfrog/live-logs/internal/constants"
+	"github.com/jfrog/live-logs/internal/dedup"
+	"github.com/jfrog/live-logs/internal/format"
 	"github.com/jfrog/live-logs/internal/model"
+	"github.com/jfrog/live-logs/internal/redact"
+	"github.com/jfrog/live-logs/internal/relay"
 	"github.com/jfrog/live-logs/internal/servicelayer"
+	"github.com/jfrog/live-logs/internal/ship"
+	"github.com/jfrog/live-logs/internal/stream"
+	"github.com/jfrog/live-logs/internal/syslog"
+	"github.com/jfrog/live-logs/internal/timerange"
 	"github.com/jfrog/live-logs/internal/util"
 	"io"
 	"os"
@@ -15,7 +24,7 @@ import (
 )
 
 // Method initialised as a variable to improved unit test coverage
-var getAllServiceIds = cliCommands.GetAllServerIds
+var getAllServiceIds = clientlayer.GetAllServerIds
 var newServiceLayer = servicelayer.NewService
 
 const (
@@ -27,11 +36,29 @@ type Data struct {
 	serviceId       string
 	serviceLayerClient servicelayer.ServiceLayer
 	logsRefreshRate time.Duration
+	configFormat    string
+	refreshRateOverride time.Duration
+	recorder        client.Recorder
+	pipelineOptions
+}
+
+// The output stages the log lines are written through, see newPipeline. The options are shared by all the streams of
+// a session, and copied as a whole for the streams of another server.
+type pipelineOptions struct {
+	timeRange       *timerange.Range
+	deduplication   *dedup.Config
+	redactor        *redact.Redactor
+	syslogForwarder *syslog.Forwarder
+	lineFormatter   *format.Formatter
+	colorizer       *color.Colorizer
 }
 
 type LiveLogs interface {
 	// The non interactive flow to display logs data.
 	// The configured product id, server id, node id and log file name are used.
+	// The server id, node id and log file name can be comma separated lists with wildcards, or "all" for all the values.
+	// When several server ids are selected, the node ids and log file names are resolved on each server.
+	// When all the nodes are tailed, the nodes are refreshed periodically to follow the nodes which join and leave.
 	// Any error during read or write is returned.
 	LogNonInteractive(ctx context.Context, cliProductId, cliServerId, nodeId, logName string, isStreaming bool) error
 
@@ -40,7 +67,14 @@ type LiveLogs interface {
 	// Any error during read or write is returned.
 	PrintLogs (ctx context.Context, nodeId, logName  string, isStreaming bool) error
 
+	// Writes the log data of several nodes and log files, labeling each line with the node and log file it comes from.
+	PrintMultipleLogs(ctx context.Context, nodeIds, logNames []string, isStreaming bool) error
+
+	// Tails the given node and log file into the output, several logs can be streamed concurrently.
+	StreamLog(ctx context.Context, nodeId, logName string, output io.Writer) error
+
 	// Displays the list of available nodes and log files.
+	// The server id can be a comma separated list with wildcards, or "all" for all the server ids.
 	ConfigNonInteractive(ctx context.Context, cliProductId, cliServerId string) error
 
 	// A wrapper around service config api method call.
@@ -49,6 +83,21 @@ type LiveLogs interface {
 	// Displays the list of available nodes and log files.
 	DisplayConfig(ctx context.Context)  error
 
+	// Probes every product with the server id, and returns which ones are configured, reachable, authorized and supported.
+	DiscoverProducts(ctx context.Context, cliServerId string) ([]model.ProductStatus, error)
+
+	// Displays the status of every product for the server id.
+	DisplayProducts(ctx context.Context, cliServerId string) error
+
+	// Checks the configuration, connectivity, authentication and version of the product, and displays a report.
+	Doctor(ctx context.Context, cliProductId, cliServerId string) error
+
+	// Searches all the logs of the products for the lines containing the trace id, and displays them grouped by service.
+	Trace(ctx context.Context, cliServerId, traceId string, productIds []string) error
+
+	// Downloads all the log files from all the nodes into a single tar.gz archive, described by a manifest.
+	ExportBundle(ctx context.Context, cliProductId, cliServerId, outputPath string, concurrency int) error
+
 	// Sets the product id to use when querying the remote service for log data.
 	SetProductId(productId string)
 	GetProductId() (productId string)
@@ -61,6 +110,47 @@ type LiveLogs interface {
 	SetLogsRefreshRate(logsRefreshRate time.Duration)
 	GetLogsRefreshRate() (logsRefreshRate time.Duration)
 
+	// Sets a fixed interval between the queries in tail mode, instead of adapting the interval to the log activity.
+	SetRefreshRateOverride(refreshRate time.Duration)
+
+	// Sets the format used to display the list of available nodes and log files, one of json, yaml, table or csv.
+	SetConfigFormat(configFormat string)
+	GetConfigFormat() (configFormat string)
+
+	// Sets the redactor applied to all the log data before it is written, redaction is disabled when nil.
+	SetRedactor(redactor *redact.Redactor)
+
+	// Sets the time range of the written log lines, all the lines are written when nil.
+	SetTimeRange(timeRange *timerange.Range)
+
+	// Sets which repeated log lines are collapsed into a single line with the number of repeats, all the lines are
+	// written when nil.
+	SetDeduplication(deduplication *dedup.Config)
+
+	// Sets the formatter rendering each log line with a template, the lines are written as read when nil.
+	SetLineFormatter(lineFormatter *format.Formatter)
+
+	// Sets the colorizer applied to the lines written to the terminal, the colors are disabled when nil.
+	SetColorizer(colorizer *color.Colorizer)
+
+	// Sets the forwarder sending the log lines to a syslog server, after the redaction, forwarding is disabled when nil.
+	SetSyslogForwarder(syslogForwarder *syslog.Forwarder)
+
+	// Sets the recorder receiving every chunk of log data read from the servers, recording is disabled when nil.
+	SetRecorder(recorder client.Recorder)
+
+	// Ships the selected log files of a product to a log management system until the context is done.
+	// The node id and log file name can be comma separated lists with wildcards, or "all" for all the values.
+	Ship(ctx context.Context, cliProductId, cliServerId, nodeId, logName string, shipper *ship.Shipper) error
+
+	// Tails the selected log files of a product into the hub until the context is done, for the clients of the hub.
+	// The node id and log file name can be comma separated lists with wildcards, or "all" for all the values.
+	Serve(ctx context.Context, cliProductId, cliServerId, nodeId, logName string, hub *relay.Hub) error
+
+	// Writes the log data of a recording, with the delays between the chunks divided by the speed, or without
+	// delays when the speed is zero.
+	Replay(ctx context.Context, recordingPath string, speed float64) error
+
 	// Sets and gets the a service layer.
 	SetServiceLayer(productId string) error
 	GetServiceLayer() servicelayer.ServiceLayer
@@ -69,6 +159,7 @@ type LiveLogs interface {
 func NewLiveLogs() LiveLogs {
 	return &Data{
 		logsRefreshRate: defaultLogsRefreshRate,
+		configFormat:    constants.JsonFormat,
 	}
 }
 
@@ -106,58 +197,205 @@ func (s *Data) SetLogsRefreshRate(logsRefreshRate time.Duration) {
 	s.logsRefreshRate = logsRefreshRate
 }
 
+func (s *Data) SetRefreshRateOverride(refreshRate time.Duration) {
+	s.refreshRateOverride = refreshRate
+}
+
+func (s *Data) SetConfigFormat(configFormat string) {
+	s.configFormat = configFormat
+}
+
+func (s *Data) GetConfigFormat() string {
+	if s.configFormat == "" {
+		return constants.JsonFormat
+	}
+	return s.configFormat
+}
+
+func (s *Data) SetRedactor(redactor *redact.Redactor) {
+	s.redactor = redactor
+}
+
+func (s *Data) SetRecorder(recorder client.Recorder) {
+	s.recorder = recorder
+}
+
+func (s *Data) SetColorizer(colorizer *color.Colorizer) {
+	s.colorizer = colorizer
+}
+
+func (s *Data) SetLineFormatter(lineFormatter *format.Formatter) {
+	s.lineFormatter = lineFormatter
+}
+
+func (s *Data) SetTimeRange(timeRange *timerange.Range) {
+	s.timeRange = timeRange
+}
+
+func (s *Data) SetDeduplication(deduplication *dedup.Config) {
+	s.deduplication = deduplication
+}
+
+func (s *Data) SetSyslogForwarder(syslogForwarder *syslog.Forwarder) {
+	s.syslogForwarder = syslogForwarder
+}
+
+// Creates the pipeline writing log lines to the output, through the configured output stages. The lines out of the
+// time range are dropped first, then the repeated lines are collapsed, the lines are forwarded to syslog and labeled with the labeler after the redaction,
+// and colored last. The line formatter replaces the labeler when set, as the origin of the lines is part of the
+// formatted records.
+func (o pipelineOptions) newPipeline(output io.Writer, labeler stream.Labeler) *stream.Pipeline {
+	pipeline := stream.NewPipeline(output)
+	if o.timeRange != nil {
+		pipeline.AddStage(o.timeRange.NewFilter())
+	}
+	if o.deduplication != nil {
+		pipeline.AddStage(o.deduplication.NewDeduplicator())
+	}
+	if o.redactor != nil {
+		pipeline.AddStage(o.redactor.NewLineRedactor())
+	}
+	if o.syslogForwarder != nil {
+		pipeline.AddStage(o.syslogForwarder)
+	}
+	if o.lineFormatter != nil {
+		pipeline.AddStage(o.lineFormatter)
+		labeler = stream.Labeler{}
+	}
+	pipeline.AddStage(labeler)
+	if o.colorizer != nil {
+		pipeline.AddStage(o.colorizer.WithLabeler(labeler))
+	}
+	return pipeline
+}
+
 func (s *Data) CatLog(ctx context.Context, output io.Writer) error {
-	s.GetServiceLayer().SetLastPageMarker(0)
-	logReader, err := s.doCatLog(ctx)
+	return s.catLines(ctx, s.GetServiceLayer(), s.newPipeline(output, stream.Labeler{}))
+}
+
+// Reads the log data once and writes its lines, along with the lines held back by the pipeline.
+func (s *Data) catLines(ctx context.Context, serviceLayer servicelayer.ServiceLayer, pipeline *stream.Pipeline) error {
+	err := s.client().Cat(ctx, serviceLayer, pipeline.Write)
 	if err != nil {
 		return err
 	}
-	_, err = io.Copy(output, logReader)
-	return err
+	return pipeline.Flush()
 }
 
 func (s *Data) tailLog(ctx context.Context, output io.Writer) error {
-	s.GetServiceLayer().SetLastPageMarker(0)
-	curLogRefreshRate := time.Duration(0)
-	for {
-		select {
-		case <-ctx.Done():
-			return nil
-		case <-time.After(curLogRefreshRate):
-			if curLogRefreshRate == 0 {
-				curLogRefreshRate = s.logsRefreshRate
-			}
-			var logReader io.Reader
-			var err error
+	return s.tailLines(ctx, s.GetServiceLayer(), s.newPipeline(output, stream.Labeler{}))
+}
+
+// Tails the given node and log file into the output, through the configured output stages.
+// A dedicated service layer is used, so that several logs can be streamed concurrently.
+func (s *Data) StreamLog(ctx context.Context, nodeId, logName string, output io.Writer) error {
+	serviceLayer, err := s.newStreamServiceLayer(nodeId, logName)
+	if err != nil {
+		return err
+	}
+	return s.tailLines(ctx, serviceLayer, s.newPipeline(output, stream.Labeler{}))
+}
+
+// Creates a service layer dedicated to a single node and log file, so that several logs can be read concurrently.
+func (s *Data) newStreamServiceLayer(nodeId, logName string) (servicelayer.ServiceLayer, error) {
+	return s.client().NewStreamService(nodeId, logName)
+}
 
-			logReader, err = s.doCatLog(ctx)
+// Creates the client reading the logs of the product and server, with the refresh rates of the server.
+func (s *Data) client() *client.Client {
+	logClient := client.New(s.GetProductId(), s.GetServiceId(), newServiceLayer)
+	logClient.SetRefreshRate(s.logsRefreshRate)
+	if s.refreshRateOverride > 0 {
+		logClient.SetFixedRefreshRate(s.refreshRateOverride)
+	}
+	logClient.SetRecorder(s.recorder)
+	return logClient
+}
 
+// Writes the log data of every node and log file pair, labeling each line with the node and log file it comes from.
+// In streaming mode the pairs are tailed concurrently, and the first error stops all of them.
+func (s *Data) PrintMultipleLogs(ctx context.Context, nodeIds, logNames []string, isStreaming bool) error {
+	if len(nodeIds) == 1 && len(logNames) == 1 {
+		return s.PrintLogs(ctx, nodeIds[0], logNames[0], isStreaming)
+	}
+	var targets []logTarget
+	for _, nodeId := range nodeIds {
+		for _, logName := range logNames {
+			targets = append(targets, logTarget{server: s, nodeId: nodeId, logName: logName})
+		}
+	}
+	return printTargets(ctx, targets, stream.Labeler{Node: len(nodeIds) > 1, Log: len(logNames) > 1}, isStreaming)
+}
+
+// A node and log file of a server, written along with other logs.
+type logTarget struct {
+	server  *Data
+	nodeId  string
+	logName string
+}
+
+func (t logTarget) wrapError(err error) error {
+	return fmt.Errorf("%s %s %s: %w", t.server.GetServiceId(), t.nodeId, t.logName, err)
+}
+
+// Writes the log data of the targets, each line labeled with its origin.
+// In streaming mode the targets are tailed concurrently, and the first error stops all of them.
+func printTargets(ctx context.Context, targets []logTarget, labeler stream.Labeler, isStreaming bool) error {
+	output := stream.NewSyncWriter(os.Stdout)
+
+	streamsCtx, cancelStreams := context.WithCancel(ctx)
+	defer cancelStreams()
+	errs := make(chan error, len(targets))
+	for _, target := range targets {
+		serviceLayer, err := target.server.newStreamServiceLayer(target.nodeId, target.logName)
+		if err != nil {
+			return err
+		}
+		pipeline := target.server.newPipeline(output, labeler)
+		if !isStreaming {
+			err = target.server.catLines(ctx, serviceLayer, pipeline)
 			if err != nil {
-				return err
+				return target.wrapError(err)
 			}
-			_, err = io.Copy(output, logReader)
+			continue
+		}
+		go func(target logTarget) {
+			err := target.server.tailLines(streamsCtx, serviceLayer, pipeline)
 			if err != nil {
-				return err
+				err = target.wrapError(err)
 			}
+			errs <- err
+		}(target)
+	}
+	if !isStreaming {
+		return nil
+	}
+
+	var firstErr error
+	for range targets {
+		if err := <-errs; err != nil && firstErr == nil {
+			firstErr = err
+			cancelStreams()
 		}
 	}
+	return firstErr
 }
 
-func (s *Data) doCatLog(ctx context.Context) (logReader io.Reader, err error) {
-	if s.GetServiceLayer().GetNodeId() == "" {
-		return nil, fmt.Errorf("node id must be set")
-	}
-	if s.GetServiceLayer().GetLogFileName() == "" {
-		return nil, fmt.Errorf("log file name must be set")
-	}
-	logData := model.Data{}
-	logData, err = s.GetServiceLayer().GetLogData(ctx,s.GetServiceId())
+// Polls the log data and writes only complete lines, see client.Tail. The lines held back by the pipeline are written
+// once they are stale, as the partial lines, or once the polls stop.
+func (s *Data) tailLines(ctx context.Context, serviceLayer servicelayer.ServiceLayer, pipeline *stream.Pipeline) error {
+	logClient := s.client()
+	err := logClient.Tail(ctx, serviceLayer, func(lines ...stream.Line) error {
+		err := pipeline.Write(lines...)
+		if err != nil {
+			return err
+		}
+		return pipeline.FlushStale(logClient.LineFlushTimeout())
+	})
 	if err != nil {
-		return nil, err
+		return err
 	}
-	s.GetServiceLayer().SetLastPageMarker(logData.PageMarker)
-	logDataBuf := bytes.NewBufferString(logData.Content)
-	return logDataBuf, nil
+	return pipeline.Flush()
 }
 
 func (s *Data) LogNonInteractive(ctx context.Context, cliProductId, cliServerId, nodeId, logName string, isStreaming bool) error {
@@ -165,12 +403,14 @@ func (s *Data) LogNonInteractive(ctx context.Context, cliProductId, cliServerId,
 	err := util.ValidateArgument("product id", cliProductId, productIds)
 
 	s.SetProductId(cliProductId)
-	serverIds := getAllServiceIds()
-	s.SetServiceId(cliServerId)
-	err = util.ValidateArgument("server id", cliServerId, serverIds)
+	serverIds, err := util.ParseSelection("server id", cliServerId, getAllServiceIds())
 	if err != nil {
 		return err
 	}
+	if len(serverIds) > 1 {
+		return s.printFleetLogs(ctx, serverIds, nodeId, logName, isStreaming)
+	}
+	s.SetServiceId(serverIds[0])
 
 	err = s.SetServiceLayer(s.GetProductId())
 	if err != nil {
@@ -184,22 +424,26 @@ func (s *Data) LogNonInteractive(ctx context.Context, cliProductId, cliServerId,
 	}
 
 	logsRefreshRate = util.MillisToDuration(srvConfig.RefreshRateMillis)
-	err = util.ValidateArgument("log name", logName, srvConfig.LogFileNames)
+	logNames, err := util.ParseSelection("log name", logName, srvConfig.LogFileNames)
 
 	if err != nil {
 		return err
 	}
-	err = util.ValidateArgument("node id", nodeId, srvConfig.Nodes)
+	nodeIds, err := util.ParseSelection("node id", nodeId, srvConfig.Nodes)
 	if err != nil {
 		return err
 	}
 
 	s.SetLogsRefreshRate(logsRefreshRate)
-	return s.PrintLogs(ctx , nodeId, logName, isStreaming)
+	if isStreaming && nodeId == constants.AllValues {
+		labeler := stream.Labeler{Node: true, Log: len(logNames) > 1}
+		return watchAllNodes(ctx, []watchedServer{{server: s, nodeIds: nodeIds, logNames: logNames}}, labeler)
+	}
+	return s.PrintMultipleLogs(ctx, nodeIds, logNames, isStreaming)
 }
 
 func (s *Data) GetConfigData (ctx context.Context, productId, serviceId string) (srvConfig *model.Config, err error) {
-	s.SetServiceLayer(productId)
+	err = s.SetServiceLayer(productId)
 	if err != nil {
 		return nil, err
 	}
@@ -229,31 +473,33 @@ func (s *Data) PrintLogs (ctx context.Context, nodeId, logName  string, isStream
 }
 
 func (s *Data) DisplayConfig(ctx context.Context)  error {
-	var err error
+	displayData, err := s.getConfigDisplayData(ctx)
+	if err != nil {
+		return err
+	}
+	return writeConfig(os.Stdout, s.GetConfigFormat(), displayData)
+}
 
+func (s *Data) getConfigDisplayData(ctx context.Context) (displayData model.ConfigDisplayData, err error) {
 	err = s.SetServiceLayer(s.GetProductId())
 	if err != nil {
-		return err
+		return
 	}
 
 	srvConfig, fetchErr := s.GetConfigData(ctx, s.GetProductId(), s.GetServiceId())
 
 	if fetchErr != nil {
-		return fetchErr
+		return displayData, fetchErr
 	}
 
-	var displayData model.ConfigDisplayData
-
+	displayData.ServerId = s.GetServiceId()
+	displayData.ProductId = s.GetProductId()
+	displayData.RefreshRateMillis = srvConfig.RefreshRateMillis
 	displayData.Logs = srvConfig.LogFileNames
 	displayData.Nodes = srvConfig.Nodes
-
-	data, err := json.MarshalIndent(displayData, "", "  ")
-	if err != nil {
-		return err
-	}
-
-	fmt.Println(string(data))
-	return nil
+	// The version is informative only, not every product exposes it.
+	displayData.ProductVersion, _ = s.GetServiceLayer().GetVersion(ctx, s.GetServiceId())
+	return
 }
 
 func (s *Data)  ConfigNonInteractive(ctx context.Context, cliProductId, cliServerId string) error {
@@ -265,10 +511,14 @@ func (s *Data)  ConfigNonInteractive(ctx context.Context, cliProductId, cliServe
 	}
 	s.SetProductId(cliProductId)
 
-	serverIds := getAllServiceIds()
-
-	err = util.ValidateArgument("server id", cliServerId, serverIds)
-	s.SetServiceId(cliServerId)
+	serverIds, err := util.ParseSelection("server id", cliServerId, getAllServiceIds())
+	if err != nil {
+		return err
+	}
+	if len(serverIds) > 1 {
+		return s.displayFleetConfig(ctx, serverIds)
+	}
+	s.SetServiceId(serverIds[0])
 
 	return s.DisplayConfig(ctx)
 }
@@ -6,12 +6,18 @@ import (
 	"encoding/json"
 	"fmt"
 	"github.com/jfrog/live-logs/internal/constants"
+	"github.com/jfrog/live-logs/internal/dedup"
+	"github.com/jfrog/live-logs/internal/format"
 	"github.com/jfrog/live-logs/internal/model"
+	"github.com/jfrog/live-logs/internal/redact"
 	"github.com/jfrog/live-logs/internal/servicelayer"
+	"github.com/jfrog/live-logs/internal/syslog"
 	"github.com/stretchr/testify/require"
 	"io/ioutil"
+	"net"
 	"os"
 	"reflect"
+	"strings"
 	"testing"
 	"time"
 )
@@ -101,6 +107,56 @@ func Test_LiveLogs_CatLog(t *testing.T) {
 	}
 }
 
+func Test_LiveLogs_CatLog_Redacted(t *testing.T) {
+	s := &Data{
+		serviceLayerClient: &mockServiceLayer{
+			t:                 t,
+			expectNodeId:      "node-1",
+			expectLogFileName: "one.log",
+			getLogResponse:    model.Data{Content: "login from 10.0.0.1 by john@example.com", PageMarker: 123},
+		},
+		pipelineOptions: pipelineOptions{redactor: redact.NewRedactor(redact.BuiltInRules(), false, "")},
+	}
+	out := &bytes.Buffer{}
+	err := s.CatLog(context.Background(), out)
+	require.NoError(t, err)
+	require.Equal(t, "login from [REDACTED] by [REDACTED]", out.String())
+}
+
+func Test_LiveLogs_CatLog_Syslog(t *testing.T) {
+	listener, err := net.ListenPacket("udp", "127.0.0.1:0")
+	require.NoError(t, err)
+	defer listener.Close()
+	forwarder, err := syslog.NewForwarder(syslog.Config{Url: "udp://" + listener.LocalAddr().String()})
+	require.NoError(t, err)
+	defer forwarder.Close()
+	lineFormatter, err := format.NewFormatter("{{.Node}}: {{.Message}}")
+	require.NoError(t, err)
+	s := &Data{
+		serviceLayerClient: &mockServiceLayer{
+			t:                 t,
+			expectNodeId:      "node-1",
+			expectLogFileName: "one.log",
+			getLogResponse:    model.Data{Content: "login by john@example.com\n", PageMarker: 123},
+		},
+		pipelineOptions: pipelineOptions{
+			redactor:        redact.NewRedactor(redact.BuiltInRules(), false, ""),
+			syslogForwarder: forwarder,
+			lineFormatter:   lineFormatter,
+		},
+	}
+	out := &bytes.Buffer{}
+	require.NoError(t, s.CatLog(context.Background(), out))
+	require.Equal(t, "node-1: login by [REDACTED]\n", out.String())
+
+	// The redacted line is forwarded as read, before it is formatted.
+	buffer := make([]byte, 4096)
+	require.NoError(t, listener.SetReadDeadline(time.Now().Add(10*time.Second)))
+	n, _, err := listener.ReadFrom(buffer)
+	require.NoError(t, err)
+	require.True(t, strings.HasSuffix(string(buffer[:n]), "] login by [REDACTED]"), string(buffer[:n]))
+}
+
 func Test_LiveLogs_PrintLogs(t *testing.T) {
 	tests := []struct {
 		name            string
@@ -196,7 +252,7 @@ func Test_LiveLogs_DisplayConfig(t *testing.T) {
 			nodeId:          "node-1",
 			logFileName:     "one.log",
 			mockGetConfigResponse: &model.Config{ Nodes : []string{"node1","node2"}, LogFileNames: []string{"log1","log2"}, RefreshRateMillis: 10000},
-			want:            model.ConfigDisplayData{Nodes : []string{"node1","node2"}, Logs: []string{"log1","log2"}},
+			want:            model.ConfigDisplayData{Nodes : []string{"node1","node2"}, Logs: []string{"log1","log2"}, RefreshRateMillis: 10000},
 		},
 	}
 	for _, tt := range tests {
@@ -240,6 +296,9 @@ func Test_LiveLogs_DisplayConfig(t *testing.T) {
 			if !reflect.DeepEqual(actualOut.Logs, tt.want.Logs ) {
 				t.Errorf("DisplayConfig() got = %v, want %v", actualOut.Logs, tt.want.Logs)
 			}
+			if actualOut.RefreshRateMillis != tt.want.RefreshRateMillis {
+				t.Errorf("DisplayConfig() got = %v, want %v", actualOut.RefreshRateMillis, tt.want.RefreshRateMillis)
+			}
 		})
 	}
 }
@@ -268,10 +327,11 @@ func Test_LiveLogs_ConfigNonInteractive(t *testing.T) {
 		{
 			name:            "ConfigNonInteractive response",
 			productId:        constants.ArtifactoryId,
+			serviceId:        "test-rt",
 			nodeId:          "node-1",
 			logFileName:     "one.log",
 			mockGetConfigResponse: &model.Config{ Nodes : []string{"node1","node2"}, LogFileNames: []string{"log1","log2"}, RefreshRateMillis: 10000},
-			want:            model.ConfigDisplayData{Nodes : []string{"node1","node2"}, Logs: []string{"log1","log2"}},
+			want:            model.ConfigDisplayData{Nodes : []string{"node1","node2"}, Logs: []string{"log1","log2"}, RefreshRateMillis: 10000},
 		},
 	}
 	for _, tt := range tests {
@@ -304,6 +364,12 @@ func Test_LiveLogs_ConfigNonInteractive(t *testing.T) {
 			r, w, _ := os.Pipe()
 			os.Stdout = w
 
+			realGetAllServiceIds := getAllServiceIds
+			getAllServiceIds = func() []string {
+				return []string{"test-rt"}
+			}
+			defer func() { getAllServiceIds = realGetAllServiceIds }()
+
 			err := s.ConfigNonInteractive(context.Background(),tt.productId, tt.serviceId)
 
 			w.Close()
@@ -384,10 +450,118 @@ func Test_LiveLogs_TailLog(t *testing.T) {
 	}
 }
 
+func Test_LiveLogs_TailLog_PartialLines(t *testing.T) {
+	s := &Data{
+		serviceLayerClient: &sequenceMockServiceLayer{
+			mockServiceLayer: mockServiceLayer{t: t, expectNodeId: "node-1", expectLogFileName: "one.log"},
+			responses: []model.Data{
+				{Content: "first li", PageMarker: 8},
+				{Content: "ne\nsecond", PageMarker: 17},
+			},
+		},
+		logsRefreshRate: time.Millisecond,
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	out := &bytes.Buffer{}
+	err := s.tailLog(ctx, out)
+	require.NoError(t, err)
+	require.Equal(t, "first line\nsecond", out.String())
+}
+
+func Test_LiveLogs_PrintMultipleLogs(t *testing.T) {
+	realServiceLayer := newServiceLayer
+	defer func() { newServiceLayer = realServiceLayer }()
+	newServiceLayer = func(productId string) (servicelayer.ServiceLayer, error) {
+		return &multiLogMockServiceLayer{mockServiceLayer: mockServiceLayer{t: t}}, nil
+	}
+
+	tests := []struct {
+		name        string
+		nodeIds     []string
+		logNames    []string
+		isStreaming bool
+		want        []string
+	}{
+		{
+			name:     "several nodes",
+			nodeIds:  []string{"node-1", "node-2"},
+			logNames: []string{"one.log"},
+			want:     []string{"[node-1] node-1/one.log", "[node-2] node-2/one.log"},
+		},
+		{
+			name:     "several nodes and logs",
+			nodeIds:  []string{"node-1", "node-2"},
+			logNames: []string{"one.log", "two.log"},
+			want: []string{"[node-1 one.log] node-1/one.log", "[node-1 two.log] node-1/two.log",
+				"[node-2 one.log] node-2/one.log", "[node-2 two.log] node-2/two.log"},
+		},
+		{
+			name:        "several logs streamed",
+			nodeIds:     []string{"node-1"},
+			logNames:    []string{"one.log", "two.log"},
+			isStreaming: true,
+			want:        []string{"[one.log] node-1/one.log", "[two.log] node-1/two.log"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := &Data{logsRefreshRate: time.Millisecond}
+			ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+			defer cancel()
+			rescueStdout := os.Stdout
+			r, w, _ := os.Pipe()
+			os.Stdout = w
+
+			err := s.PrintMultipleLogs(ctx, tt.nodeIds, tt.logNames, tt.isStreaming)
+
+			w.Close()
+			out, _ := ioutil.ReadAll(r)
+			os.Stdout = rescueStdout
+
+			require.NoError(t, err)
+			require.ElementsMatch(t, tt.want, strings.Split(strings.TrimSuffix(string(out), "\n"), "\n"))
+		})
+	}
+}
+
+// Returns a single line naming its node and log file, followed by empty log data.
+type multiLogMockServiceLayer struct {
+	mockServiceLayer
+}
+
+func (s *multiLogMockServiceLayer) SetLogFileName(logFileName string) {
+	s.expectLogFileName = logFileName
+}
+
+func (s *multiLogMockServiceLayer) GetLogData(_ context.Context, _ string) (model.Data, error) {
+	if s.lastPageMarker > 0 {
+		return model.Data{PageMarker: s.lastPageMarker}, nil
+	}
+	content := s.expectNodeId + "/" + s.expectLogFileName + "\n"
+	return model.Data{Content: content, PageMarker: int64(len(content))}, nil
+}
+
+// Returns the responses in order, followed by empty log data.
+type sequenceMockServiceLayer struct {
+	mockServiceLayer
+	responses []model.Data
+}
+
+func (s *sequenceMockServiceLayer) GetLogData(_ context.Context, _ string) (model.Data, error) {
+	if len(s.responses) == 0 {
+		return model.Data{PageMarker: s.lastPageMarker}, nil
+	}
+	response := s.responses[0]
+	s.responses = s.responses[1:]
+	return response, nil
+}
+
 type mockServiceLayer struct {
 	t                  *testing.T
 	getLogResponse     model.Data
 	getConfigResponse  *model.Config
+	getVersionResponse string
 	getErr             error
 	expectNodeId       string
 	expectLogFileName  string
@@ -400,6 +574,9 @@ type mockServiceLayer struct {
 func (s *mockServiceLayer) GetLogData (_ context.Context,serviceId string) (logData model.Data, err error) {
 	return s.getLogResponse, s.getErr
 }
+func (s *mockServiceLayer) GetVersion(_ context.Context, serviceId string) (string, error) {
+	return s.getVersionResponse, s.getErr
+}
 func (s *mockServiceLayer) GetConfig(ctx context.Context, serviceId string) (*model.Config, error) {
 	return s.getConfigResponse, s.getErr
 }
@@ -439,3 +616,23 @@ func (s *mockServiceLayer) GetServiceLayer () *mockServiceLayer{
 func (s *mockServiceLayer) SetServiceLayer (productId string) error {
 	return nil
 }
+
+func Test_LiveLogs_CatLog_Dedup(t *testing.T) {
+	s := &Data{
+		serviceLayerClient: &mockServiceLayer{
+			t:                 t,
+			expectNodeId:      "node-1",
+			expectLogFileName: "one.log",
+			getLogResponse: model.Data{Content: "login by john@example.com\nlogin by john@example.com\n" +
+				"login by john@example.com\nlogout\nlogout\n", PageMarker: 123},
+		},
+		pipelineOptions: pipelineOptions{
+			redactor:      redact.NewRedactor(redact.BuiltInRules(), false, ""),
+			deduplication: &dedup.Config{Rules: dedup.BuiltInRules()},
+		},
+	}
+	out := &bytes.Buffer{}
+	require.NoError(t, s.CatLog(context.Background(), out))
+	// The repeats are released through the following stages, and the last ones once the log is read.
+	require.Equal(t, "login by [REDACTED]\nlogin by [REDACTED] [repeated 2 times]\nlogout\nlogout\n", out.String())
+}
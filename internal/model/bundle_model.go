@@ -0,0 +1,22 @@
+package model
+
+import "time"
+
+type BundleManifest struct {
+	ServerId       string       `json:"server_id"`
+	ProductId      string       `json:"product_id"`
+	ProductVersion string       `json:"product_version,omitempty"`
+	Nodes          []string     `json:"nodes"`
+	CreatedAt      time.Time    `json:"created_at"`
+	Files          []BundleFile `json:"files"`
+}
+
+type BundleFile struct {
+	NodeId       string    `json:"node_id"`
+	LogName      string    `json:"log_name"`
+	Path         string    `json:"path,omitempty"`
+	Size         int64     `json:"size"`
+	Sha256       string    `json:"sha256,omitempty"`
+	DownloadedAt time.Time `json:"downloaded_at"`
+	Error        string    `json:"error,omitempty"`
+}
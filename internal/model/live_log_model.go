@@ -16,6 +16,10 @@ type ServiceNode struct {
 }
 
 type ConfigDisplayData struct {
-	Logs      []string `json:"logs,omitempty"`
-	Nodes     []string `json:"nodes"`
+	ServerId          string   `json:"server_id,omitempty" yaml:"server_id,omitempty"`
+	ProductId         string   `json:"product_id,omitempty" yaml:"product_id,omitempty"`
+	ProductVersion    string   `json:"product_version,omitempty" yaml:"product_version,omitempty"`
+	RefreshRateMillis int64    `json:"refresh_rate_millis,omitempty" yaml:"refresh_rate_millis,omitempty"`
+	Logs              []string `json:"logs,omitempty" yaml:"logs,omitempty"`
+	Nodes             []string `json:"nodes" yaml:"nodes"`
 }
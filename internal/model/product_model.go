@@ -0,0 +1,18 @@
+package model
+
+type ProductStatus struct {
+	ProductId        string `json:"product_id"`
+	Url              string `json:"url,omitempty"`
+	Configured       bool   `json:"configured"`
+	Reachable        bool   `json:"reachable"`
+	Authorized       bool   `json:"authorized"`
+	Version          string `json:"version,omitempty"`
+	MinVersion       string `json:"min_version,omitempty"`
+	SupportedVersion bool   `json:"supported_version"`
+	Error            string `json:"error,omitempty"`
+}
+
+// A product is available when its logs can be read with the server id.
+func (p ProductStatus) IsAvailable() bool {
+	return p.Configured && p.Reachable && p.Authorized && p.SupportedVersion
+}
@@ -0,0 +1,14 @@
+package model
+
+import "time"
+
+// A chunk of log data as it was received from a server, along with the time it was received and its origin.
+type RecordedChunk struct {
+	Time       time.Time `json:"time"`
+	ServerId   string    `json:"server_id"`
+	ProductId  string    `json:"product_id"`
+	NodeId     string    `json:"node_id"`
+	LogName    string    `json:"log_name"`
+	Content    string    `json:"log_content"`
+	PageMarker int64     `json:"file_size"`
+}
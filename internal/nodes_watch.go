@@ -0,0 +1,226 @@
+package livelog
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/jfrog/live-logs/internal/servicelayer"
+	"github.com/jfrog/live-logs/internal/stream"
+	"github.com/jfrog/live-logs/internal/util"
+	"io"
+	"net/http"
+	"os"
+	"sync"
+	"time"
+)
+
+// Initialised as a variable to improved unit test coverage
+var nodesRefreshInterval = 30 * time.Second
+
+// The streams of a log file of a node.
+type nodeStreamKey struct {
+	nodeId  string
+	logName string
+}
+
+type nodeStream struct {
+	cancel context.CancelFunc
+}
+
+// Tails the log files of all the nodes of a server, while the nodes are replaced. The nodes are refreshed periodically,
+// the streams of a node which joins are started, and the streams of a node which leaves are stopped.
+// A stream which fails is reported and started again on the following refresh, if its node is still listed. A stream
+// or a refresh rejected by the server, see isFatalError, stops the watch with its error instead, as retrying would not
+// fix it.
+type nodesWatch struct {
+	server   *Data
+	logNames []string
+	pipeline func() *stream.Pipeline
+	notices  io.Writer
+	// Stops the watch, set when it runs.
+	cancel context.CancelFunc
+
+	lock    sync.Mutex
+	nodes   map[string]bool
+	streams map[nodeStreamKey]*nodeStream
+	err     error
+	wg      sync.WaitGroup
+}
+
+func newNodesWatch(server *Data, logNames []string, labeler stream.Labeler, output, notices io.Writer) *nodesWatch {
+	return &nodesWatch{
+		server:   server,
+		logNames: logNames,
+		pipeline: func() *stream.Pipeline {
+			return server.newPipeline(output, labeler)
+		},
+		notices: notices,
+		nodes:   map[string]bool{},
+		streams: map[nodeStreamKey]*nodeStream{},
+	}
+}
+
+// Runs until the context is done, starting with the given nodes. The error which stopped the watch is returned, nil
+// when the context is done.
+func (w *nodesWatch) run(ctx context.Context, nodeIds []string) error {
+	ctx, w.cancel = context.WithCancel(ctx)
+	defer w.cancel()
+	w.update(ctx, nodeIds, false)
+	for {
+		select {
+		case <-ctx.Done():
+			w.wg.Wait()
+			w.lock.Lock()
+			defer w.lock.Unlock()
+			return w.err
+		case <-time.After(nodesRefreshInterval):
+			srvConfig, err := w.server.GetServiceLayer().GetConfig(ctx, w.server.GetServiceId())
+			if err != nil {
+				if isFatalError(err) {
+					w.fail(fmt.Errorf("failed to refresh the nodes of %s: %w", w.server.GetServiceId(), err))
+				} else if ctx.Err() == nil {
+					w.notice("failed to refresh the nodes of %s: %s", w.server.GetServiceId(), err)
+				}
+				continue
+			}
+			w.update(ctx, srvConfig.Nodes, true)
+		}
+	}
+}
+
+// Stops the watch with the error.
+func (w *nodesWatch) fail(err error) {
+	w.lock.Lock()
+	if w.err == nil {
+		w.err = err
+	}
+	w.lock.Unlock()
+	w.cancel()
+}
+
+// Returns whether the server rejected the credentials, the permissions or the selected log, with the status 401, 403
+// or 404, which retrying would not fix.
+func isFatalError(err error) bool {
+	var statusErr *servicelayer.StatusError
+	if !errors.As(err, &statusErr) {
+		return false
+	}
+	switch statusErr.StatusCode {
+	case http.StatusUnauthorized, http.StatusForbidden, http.StatusNotFound:
+		return true
+	}
+	return false
+}
+
+func (w *nodesWatch) update(ctx context.Context, nodeIds []string, notify bool) {
+	w.lock.Lock()
+	defer w.lock.Unlock()
+
+	listed := map[string]bool{}
+	for _, nodeId := range nodeIds {
+		listed[nodeId] = true
+	}
+	for nodeId := range w.nodes {
+		if listed[nodeId] {
+			continue
+		}
+		delete(w.nodes, nodeId)
+		for _, logName := range w.logNames {
+			if running, found := w.streams[nodeStreamKey{nodeId, logName}]; found {
+				running.cancel()
+				delete(w.streams, nodeStreamKey{nodeId, logName})
+			}
+		}
+		w.notice("node %s of %s left", nodeId, w.server.GetServiceId())
+	}
+	for _, nodeId := range nodeIds {
+		if !w.nodes[nodeId] {
+			w.nodes[nodeId] = true
+			if notify {
+				w.notice("node %s of %s joined", nodeId, w.server.GetServiceId())
+			}
+		}
+		for _, logName := range w.logNames {
+			if _, found := w.streams[nodeStreamKey{nodeId, logName}]; !found {
+				w.start(ctx, nodeStreamKey{nodeId, logName})
+			}
+		}
+	}
+}
+
+// Must be called with the lock held.
+func (w *nodesWatch) start(ctx context.Context, key nodeStreamKey) {
+	serviceLayer, err := w.server.newStreamServiceLayer(key.nodeId, key.logName)
+	if err != nil {
+		w.notice("failed to stream %s %s: %s", key.nodeId, key.logName, err)
+		return
+	}
+	streamCtx, cancel := context.WithCancel(ctx)
+	running := &nodeStream{cancel: cancel}
+	w.streams[key] = running
+	pipeline := w.pipeline()
+	w.wg.Add(1)
+	go func() {
+		defer w.wg.Done()
+		err := w.server.tailLines(streamCtx, serviceLayer, pipeline)
+		if err == nil || streamCtx.Err() != nil {
+			return
+		}
+		if isFatalError(err) && w.isListed(streamCtx, key) {
+			w.fail(fmt.Errorf("%s %s %s: %w", w.server.GetServiceId(), key.nodeId, key.logName, err))
+			return
+		}
+		w.notice("stream of %s %s stopped: %s", key.nodeId, key.logName, err)
+		w.lock.Lock()
+		if w.streams[key] == running {
+			delete(w.streams, key)
+		}
+		w.lock.Unlock()
+		cancel()
+	}()
+}
+
+// Returns whether the server still lists the node and the log file of a stream, so that a stream of a node which is
+// leaving is not taken for a rejected one. The stream is taken for a leaving one when the nodes cannot be read.
+func (w *nodesWatch) isListed(ctx context.Context, key nodeStreamKey) bool {
+	srvConfig, err := w.server.GetServiceLayer().GetConfig(ctx, w.server.GetServiceId())
+	if err != nil {
+		return isFatalError(err)
+	}
+	return util.InSlice(srvConfig.Nodes, key.nodeId) && util.InSlice(srvConfig.LogFileNames, key.logName)
+}
+
+func (w *nodesWatch) notice(format string, args ...interface{}) {
+	fmt.Fprintf(w.notices, "--- "+format+" ---\n", args...)
+}
+
+// The log files to tail on all the nodes of a server.
+type watchedServer struct {
+	server   *Data
+	nodeIds  []string
+	logNames []string
+}
+
+// Tails the log files of all the nodes of the servers until the context is done, following the nodes which join and
+// leave. The join and leave notices are written to the standard error, so that they are kept apart from the log data.
+// The first watch which fails stops all of them, and its error is returned.
+func watchAllNodes(ctx context.Context, servers []watchedServer, labeler stream.Labeler) error {
+	output := stream.NewSyncWriter(os.Stdout)
+	notices := stream.NewSyncWriter(os.Stderr)
+	watchesCtx, cancelWatches := context.WithCancel(ctx)
+	defer cancelWatches()
+	errs := make(chan error, len(servers))
+	for _, watched := range servers {
+		go func(watched watchedServer) {
+			errs <- newNodesWatch(watched.server, watched.logNames, labeler, output, notices).run(watchesCtx, watched.nodeIds)
+		}(watched)
+	}
+	var firstErr error
+	for range servers {
+		if err := <-errs; err != nil && firstErr == nil {
+			firstErr = err
+			cancelWatches()
+		}
+	}
+	return firstErr
+}
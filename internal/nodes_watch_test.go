@@ -0,0 +1,132 @@
+package livelog
+
+import (
+	"bytes"
+	"context"
+	"github.com/jfrog/live-logs/internal/model"
+	"github.com/jfrog/live-logs/internal/servicelayer"
+	"github.com/jfrog/live-logs/internal/stream"
+	"github.com/stretchr/testify/require"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+)
+
+// Returns the nodes of each refresh in order, and keeps returning the last ones.
+type nodesMockServiceLayer struct {
+	mockServiceLayer
+	nodes [][]string
+}
+
+func (s *nodesMockServiceLayer) GetConfig(_ context.Context, _ string) (*model.Config, error) {
+	nodes := s.nodes[0]
+	if len(s.nodes) > 1 {
+		s.nodes = s.nodes[1:]
+	}
+	return &model.Config{Nodes: nodes, LogFileNames: []string{"one.log"}}, nil
+}
+
+func Test_LiveLogs_nodesWatch(t *testing.T) {
+	realServiceLayer := newServiceLayer
+	realNodesRefreshInterval := nodesRefreshInterval
+	defer func() {
+		newServiceLayer = realServiceLayer
+		nodesRefreshInterval = realNodesRefreshInterval
+	}()
+	newServiceLayer = func(productId string) (servicelayer.ServiceLayer, error) {
+		return &multiLogMockServiceLayer{mockServiceLayer: mockServiceLayer{t: t}}, nil
+	}
+	nodesRefreshInterval = 20 * time.Millisecond
+
+	s := &Data{
+		serviceId:       "test-rt",
+		logsRefreshRate: time.Millisecond,
+		serviceLayerClient: &nodesMockServiceLayer{
+			mockServiceLayer: mockServiceLayer{t: t},
+			nodes:            [][]string{{"node-1", "node-2"}, {"node-2"}},
+		},
+	}
+	output := &bytes.Buffer{}
+	notices := &bytes.Buffer{}
+	ctx, cancel := context.WithTimeout(context.Background(), 200*time.Millisecond)
+	defer cancel()
+
+	watch := newNodesWatch(s, []string{"one.log"}, stream.Labeler{Node: true}, stream.NewSyncWriter(output), stream.NewSyncWriter(notices))
+	watch.run(ctx, []string{"node-1"})
+
+	require.Equal(t, "--- node node-2 of test-rt joined ---\n--- node node-1 of test-rt left ---\n", notices.String())
+	require.Contains(t, output.String(), "[node-1] node-1/one.log\n")
+	require.Contains(t, output.String(), "[node-2] node-2/one.log\n")
+	require.Equal(t, map[string]bool{"node-2": true}, watch.nodes)
+}
+
+// Fails to read the log data of node-1 with its error, and reads the log data of the other nodes.
+type failingMockServiceLayer struct {
+	multiLogMockServiceLayer
+}
+
+func (s *failingMockServiceLayer) GetLogData(ctx context.Context, serviceId string) (model.Data, error) {
+	if s.expectNodeId == "node-1" {
+		return model.Data{}, s.getErr
+	}
+	return s.multiLogMockServiceLayer.GetLogData(ctx, serviceId)
+}
+
+func Test_LiveLogs_nodesWatch_Errors(t *testing.T) {
+	realServiceLayer := newServiceLayer
+	realNodesRefreshInterval := nodesRefreshInterval
+	defer func() {
+		newServiceLayer = realServiceLayer
+		nodesRefreshInterval = realNodesRefreshInterval
+	}()
+	nodesRefreshInterval = 20 * time.Millisecond
+
+	tests := []struct {
+		name       string
+		streamErr  error
+		nodes      [][]string
+		wantErr    bool
+		wantNotice string
+	}{
+		{name: "rejected credentials", streamErr: &servicelayer.StatusError{StatusCode: http.StatusUnauthorized},
+			nodes: [][]string{{"node-1"}}, wantErr: true},
+		{name: "unknown log", streamErr: &servicelayer.StatusError{StatusCode: http.StatusNotFound},
+			nodes: [][]string{{"node-1"}}, wantErr: true},
+		{name: "leaving node", streamErr: &servicelayer.StatusError{StatusCode: http.StatusNotFound},
+			nodes: [][]string{{"node-2"}}, wantNotice: "stream of node-1 one.log stopped"},
+		{name: "transient failure", streamErr: &servicelayer.StatusError{StatusCode: http.StatusBadGateway},
+			nodes: [][]string{{"node-1"}}, wantNotice: "stream of node-1 one.log stopped"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			newServiceLayer = func(productId string) (servicelayer.ServiceLayer, error) {
+				return &failingMockServiceLayer{multiLogMockServiceLayer{mockServiceLayer{t: t, getErr: tt.streamErr}}}, nil
+			}
+			s := &Data{
+				serviceId:       "test-rt",
+				logsRefreshRate: time.Millisecond,
+				serviceLayerClient: &nodesMockServiceLayer{
+					mockServiceLayer: mockServiceLayer{t: t},
+					nodes:            tt.nodes,
+				},
+			}
+			notices := &bytes.Buffer{}
+			ctx, cancel := context.WithTimeout(context.Background(), 200*time.Millisecond)
+			defer cancel()
+
+			watch := newNodesWatch(s, []string{"one.log"}, stream.Labeler{Node: true}, ioutil.Discard, stream.NewSyncWriter(notices))
+			err := watch.run(ctx, []string{"node-1"})
+			if tt.wantErr {
+				require.Error(t, err)
+				require.True(t, strings.HasPrefix(err.Error(), "test-rt node-1 one.log: "), err.Error())
+				// The watch stops right away rather than retrying.
+				require.NoError(t, ctx.Err())
+				return
+			}
+			require.NoError(t, err)
+			require.Contains(t, notices.String(), tt.wantNotice)
+		})
+	}
+}
@@ -0,0 +1,71 @@
+package livelog
+
+import (
+	"context"
+	"fmt"
+	"github.com/jfrog/live-logs/internal/model"
+	"github.com/jfrog/live-logs/internal/servicelayer"
+	"github.com/jfrog/live-logs/internal/util"
+	"io"
+	"os"
+	"strings"
+	"sync"
+	"text/tabwriter"
+)
+
+// Method initialised as a variable to improved unit test coverage
+var probeProduct = servicelayer.ProbeProduct
+
+var productsTableHeader = []string{"PRODUCT", "CONFIGURED", "REACHABLE", "AUTHORIZED", "VERSION", "SUPPORTED", "ERROR"}
+
+// Probes all the products concurrently, the statuses are returned in the order of the product ids.
+func (s *Data) DiscoverProducts(ctx context.Context, cliServerId string) ([]model.ProductStatus, error) {
+	err := util.ValidateArgument("server id", cliServerId, getAllServiceIds())
+	if err != nil {
+		return nil, err
+	}
+	productIds := util.FetchAllProductIds()
+	statuses := make([]model.ProductStatus, len(productIds))
+	var wg sync.WaitGroup
+	for i, productId := range productIds {
+		wg.Add(1)
+		go func(i int, productId string) {
+			defer wg.Done()
+			statuses[i] = probeProduct(ctx, productId, cliServerId)
+		}(i, productId)
+	}
+	wg.Wait()
+	return statuses, nil
+}
+
+func (s *Data) DisplayProducts(ctx context.Context, cliServerId string) error {
+	statuses, err := s.DiscoverProducts(ctx, cliServerId)
+	if err != nil {
+		return err
+	}
+	return writeProducts(os.Stdout, statuses)
+}
+
+func writeProducts(output io.Writer, statuses []model.ProductStatus) error {
+	tableWriter := tabwriter.NewWriter(output, 0, 0, 2, ' ', 0)
+	_, err := fmt.Fprintln(tableWriter, strings.Join(productsTableHeader, "\t"))
+	if err != nil {
+		return err
+	}
+	for _, status := range statuses {
+		row := []string{status.ProductId, yesNo(status.Configured), yesNo(status.Reachable), yesNo(status.Authorized),
+			status.Version, yesNo(status.SupportedVersion), strings.Join(strings.Fields(status.Error), " ")}
+		_, err = fmt.Fprintln(tableWriter, strings.Join(row, "\t"))
+		if err != nil {
+			return err
+		}
+	}
+	return tableWriter.Flush()
+}
+
+func yesNo(value bool) string {
+	if value {
+		return "yes"
+	}
+	return "no"
+}
@@ -0,0 +1,52 @@
+package livelog
+
+import (
+	"bytes"
+	"context"
+	"github.com/jfrog/live-logs/internal/constants"
+	"github.com/jfrog/live-logs/internal/model"
+	"github.com/stretchr/testify/require"
+	"testing"
+)
+
+func Test_LiveLogs_DiscoverProducts(t *testing.T) {
+	realGetAllServiceIds := getAllServiceIds
+	realProbeProduct := probeProduct
+	defer func() {
+		getAllServiceIds = realGetAllServiceIds
+		probeProduct = realProbeProduct
+	}()
+	getAllServiceIds = func() []string {
+		return []string{"test-rt"}
+	}
+	probeProduct = func(_ context.Context, productId, serverId string) model.ProductStatus {
+		require.Equal(t, "test-rt", serverId)
+		status := model.ProductStatus{ProductId: productId}
+		if productId == constants.ArtifactoryId {
+			status.Configured, status.Reachable, status.Authorized, status.SupportedVersion = true, true, true, true
+			status.Version = "7.41.0"
+		} else {
+			status.Error = "the url is not configured\nin the server id"
+		}
+		return status
+	}
+
+	s := &Data{}
+	statuses, err := s.DiscoverProducts(context.Background(), "test-rt")
+	require.NoError(t, err)
+	require.Len(t, statuses, 5)
+	require.Equal(t, constants.ArtifactoryId, statuses[0].ProductId)
+	require.True(t, statuses[0].IsAvailable())
+	for _, status := range statuses[1:] {
+		require.False(t, status.IsAvailable())
+	}
+
+	out := &bytes.Buffer{}
+	require.NoError(t, writeProducts(out, statuses[:2]))
+	require.Equal(t, "PRODUCT  CONFIGURED  REACHABLE  AUTHORIZED  VERSION  SUPPORTED  ERROR\n"+
+		"rt       yes         yes        yes         7.41.0   yes        \n"+
+		"xr       no          no         no                   no         the url is not configured in the server id\n", out.String())
+
+	_, err = s.DiscoverProducts(context.Background(), "unknown")
+	require.Error(t, err)
+}
@@ -0,0 +1,137 @@
+// Package recording stores the log data received during a session into a file, and replays it later.
+// A recording is a JSON lines file, with a model.RecordedChunk on each line.
+package recording
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"github.com/jfrog/live-logs/internal/model"
+	"github.com/jfrog/live-logs/internal/stream"
+	"os"
+	"sync"
+	"time"
+)
+
+// The maximum size of a recorded chunk, the log data requests return a few megabytes at most.
+const maxChunkSize = 64 * 1024 * 1024
+
+// Appends the received log data to a recording file. Each chunk is written as soon as it is received, so the
+// recording is complete even when the session is interrupted.
+type Recorder struct {
+	lock sync.Mutex
+	file *os.File
+	now  func() time.Time
+}
+
+func NewRecorder(path string) (*Recorder, error) {
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0600)
+	if err != nil {
+		return nil, err
+	}
+	return &Recorder{file: file, now: time.Now}, nil
+}
+
+// Records a chunk of log data, the chunks without content are skipped.
+func (r *Recorder) Record(source stream.Source, logData model.Data) error {
+	if logData.Content == "" {
+		return nil
+	}
+	chunk := model.RecordedChunk{
+		Time:       r.now().UTC(),
+		ServerId:   source.ServerId,
+		ProductId:  source.ProductId,
+		NodeId:     source.NodeId,
+		LogName:    source.LogName,
+		Content:    logData.Content,
+		PageMarker: logData.PageMarker,
+	}
+	data, err := json.Marshal(chunk)
+	if err != nil {
+		return err
+	}
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	_, err = r.file.Write(append(data, '\n'))
+	return err
+}
+
+func (r *Recorder) Close() error {
+	return r.file.Close()
+}
+
+// Reads the chunks of a recording, in the order they were received.
+func Read(path string) ([]model.RecordedChunk, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	var chunks []model.RecordedChunk
+	scanner := bufio.NewScanner(file)
+	scanner.Buffer(nil, maxChunkSize)
+	for lineNumber := 1; scanner.Scan(); lineNumber++ {
+		if len(scanner.Bytes()) == 0 {
+			continue
+		}
+		var chunk model.RecordedChunk
+		err = json.Unmarshal(scanner.Bytes(), &chunk)
+		if err != nil {
+			return nil, fmt.Errorf("invalid recording %s, line %d: %w", path, lineNumber, err)
+		}
+		chunks = append(chunks, chunk)
+	}
+	return chunks, scanner.Err()
+}
+
+// Returns the origin of a recorded chunk.
+func SourceOf(chunk model.RecordedChunk) stream.Source {
+	return stream.Source{
+		ServerId:  chunk.ServerId,
+		ProductId: chunk.ProductId,
+		NodeId:    chunk.NodeId,
+		LogName:   chunk.LogName,
+	}
+}
+
+// Writes the lines of the recorded chunks, each chunk after the delay that preceded it during the recording, divided
+// by the speed. A speed of zero replays the chunks without delays. The lines of each origin are buffered separately,
+// as during the recording, and a trailing partial line is written once the replay ends or the context is done.
+func Replay(ctx context.Context, chunks []model.RecordedChunk, speed float64, write func(lines ...stream.Line) error) error {
+	if speed < 0 {
+		return fmt.Errorf("speed must not be negative, received: %v", speed)
+	}
+	lineBuffers := map[stream.Source]*stream.LineBuffer{}
+	var sources []stream.Source
+replayChunks:
+	for i, chunk := range chunks {
+		if i > 0 && speed > 0 {
+			delay := time.Duration(float64(chunk.Time.Sub(chunks[i-1].Time)) / speed)
+			select {
+			case <-ctx.Done():
+				break replayChunks
+			case <-time.After(delay):
+			}
+		}
+		source := SourceOf(chunk)
+		lineBuffer, ok := lineBuffers[source]
+		if !ok {
+			lineBuffer = stream.NewLineBuffer(source)
+			lineBuffers[source] = lineBuffer
+			sources = append(sources, source)
+		}
+		err := write(lineBuffer.Write([]byte(chunk.Content))...)
+		if err != nil {
+			return err
+		}
+	}
+	for _, source := range sources {
+		err := write(lineBuffers[source].Flush()...)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,102 @@
+package recording
+
+import (
+	"context"
+	"github.com/jfrog/live-logs/internal/model"
+	"github.com/jfrog/live-logs/internal/stream"
+	"github.com/stretchr/testify/require"
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestRecorder(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "session.jsonl")
+	recorder, err := NewRecorder(path)
+	require.NoError(t, err)
+	start := time.Date(2021, 3, 4, 10, 0, 0, 0, time.UTC)
+	recorder.now = func() time.Time { return start }
+
+	source := stream.Source{ServerId: "test-rt", ProductId: "rt", NodeId: "node-1", LogName: "one.log"}
+	require.NoError(t, recorder.Record(source, model.Data{Content: "first line\n", PageMarker: 11}))
+	require.NoError(t, recorder.Record(source, model.Data{PageMarker: 11}))
+	require.NoError(t, recorder.Record(source, model.Data{Content: "second", PageMarker: 17}))
+	require.NoError(t, recorder.Close())
+
+	chunks, err := Read(path)
+	require.NoError(t, err)
+	require.Equal(t, []model.RecordedChunk{
+		{Time: start, ServerId: "test-rt", ProductId: "rt", NodeId: "node-1", LogName: "one.log", Content: "first line\n", PageMarker: 11},
+		{Time: start, ServerId: "test-rt", ProductId: "rt", NodeId: "node-1", LogName: "one.log", Content: "second", PageMarker: 17},
+	}, chunks)
+	require.Equal(t, source, SourceOf(chunks[0]))
+}
+
+func TestRead_Invalid(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "session.jsonl")
+	require.NoError(t, ioutil.WriteFile(path, []byte("{}\nnot json\n"), 0600))
+	_, err := Read(path)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "line 2")
+}
+
+func TestReplay(t *testing.T) {
+	start := time.Date(2021, 3, 4, 10, 0, 0, 0, time.UTC)
+	chunks := []model.RecordedChunk{
+		{Time: start, NodeId: "node-1", LogName: "one.log", Content: "first li"},
+		{Time: start.Add(time.Second), NodeId: "node-2", LogName: "one.log", Content: "other line\n"},
+		{Time: start.Add(2 * time.Second), NodeId: "node-1", LogName: "one.log", Content: "ne\nsecond"},
+	}
+	node1 := stream.Source{NodeId: "node-1", LogName: "one.log"}
+	node2 := stream.Source{NodeId: "node-2", LogName: "one.log"}
+	wantLines := []stream.Line{
+		{Source: node2, Text: "other line"},
+		{Source: node1, Text: "first line"},
+		{Source: node1, Text: "second", Partial: true},
+	}
+	tests := []struct {
+		name         string
+		speed        float64
+		minDuration  time.Duration
+		wantErrorMsg string
+	}{
+		{name: "no delay", speed: 0},
+		{name: "accelerated", speed: 40, minDuration: 50 * time.Millisecond},
+		{name: "negative speed", speed: -1, wantErrorMsg: "speed must not be negative, received: -1"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var lines []stream.Line
+			start := time.Now()
+			err := Replay(context.Background(), chunks, tt.speed, func(written ...stream.Line) error {
+				lines = append(lines, written...)
+				return nil
+			})
+			if tt.wantErrorMsg != "" {
+				require.EqualError(t, err, tt.wantErrorMsg)
+				return
+			}
+			require.NoError(t, err)
+			require.GreaterOrEqual(t, int64(time.Since(start)), int64(tt.minDuration))
+			require.Equal(t, wantLines, lines)
+		})
+	}
+}
+
+func TestReplay_Cancel(t *testing.T) {
+	start := time.Date(2021, 3, 4, 10, 0, 0, 0, time.UTC)
+	chunks := []model.RecordedChunk{
+		{Time: start, NodeId: "node-1", LogName: "one.log", Content: "first"},
+		{Time: start.Add(time.Hour), NodeId: "node-1", LogName: "one.log", Content: " line\n"},
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	var lines []stream.Line
+	err := Replay(ctx, chunks, 1, func(written ...stream.Line) error {
+		lines = append(lines, written...)
+		return nil
+	})
+	require.NoError(t, err)
+	require.Equal(t, []stream.Line{{Source: stream.Source{NodeId: "node-1", LogName: "one.log"}, Text: "first", Partial: true}}, lines)
+}
@@ -0,0 +1,200 @@
+package redact
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"github.com/jfrog/live-logs/internal/stream"
+	"io/ioutil"
+	"regexp"
+	"strings"
+)
+
+const (
+	redactedValue   = "[REDACTED]"
+	pseudonymLength = 12
+)
+
+type Rule struct {
+	Name    string
+	Pattern *regexp.Regexp
+	// The sub match to replace, the whole match is replaced when set to 0.
+	Group int
+}
+
+// The format of a user defined rule, as read from a rules file.
+type ruleDefinition struct {
+	Name    string `json:"name"`
+	Pattern string `json:"pattern"`
+	Group   int    `json:"group,omitempty"`
+}
+
+type Redactor struct {
+	rules        []Rule
+	pseudonymize bool
+	salt         []byte
+}
+
+// Creates a redactor applying the given rules in order.
+// When pseudonymize is set, every redacted value is replaced with a keyed hash of itself, so the same value is always
+// replaced with the same pseudonym, otherwise it is replaced with a fixed placeholder.
+func NewRedactor(rules []Rule, pseudonymize bool, salt string) *Redactor {
+	return &Redactor{
+		rules:        rules,
+		pseudonymize: pseudonymize,
+		salt:         []byte(salt),
+	}
+}
+
+// Returns the rules for secrets and personal data commonly found in the JFrog products logs.
+func BuiltInRules() []Rule {
+	return []Rule{
+		{Name: "bearer", Pattern: regexp.MustCompile(`(?i)(bearer\s+)([A-Za-z0-9\-._~+/]+=*)`), Group: 2},
+		{Name: "token", Pattern: regexp.MustCompile(`(?i)([?&](?:api_?key|access_?token|token)=)([^&\s|]+)`), Group: 2},
+		{Name: "password", Pattern: regexp.MustCompile(`(?i)([?&](?:password|passwd|pwd|secret)=)([^&\s|]+)`), Group: 2},
+		{Name: "api-key", Pattern: regexp.MustCompile(`\bAKC[A-Za-z0-9]{10,}`)},
+		{Name: "access-token", Pattern: regexp.MustCompile(`\beyJ[A-Za-z0-9_-]+\.[A-Za-z0-9_-]+\.[A-Za-z0-9_-]*`)},
+		{Name: "email", Pattern: regexp.MustCompile(`[A-Za-z0-9._%+-]+@[A-Za-z0-9.-]+\.[A-Za-z]{2,}`)},
+		{Name: "ipv4", Pattern: regexp.MustCompile(`\b(?:(?:25[0-5]|2[0-4]\d|1?\d?\d)\.){3}(?:25[0-5]|2[0-4]\d|1?\d?\d)\b`)},
+		{Name: "ipv6", Pattern: regexp.MustCompile(`\b(?:(?:[0-9A-Fa-f]{1,4}:){7}[0-9A-Fa-f]{1,4}|(?:[0-9A-Fa-f]{1,4}:){1,6}(?::[0-9A-Fa-f]{1,4}){1,6})\b`)},
+		// The user name is the fourth field of the request logs, for example:
+		// 2021-03-25T04:00:00.006Z|d76675e362ffbd6a|10.0.0.1|admin|GET|/api/system/ping|200|-1|2|3|JFrog-Router/7.17.0
+		{Name: "user", Pattern: regexp.MustCompile(`(?m)^(\d{4}-\d{2}-\d{2}T[^|]*\|[^|]*\|[^|]*\|)([^|\n]+)`), Group: 2},
+	}
+}
+
+// Reads user defined rules from a JSON file, containing a list of objects with a name, a pattern and an optional group.
+func LoadRules(rulesFilePath string) ([]Rule, error) {
+	content, err := ioutil.ReadFile(rulesFilePath)
+	if err != nil {
+		return nil, err
+	}
+	var definitions []ruleDefinition
+	err = json.Unmarshal(content, &definitions)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse the redaction rules file %s: %w", rulesFilePath, err)
+	}
+
+	rules := make([]Rule, 0, len(definitions))
+	for _, definition := range definitions {
+		if definition.Name == "" {
+			return nil, fmt.Errorf("a redaction rule without a name was found in %s", rulesFilePath)
+		}
+		pattern, err := regexp.Compile(definition.Pattern)
+		if err != nil {
+			return nil, fmt.Errorf("invalid pattern for redaction rule '%s': %w", definition.Name, err)
+		}
+		if definition.Group < 0 || definition.Group > pattern.NumSubexp() {
+			return nil, fmt.Errorf("invalid group %d for redaction rule '%s', the pattern has %d groups", definition.Group, definition.Name, pattern.NumSubexp())
+		}
+		rules = append(rules, Rule{Name: definition.Name, Pattern: pattern, Group: definition.Group})
+	}
+	return rules, nil
+}
+
+// Returns the text with every match of every rule replaced.
+func (r *Redactor) Redact(text string) string {
+	for _, rule := range r.rules {
+		text = r.applyRule(rule, text)
+	}
+	return text
+}
+
+func (r *Redactor) applyRule(rule Rule, text string) string {
+	matches := rule.Pattern.FindAllStringSubmatchIndex(text, -1)
+	if len(matches) == 0 {
+		return text
+	}
+	var result strings.Builder
+	lastIndex := 0
+	for _, match := range matches {
+		start, end := match[2*rule.Group], match[2*rule.Group+1]
+		// An optional group which did not participate in the match.
+		if start < 0 {
+			continue
+		}
+		result.WriteString(text[lastIndex:start])
+		result.WriteString(r.replacement(rule.Name, text[start:end]))
+		lastIndex = end
+	}
+	result.WriteString(text[lastIndex:])
+	return result.String()
+}
+
+func (r *Redactor) replacement(ruleName, value string) string {
+	if !r.pseudonymize {
+		return redactedValue
+	}
+	mac := hmac.New(sha256.New, r.salt)
+	mac.Write([]byte(value))
+	return "[" + ruleName + ":" + hex.EncodeToString(mac.Sum(nil))[:pseudonymLength] + "]"
+}
+
+// Creates a pipeline stage redacting the lines.
+func (r *Redactor) NewLineRedactor() *LineRedactor {
+	return &LineRedactor{redactor: r, partials: map[stream.Source]stream.Line{}}
+}
+
+// Redacts the content of the lines. A value to redact may be split between a line flushed before its end was received
+// and the rest of the line, so the partial lines are held back, and redacted along with their rest once the line is
+// complete, or once the stream is over.
+type LineRedactor struct {
+	redactor *Redactor
+	// The start of the line of each source which was flushed before its end was received.
+	partials map[stream.Source]stream.Line
+	order    []stream.Source
+	released []stream.Line
+}
+
+func (l *LineRedactor) Process(line *stream.Line) bool {
+	partial, held := l.partials[line.Source]
+	if held {
+		l.remove(line.Source)
+		if line.Continuation {
+			line.Text = partial.Text + line.Text
+			line.Continuation = partial.Continuation
+		} else {
+			// The rest of the held line is missing, it is released as is.
+			partial.Text = l.redactor.Redact(partial.Text)
+			l.released = append(l.released, partial)
+		}
+	}
+	if line.Partial {
+		l.partials[line.Source] = *line
+		l.order = append(l.order, line.Source)
+		return false
+	}
+	line.Text = l.redactor.Redact(line.Text)
+	return true
+}
+
+func (l *LineRedactor) remove(source stream.Source) {
+	delete(l.partials, source)
+	for i, heldSource := range l.order {
+		if heldSource == source {
+			l.order = append(l.order[:i], l.order[i+1:]...)
+			return
+		}
+	}
+}
+
+func (l *LineRedactor) Released() []stream.Line {
+	released := l.released
+	l.released = nil
+	return released
+}
+
+// Releases the held partial lines, once the stream is over.
+func (l *LineRedactor) Flush() []stream.Line {
+	released := l.Released()
+	for _, source := range l.order {
+		partial := l.partials[source]
+		partial.Text = l.redactor.Redact(partial.Text)
+		released = append(released, partial)
+	}
+	l.partials = map[stream.Source]stream.Line{}
+	l.order = nil
+	return released
+}
@@ -0,0 +1,198 @@
+// Package relay fans the lines of the live log streams out to any number of local clients, over Server-Sent Events
+// and WebSocket, and serves a minimal HTML viewer. The streams are polled once, whatever the number of clients.
+package relay
+
+import (
+	"fmt"
+	"github.com/jfrog/live-logs/internal/format"
+	"github.com/jfrog/live-logs/internal/stream"
+	"net/url"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	DefaultListenAddress = "localhost:8080"
+	DefaultHistorySize   = 1000
+)
+
+// The number of events queued for a client, a client which falls further behind is disconnected. Overridden by
+// the tests.
+var subscriberBufferSize = 1000
+
+// A relayed log line.
+type Event struct {
+	// Increases with each line, a reconnecting client passes the last id it received to get the lines it missed.
+	Id      int64     `json:"id"`
+	Time    time.Time `json:"time"`
+	Server  string    `json:"server"`
+	Product string    `json:"product"`
+	Node    string    `json:"node"`
+	Log     string    `json:"log"`
+	// The level of the service log lines, empty for the other lines.
+	Level string `json:"level,omitempty"`
+	Line  string `json:"line"`
+}
+
+// Selects the events sent to a client, the empty fields select every event.
+type Filter struct {
+	Nodes []string
+	Logs  []string
+	Match *regexp.Regexp
+}
+
+// Parses the node, log and match query parameters, the node and log parameters are comma separated lists and match
+// is a regular expression.
+func ParseFilter(query url.Values) (Filter, error) {
+	var filter Filter
+	if nodes := query.Get("node"); nodes != "" {
+		filter.Nodes = strings.Split(nodes, ",")
+	}
+	if logs := query.Get("log"); logs != "" {
+		filter.Logs = strings.Split(logs, ",")
+	}
+	if match := query.Get("match"); match != "" {
+		var err error
+		filter.Match, err = regexp.Compile(match)
+		if err != nil {
+			return Filter{}, fmt.Errorf("invalid match expression: %w", err)
+		}
+	}
+	return filter, nil
+}
+
+func (f Filter) matches(event Event) bool {
+	return (len(f.Nodes) == 0 || contains(f.Nodes, event.Node)) &&
+		(len(f.Logs) == 0 || contains(f.Logs, event.Log)) &&
+		(f.Match == nil || f.Match.MatchString(event.Line))
+}
+
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
+
+// A pipeline stage publishing each line to the subscribed clients, the lines are passed on unchanged. The last lines
+// are kept, so a client which connects or reconnects starts with them.
+type Hub struct {
+	historySize int
+	now         func() time.Time
+
+	lock        sync.Mutex
+	lastId      int64
+	history     []Event
+	subscribers map[*Subscription]bool
+	closed      bool
+}
+
+// The events of a client, the channel is closed when the client falls behind, is unsubscribed or the hub is closed.
+type Subscription struct {
+	Events <-chan Event
+	events chan Event
+	filter Filter
+}
+
+// Creates a hub keeping the last historySize lines.
+func NewHub(historySize int) *Hub {
+	return &Hub{historySize: historySize, now: time.Now, subscribers: map[*Subscription]bool{}}
+}
+
+func (h *Hub) Process(line *stream.Line) bool {
+	event := Event{
+		Time:    h.now().UTC(),
+		Server:  line.Source.ServerId,
+		Product: line.Source.ProductId,
+		Node:    line.Source.NodeId,
+		Log:     line.Source.LogName,
+		Line:    line.Text,
+	}
+	if record := format.Parse(*line); record.IsService {
+		event.Level = record.Level
+	}
+	h.publish(event)
+	return true
+}
+
+func (h *Hub) publish(event Event) {
+	h.lock.Lock()
+	defer h.lock.Unlock()
+	if h.closed {
+		return
+	}
+	h.lastId++
+	event.Id = h.lastId
+	if h.historySize > 0 {
+		if len(h.history) == h.historySize {
+			h.history = append(h.history[:0], h.history[1:]...)
+		}
+		h.history = append(h.history, event)
+	}
+	for subscription := range h.subscribers {
+		if !subscription.filter.matches(event) {
+			continue
+		}
+		select {
+		case subscription.events <- event:
+		default:
+			// The client is too slow, it is disconnected rather than slowing down the other clients.
+			h.remove(subscription)
+		}
+	}
+}
+
+// Subscribes a client to the events matching the filter. The kept events following lastId are returned, and are
+// followed by the events of the subscription without gap.
+func (h *Hub) Subscribe(filter Filter, lastId int64) (*Subscription, []Event) {
+	events := make(chan Event, subscriberBufferSize)
+	subscription := &Subscription{Events: events, events: events, filter: filter}
+	h.lock.Lock()
+	defer h.lock.Unlock()
+	if h.closed {
+		close(events)
+		return subscription, nil
+	}
+	h.subscribers[subscription] = true
+	var missed []Event
+	for _, event := range h.history {
+		if event.Id > lastId && filter.matches(event) {
+			missed = append(missed, event)
+		}
+	}
+	return subscription, missed
+}
+
+func (h *Hub) Unsubscribe(subscription *Subscription) {
+	h.lock.Lock()
+	defer h.lock.Unlock()
+	h.remove(subscription)
+}
+
+func (h *Hub) remove(subscription *Subscription) {
+	if h.subscribers[subscription] {
+		delete(h.subscribers, subscription)
+		close(subscription.events)
+	}
+}
+
+// Returns the number of subscribed clients.
+func (h *Hub) Clients() int {
+	h.lock.Lock()
+	defer h.lock.Unlock()
+	return len(h.subscribers)
+}
+
+// Disconnects all the clients, the following lines are no longer published.
+func (h *Hub) Close() {
+	h.lock.Lock()
+	defer h.lock.Unlock()
+	h.closed = true
+	for subscription := range h.subscribers {
+		h.remove(subscription)
+	}
+}
@@ -0,0 +1,127 @@
+package relay
+
+import (
+	"github.com/jfrog/live-logs/internal/stream"
+	"github.com/stretchr/testify/require"
+	"net/url"
+	"testing"
+)
+
+var (
+	node1Source = stream.Source{ServerId: "local-rt", ProductId: "rt", NodeId: "node1", LogName: "artifactory-service.log"}
+	node2Source = stream.Source{ServerId: "local-rt", ProductId: "rt", NodeId: "node2", LogName: "artifactory-request.log"}
+)
+
+func publish(hub *Hub, source stream.Source, texts ...string) {
+	for _, text := range texts {
+		line := stream.Line{Source: source, Text: text}
+		hub.Process(&line)
+	}
+}
+
+func lines(events []Event) []string {
+	var result []string
+	for _, event := range events {
+		result = append(result, event.Line)
+	}
+	return result
+}
+
+func receive(t *testing.T, subscription *Subscription, count int) []Event {
+	var events []Event
+	for i := 0; i < count; i++ {
+		event, ok := <-subscription.Events
+		require.True(t, ok)
+		events = append(events, event)
+	}
+	return events
+}
+
+func TestHub(t *testing.T) {
+	hub := NewHub(3)
+	line := stream.Line{Source: node1Source, Text: "2021-03-25T04:00:00.006Z [jfrt ] [WARN ] [d76675e362ffbd6a] [GarbageCollector:66] [art-exec-11 ] - Starting GC"}
+	require.True(t, hub.Process(&line))
+	publish(hub, node1Source, "two", "three", "four")
+
+	// The last lines are kept.
+	subscription, missed := hub.Subscribe(Filter{}, 0)
+	require.Equal(t, []string{"two", "three", "four"}, lines(missed))
+	require.Equal(t, int64(2), missed[0].Id)
+
+	// A reconnecting client gets the lines following the last one it received.
+	_, missed = hub.Subscribe(Filter{}, 3)
+	require.Equal(t, []string{"four"}, lines(missed))
+
+	other, _ := hub.Subscribe(Filter{}, 4)
+	publish(hub, node2Source, "five")
+	require.Equal(t, []string{"five"}, lines(receive(t, subscription, 1)))
+	event := receive(t, other, 1)[0]
+	require.Equal(t, int64(5), event.Id)
+	require.Equal(t, "node2", event.Node)
+	require.Equal(t, "artifactory-request.log", event.Log)
+	require.Equal(t, 3, hub.Clients())
+
+	hub.Unsubscribe(other)
+	_, ok := <-other.Events
+	require.False(t, ok)
+	hub.Close()
+	_, ok = <-subscription.Events
+	require.False(t, ok)
+	require.Zero(t, hub.Clients())
+}
+
+func TestHub_Level(t *testing.T) {
+	hub := NewHub(1)
+	publish(hub, node1Source, "2021-03-25T04:00:00.006Z [jfrt ] [WARN ] [d76675e362ffbd6a] [GarbageCollector:66] [art-exec-11 ] - Starting GC")
+	_, missed := hub.Subscribe(Filter{}, 0)
+	require.Equal(t, "WARN", missed[0].Level)
+}
+
+func TestHub_SlowClient(t *testing.T) {
+	realSubscriberBufferSize := subscriberBufferSize
+	defer func() { subscriberBufferSize = realSubscriberBufferSize }()
+	subscriberBufferSize = 2
+
+	hub := NewHub(0)
+	slow, _ := hub.Subscribe(Filter{}, 0)
+	publish(hub, node1Source, "one", "two", "three")
+	require.Zero(t, hub.Clients())
+	require.Equal(t, []string{"one", "two"}, lines(receive(t, slow, 2)))
+	_, ok := <-slow.Events
+	require.False(t, ok)
+}
+
+func TestParseFilter(t *testing.T) {
+	tests := []struct {
+		name      string
+		query     string
+		want      []string
+		wantError string
+	}{
+		{name: "all", query: "", want: []string{"one", "two", "three"}},
+		{name: "node", query: "node=node2", want: []string{"three"}},
+		{name: "nodes", query: "node=node1,node2", want: []string{"one", "two", "three"}},
+		{name: "log", query: "log=artifactory-service.log", want: []string{"one", "two"}},
+		{name: "match", query: "match=^t", want: []string{"two", "three"}},
+		{name: "node and match", query: "node=node1&match=o$", want: []string{"two"}},
+		{name: "invalid match", query: "match=(", wantError: "invalid match expression"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			query, err := url.ParseQuery(tt.query)
+			require.NoError(t, err)
+			filter, err := ParseFilter(query)
+			if tt.wantError != "" {
+				require.Error(t, err)
+				require.Contains(t, err.Error(), tt.wantError)
+				return
+			}
+			require.NoError(t, err)
+			hub := NewHub(10)
+			publish(hub, node1Source, "one", "two")
+			publish(hub, node2Source, "three")
+			_, missed := hub.Subscribe(filter, 0)
+			require.Equal(t, tt.want, lines(missed))
+		})
+	}
+}
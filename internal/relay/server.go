@@ -0,0 +1,204 @@
+package relay
+
+import (
+	"encoding/json"
+	"fmt"
+	"golang.org/x/net/websocket"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// The delays applied to the client connections, overridden by the tests.
+var (
+	// The interval of the comments sent to the idle Server-Sent Events clients, so the proxies keep the connection.
+	heartbeatInterval = 15 * time.Second
+	// The maximum time to send an event to a WebSocket client.
+	sendTimeout = 10 * time.Second
+)
+
+// Creates the handler serving the viewer on /, the events as Server-Sent Events on /events, and as WebSocket
+// text messages on /ws. Each event is a JSON Event, and the events can be filtered with the node, log and match query
+// parameters, see ParseFilter. The requests for another host than the one of the listen address are rejected, see
+// checkHost.
+func NewHandler(hub *Hub, listenAddress string) http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/" {
+			http.NotFound(w, r)
+			return
+		}
+		w.Header().Set("Content-Type", "text/html; charset=utf-8")
+		_, _ = w.Write([]byte(viewerHtml))
+	})
+	mux.HandleFunc("/events", func(w http.ResponseWriter, r *http.Request) {
+		serveEvents(hub, w, r)
+	})
+	mux.Handle("/ws", websocket.Server{
+		Handshake: checkOrigin,
+		Handler: func(conn *websocket.Conn) {
+			serveWebSocket(hub, conn)
+		},
+	})
+	return checkHost(listenAddress, mux)
+}
+
+// Rejects the requests whose host is not localhost or the host of the listen address, so that a site whose name is
+// resolved to the address of the server, by DNS rebinding, cannot read the logs through the browser of a user reaching
+// the server. When listening on all the interfaces, the IP addresses are also accepted, as such a site has a name.
+func checkHost(listenAddress string, handler http.Handler) http.Handler {
+	listenHost, _, err := net.SplitHostPort(listenAddress)
+	if err != nil {
+		listenHost = listenAddress
+	}
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !isAllowedHost(listenHost, r.Host) {
+			http.Error(w, fmt.Sprintf("the host %s is not allowed", r.Host), http.StatusForbidden)
+			return
+		}
+		handler.ServeHTTP(w, r)
+	})
+}
+
+func isAllowedHost(listenHost, requestHost string) bool {
+	host, _, err := net.SplitHostPort(requestHost)
+	if err != nil {
+		host = strings.TrimSuffix(strings.TrimPrefix(requestHost, "["), "]")
+	}
+	if strings.EqualFold(host, "localhost") || strings.EqualFold(host, listenHost) {
+		return true
+	}
+	ip := net.ParseIP(host)
+	if ip == nil {
+		return false
+	}
+	if ip.IsLoopback() {
+		return true
+	}
+	listenIp := net.ParseIP(listenHost)
+	return listenHost == "" || listenIp != nil && listenIp.IsUnspecified()
+}
+
+// Accepts the WebSocket connections of the viewer and of the clients which are not browsers. The browsers send the
+// origin of the page opening the connection, a page of another site is rejected, so that it cannot read the logs
+// through the browser of a user reaching the server. The other clients may not send an origin.
+func checkOrigin(config *websocket.Config, r *http.Request) error {
+	origin, err := websocket.Origin(config, r)
+	if err != nil {
+		return err
+	}
+	if origin != nil && origin.Host != r.Host {
+		return fmt.Errorf("the origin %s is not allowed", origin)
+	}
+	return nil
+}
+
+// Streams the events as Server-Sent Events. A reconnecting EventSource passes the id of the last event it received
+// in the Last-Event-ID header, and gets the events it missed while they are kept.
+func serveEvents(hub *Hub, w http.ResponseWriter, r *http.Request) {
+	filter, err := ParseFilter(r.URL.Query())
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		http.Error(w, "streaming is not supported", http.StatusInternalServerError)
+		return
+	}
+	subscription, missed := hub.Subscribe(filter, lastEventId(r))
+	defer hub.Unsubscribe(subscription)
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("X-Accel-Buffering", "no")
+	w.WriteHeader(http.StatusOK)
+	for _, event := range missed {
+		if writeEvent(w, event) != nil {
+			return
+		}
+	}
+	flusher.Flush()
+
+	heartbeat := time.NewTicker(heartbeatInterval)
+	defer heartbeat.Stop()
+	for {
+		select {
+		case <-r.Context().Done():
+			return
+		case <-heartbeat.C:
+			if _, err := fmt.Fprint(w, ": heartbeat\n\n"); err != nil {
+				return
+			}
+		case event, ok := <-subscription.Events:
+			if !ok {
+				return
+			}
+			if writeEvent(w, event) != nil {
+				return
+			}
+		}
+		flusher.Flush()
+	}
+}
+
+func writeEvent(w http.ResponseWriter, event Event) error {
+	data, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Fprintf(w, "id: %d\ndata: %s\n\n", event.Id, data)
+	return err
+}
+
+// Returns the id of the last event received by the client, from the Last-Event-ID header or the last_event_id
+// query parameter, zero when it received none.
+func lastEventId(r *http.Request) int64 {
+	value := r.Header.Get("Last-Event-ID")
+	if value == "" {
+		value = r.URL.Query().Get("last_event_id")
+	}
+	id, _ := strconv.ParseInt(value, 10, 64)
+	return id
+}
+
+// Sends the events as WebSocket text messages, until the client closes the connection.
+func serveWebSocket(hub *Hub, conn *websocket.Conn) {
+	filter, err := ParseFilter(conn.Request().URL.Query())
+	if err != nil {
+		_ = websocket.Message.Send(conn, err.Error())
+		return
+	}
+	subscription, missed := hub.Subscribe(filter, lastEventId(conn.Request()))
+	defer hub.Unsubscribe(subscription)
+
+	// The messages of the client are ignored, reading them detects that it closed the connection.
+	closed := make(chan struct{})
+	go func() {
+		defer close(closed)
+		var message string
+		for websocket.Message.Receive(conn, &message) == nil {
+		}
+	}()
+
+	send := func(event Event) bool {
+		return conn.SetWriteDeadline(time.Now().Add(sendTimeout)) == nil && websocket.JSON.Send(conn, event) == nil
+	}
+	for _, event := range missed {
+		if !send(event) {
+			return
+		}
+	}
+	for {
+		select {
+		case <-closed:
+			return
+		case event, ok := <-subscription.Events:
+			if !ok || !send(event) {
+				return
+			}
+		}
+	}
+}
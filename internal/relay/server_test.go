@@ -0,0 +1,216 @@
+package relay
+
+import (
+	"bufio"
+	"encoding/json"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/net/websocket"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+)
+
+// Reads the Server-Sent Events of the response, skipping the comments.
+func readSse(t *testing.T, reader *bufio.Reader, count int) []Event {
+	var events []Event
+	var id string
+	for len(events) < count {
+		text, err := reader.ReadString('\n')
+		require.NoError(t, err)
+		text = strings.TrimSuffix(text, "\n")
+		switch {
+		case strings.HasPrefix(text, "id: "):
+			id = strings.TrimPrefix(text, "id: ")
+		case strings.HasPrefix(text, "data: "):
+			var event Event
+			require.NoError(t, json.Unmarshal([]byte(strings.TrimPrefix(text, "data: ")), &event))
+			require.Equal(t, id, strconv.FormatInt(event.Id, 10))
+			events = append(events, event)
+		}
+	}
+	return events
+}
+
+func waitForClients(t *testing.T, hub *Hub, count int) {
+	require.Eventually(t, func() bool { return hub.Clients() == count }, 10*time.Second, time.Millisecond)
+}
+
+func TestHandler_Events(t *testing.T) {
+	realHeartbeatInterval := heartbeatInterval
+	defer func() { heartbeatInterval = realHeartbeatInterval }()
+	heartbeatInterval = time.Millisecond
+
+	hub := NewHub(10)
+	server := httptest.NewServer(NewHandler(hub, DefaultListenAddress))
+	defer server.Close()
+	publish(hub, node1Source, "one", "two")
+
+	request, err := http.NewRequest(http.MethodGet, server.URL+"/events?node=node1", nil)
+	require.NoError(t, err)
+	request.Header.Set("Last-Event-ID", "1")
+	response, err := http.DefaultClient.Do(request)
+	require.NoError(t, err)
+	defer response.Body.Close()
+	require.Equal(t, "text/event-stream", response.Header.Get("Content-Type"))
+	reader := bufio.NewReader(response.Body)
+	require.Equal(t, []string{"two"}, lines(readSse(t, reader, 1)))
+
+	waitForClients(t, hub, 1)
+	publish(hub, node2Source, "other node")
+	publish(hub, node1Source, "three")
+	require.Equal(t, []string{"three"}, lines(readSse(t, reader, 1)))
+
+	// The stream ends when the hub is closed.
+	hub.Close()
+	_, err = ioutil.ReadAll(reader)
+	require.NoError(t, err)
+}
+
+func TestHandler_Events_InvalidFilter(t *testing.T) {
+	server := httptest.NewServer(NewHandler(NewHub(10), DefaultListenAddress))
+	defer server.Close()
+	response, err := http.Get(server.URL + "/events?match=(")
+	require.NoError(t, err)
+	defer response.Body.Close()
+	require.Equal(t, http.StatusBadRequest, response.StatusCode)
+}
+
+func TestHandler_WebSocket(t *testing.T) {
+	hub := NewHub(10)
+	server := httptest.NewServer(NewHandler(hub, DefaultListenAddress))
+	defer server.Close()
+	publish(hub, node1Source, "one")
+	wsUrl := "ws" + strings.TrimPrefix(server.URL, "http") + "/ws?match=e"
+
+	// Any number of clients receive the same lines.
+	var clients []*websocket.Conn
+	for i := 0; i < 3; i++ {
+		conn, err := websocket.Dial(wsUrl, "", server.URL)
+		require.NoError(t, err)
+		defer conn.Close()
+		clients = append(clients, conn)
+	}
+	waitForClients(t, hub, 3)
+	publish(hub, node2Source, "two", "three")
+	for _, conn := range clients {
+		require.NoError(t, conn.SetReadDeadline(time.Now().Add(10*time.Second)))
+		var first, second Event
+		require.NoError(t, websocket.JSON.Receive(conn, &first))
+		require.NoError(t, websocket.JSON.Receive(conn, &second))
+		require.Equal(t, "one", first.Line)
+		require.Equal(t, "three", second.Line)
+		require.Equal(t, "node2", second.Node)
+	}
+
+	// A client which closes the connection is unsubscribed.
+	require.NoError(t, clients[0].Close())
+	waitForClients(t, hub, 2)
+}
+
+func TestHandler_WebSocket_Origin(t *testing.T) {
+	server := httptest.NewServer(NewHandler(NewHub(10), DefaultListenAddress))
+	defer server.Close()
+	wsUrl := "ws" + strings.TrimPrefix(server.URL, "http") + "/ws"
+
+	tests := []struct {
+		name       string
+		origin     string
+		wantStatus int
+	}{
+		{name: "same origin", origin: server.URL, wantStatus: http.StatusSwitchingProtocols},
+		{name: "no origin", wantStatus: http.StatusSwitchingProtocols},
+		{name: "foreign origin", origin: "http://evil.example", wantStatus: http.StatusForbidden},
+		{name: "foreign port", origin: "http://127.0.0.1:1", wantStatus: http.StatusForbidden},
+		{name: "opaque origin", origin: "null", wantStatus: http.StatusForbidden},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			request, err := http.NewRequest(http.MethodGet, server.URL+"/ws", nil)
+			require.NoError(t, err)
+			request.Header.Set("Connection", "Upgrade")
+			request.Header.Set("Upgrade", "websocket")
+			request.Header.Set("Sec-WebSocket-Version", "13")
+			request.Header.Set("Sec-WebSocket-Key", "dGhlIHNhbXBsZSBub25jZQ==")
+			if tt.origin != "" {
+				request.Header.Set("Origin", tt.origin)
+			}
+			response, err := http.DefaultClient.Do(request)
+			require.NoError(t, err)
+			response.Body.Close()
+			require.Equal(t, tt.wantStatus, response.StatusCode)
+		})
+	}
+
+	_, err := websocket.Dial(wsUrl, "", "http://evil.example")
+	require.Error(t, err)
+}
+
+func TestHandler_Host(t *testing.T) {
+	server := httptest.NewServer(NewHandler(NewHub(10), DefaultListenAddress))
+	defer server.Close()
+
+	for _, path := range []string{"/", "/events?node=%5B", "/ws"} {
+		for host, wantStatus := range map[string]int{"evil.example": http.StatusForbidden, "localhost:8080": 0} {
+			request, err := http.NewRequest(http.MethodGet, server.URL+path, nil)
+			require.NoError(t, err)
+			request.Host = host
+			response, err := http.DefaultClient.Do(request)
+			require.NoError(t, err)
+			response.Body.Close()
+			if wantStatus != 0 {
+				require.Equal(t, wantStatus, response.StatusCode, path)
+			} else {
+				require.NotEqual(t, http.StatusForbidden, response.StatusCode, path)
+			}
+		}
+	}
+}
+
+func TestIsAllowedHost(t *testing.T) {
+	tests := []struct {
+		listenHost  string
+		requestHost string
+		want        bool
+	}{
+		{listenHost: "localhost", requestHost: "localhost:8080", want: true},
+		{listenHost: "localhost", requestHost: "LOCALHOST", want: true},
+		{listenHost: "localhost", requestHost: "127.0.0.1:8080", want: true},
+		{listenHost: "localhost", requestHost: "[::1]:8080", want: true},
+		{listenHost: "localhost", requestHost: "evil.example:8080", want: false},
+		{listenHost: "localhost", requestHost: "10.0.0.5:8080", want: false},
+		{listenHost: "relay.acme.io", requestHost: "relay.acme.io:8080", want: true},
+		{listenHost: "relay.acme.io", requestHost: "evil.example:8080", want: false},
+		{listenHost: "10.0.0.5", requestHost: "10.0.0.5:8080", want: true},
+		{listenHost: "10.0.0.5", requestHost: "10.0.0.6:8080", want: false},
+		{listenHost: "", requestHost: "10.0.0.5:8080", want: true},
+		{listenHost: "0.0.0.0", requestHost: "[fe80::1]:8080", want: true},
+		{listenHost: "", requestHost: "evil.example:8080", want: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.listenHost+" "+tt.requestHost, func(t *testing.T) {
+			require.Equal(t, tt.want, isAllowedHost(tt.listenHost, tt.requestHost))
+		})
+	}
+}
+
+func TestHandler_Viewer(t *testing.T) {
+	server := httptest.NewServer(NewHandler(NewHub(10), DefaultListenAddress))
+	defer server.Close()
+	response, err := http.Get(server.URL)
+	require.NoError(t, err)
+	defer response.Body.Close()
+	require.Equal(t, http.StatusOK, response.StatusCode)
+	require.Equal(t, "text/html; charset=utf-8", response.Header.Get("Content-Type"))
+	body, err := ioutil.ReadAll(response.Body)
+	require.NoError(t, err)
+	require.Contains(t, string(body), `new EventSource("events")`)
+
+	response, err = http.Get(server.URL + "/missing")
+	require.NoError(t, err)
+	response.Body.Close()
+	require.Equal(t, http.StatusNotFound, response.StatusCode)
+}
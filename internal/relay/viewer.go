@@ -0,0 +1,148 @@
+package relay
+
+// The viewer page, following the events with an EventSource. It keeps the last lines in the page, and filters them
+// by node, log file, level and regular expression without reconnecting.
+const viewerHtml = `<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<title>JFrog Live Logs</title>
+<style>
+  body { margin: 0; font-family: sans-serif; background: #1e1e1e; color: #d4d4d4; }
+  header { position: sticky; top: 0; display: flex; flex-wrap: wrap; gap: 8px; align-items: center; padding: 8px; background: #2d2d2d; }
+  header input[type=text] { flex: 1; min-width: 200px; }
+  #status { font-size: 12px; color: #9d9d9d; }
+  #lines { margin: 0; padding: 8px; font: 12px monospace; white-space: pre-wrap; word-break: break-all; }
+  .origin { color: #4fc1ff; }
+  .ERROR, .FATAL { color: #f48771; }
+  .WARN { color: #cca700; }
+  .DEBUG, .TRACE { color: #808080; }
+  .hidden { display: none; }
+</style>
+</head>
+<body>
+<header>
+  <input id="match" type="text" placeholder="Filter, a regular expression" autofocus>
+  <select id="node"><option value="">All nodes</option></select>
+  <select id="log"><option value="">All logs</option></select>
+  <select id="level">
+    <option value="">All levels</option>
+    <option value="ERROR">ERROR</option>
+    <option value="WARN">WARN and above</option>
+    <option value="INFO">INFO and above</option>
+  </select>
+  <label><input id="follow" type="checkbox" checked> Follow</label>
+  <button id="pause">Pause</button>
+  <button id="clear">Clear</button>
+  <span id="status">Connecting...</span>
+</header>
+<pre id="lines"></pre>
+<script>
+  var maxLines = 5000;
+  var levelRanks = { TRACE: 0, DEBUG: 1, INFO: 2, WARN: 3, ERROR: 4, FATAL: 5 };
+  var lines = document.getElementById("lines");
+  var status = document.getElementById("status");
+  var controls = {};
+  ["match", "node", "log", "level", "follow"].forEach(function (id) { controls[id] = document.getElementById(id); });
+  var paused = false;
+  var pending = [];
+  var matcher = null;
+
+  function addOption(select, value) {
+    for (var i = 0; i < select.options.length; i++) {
+      if (select.options[i].value === value) {
+        return;
+      }
+    }
+    var option = document.createElement("option");
+    option.value = option.textContent = value;
+    select.appendChild(option);
+  }
+
+  function isShown(element) {
+    var event = element.event;
+    if (controls.node.value && event.node !== controls.node.value) {
+      return false;
+    }
+    if (controls.log.value && event.log !== controls.log.value) {
+      return false;
+    }
+    if (controls.level.value && !(levelRanks[event.level] >= levelRanks[controls.level.value])) {
+      return false;
+    }
+    return !matcher || matcher.test(event.line);
+  }
+
+  function append(event) {
+    addOption(controls.node, event.node);
+    addOption(controls.log, event.log);
+    var element = document.createElement("div");
+    element.event = event;
+    if (event.level) {
+      element.className = event.level.trim();
+    }
+    var origin = document.createElement("span");
+    origin.className = "origin";
+    origin.textContent = "[" + event.node + " " + event.log + "] ";
+    element.appendChild(origin);
+    element.appendChild(document.createTextNode(event.line));
+    element.classList.toggle("hidden", !isShown(element));
+    lines.appendChild(element);
+    while (lines.childNodes.length > maxLines) {
+      lines.removeChild(lines.firstChild);
+    }
+  }
+
+  function refilter() {
+    try {
+      matcher = controls.match.value ? new RegExp(controls.match.value, "i") : null;
+      controls.match.style.outline = "";
+    } catch (e) {
+      controls.match.style.outline = "1px solid red";
+      return;
+    }
+    for (var element = lines.firstChild; element; element = element.nextSibling) {
+      element.classList.toggle("hidden", !isShown(element));
+    }
+    scroll();
+  }
+
+  function scroll() {
+    if (controls.follow.checked) {
+      window.scrollTo(0, document.body.scrollHeight);
+    }
+  }
+
+  ["match", "node", "log", "level"].forEach(function (id) {
+    controls[id].addEventListener(id === "match" ? "input" : "change", refilter);
+  });
+  document.getElementById("clear").addEventListener("click", function () { lines.textContent = ""; });
+  document.getElementById("pause").addEventListener("click", function (click) {
+    paused = !paused;
+    click.target.textContent = paused ? "Resume" : "Pause";
+    if (!paused) {
+      pending.forEach(append);
+      pending = [];
+      scroll();
+    }
+  });
+
+  var source = new EventSource("events");
+  source.onopen = function () { status.textContent = "Connected"; };
+  source.onerror = function () { status.textContent = "Reconnecting..."; };
+  source.onmessage = function (message) {
+    var event = JSON.parse(message.data);
+    if (paused) {
+      pending.push(event);
+      if (pending.length > maxLines) {
+        pending.shift();
+      }
+      return;
+    }
+    append(event);
+    scroll();
+  };
+</script>
+</body>
+</html>
+`
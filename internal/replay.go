@@ -0,0 +1,39 @@
+package livelog
+
+import (
+	"context"
+	"github.com/jfrog/live-logs/internal/model"
+	"github.com/jfrog/live-logs/internal/recording"
+	"github.com/jfrog/live-logs/internal/stream"
+	"io"
+	"os"
+)
+
+// Writes the log data of a recording through the configured output stages, see recording.Replay. Each line is
+// labeled with its server, node or log file when the recording holds several of them.
+func (s *Data) Replay(ctx context.Context, recordingPath string, speed float64) error {
+	return s.replay(ctx, os.Stdout, recordingPath, speed)
+}
+
+func (s *Data) replay(ctx context.Context, output io.Writer, recordingPath string, speed float64) error {
+	chunks, err := recording.Read(recordingPath)
+	if err != nil {
+		return err
+	}
+	pipeline := s.newPipeline(output, replayLabeler(chunks))
+	err = recording.Replay(ctx, chunks, speed, pipeline.Write)
+	if err != nil {
+		return err
+	}
+	return pipeline.Flush()
+}
+
+func replayLabeler(chunks []model.RecordedChunk) stream.Labeler {
+	servers, nodes, logs := map[string]bool{}, map[string]bool{}, map[string]bool{}
+	for _, chunk := range chunks {
+		servers[chunk.ServerId] = true
+		nodes[chunk.NodeId] = true
+		logs[chunk.LogName] = true
+	}
+	return stream.Labeler{Server: len(servers) > 1, Node: len(nodes) > 1, Log: len(logs) > 1}
+}
@@ -0,0 +1,89 @@
+package livelog
+
+import (
+	"bytes"
+	"context"
+	"github.com/jfrog/live-logs/internal/color"
+	"github.com/jfrog/live-logs/internal/dedup"
+	"github.com/jfrog/live-logs/internal/format"
+	"github.com/jfrog/live-logs/internal/model"
+	"github.com/jfrog/live-logs/internal/recording"
+	"github.com/jfrog/live-logs/internal/stream"
+	"github.com/jfrog/live-logs/internal/timerange"
+	"github.com/stretchr/testify/require"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func Test_LiveLogs_Replay(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "session.jsonl")
+	recorder, err := recording.NewRecorder(path)
+	require.NoError(t, err)
+	node1 := stream.Source{ServerId: "test-rt", ProductId: "rt", NodeId: "node-1", LogName: "one.log"}
+	node2 := stream.Source{ServerId: "test-rt", ProductId: "rt", NodeId: "node-2", LogName: "one.log"}
+	require.NoError(t, recorder.Record(node1, model.Data{Content: "first line\n", PageMarker: 11}))
+	require.NoError(t, recorder.Record(node2, model.Data{Content: "other line\n", PageMarker: 11}))
+	require.NoError(t, recorder.Close())
+
+	var output bytes.Buffer
+	err = NewLiveLogs().(*Data).replay(context.Background(), &output, path, 0)
+	require.NoError(t, err)
+	require.Equal(t, "[node-1] first line\n[node-2] other line\n", output.String())
+}
+
+func Test_LiveLogs_Replay_Colors(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "session.jsonl")
+	recorder, err := recording.NewRecorder(path)
+	require.NoError(t, err)
+	source := stream.Source{ServerId: "test-rt", ProductId: "rt", NodeId: "node-1", LogName: "one.log"}
+	require.NoError(t, recorder.Record(source, model.Data{Content: "[ERROR] failure\n", PageMarker: 16}))
+	require.NoError(t, recorder.Close())
+
+	colorizer, err := color.NewColorizer(nil)
+	require.NoError(t, err)
+	liveLogs := NewLiveLogs().(*Data)
+	liveLogs.SetColorizer(colorizer)
+	var output bytes.Buffer
+	err = liveLogs.replay(context.Background(), &output, path, 0)
+	require.NoError(t, err)
+	require.Equal(t, "[\x1b[31mERROR\x1b[0m] failure\n", output.String())
+}
+
+func Test_LiveLogs_Replay_LineFormatter(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "session.jsonl")
+	recorder, err := recording.NewRecorder(path)
+	require.NoError(t, err)
+	node1 := stream.Source{ServerId: "test-rt", ProductId: "rt", NodeId: "node-1", LogName: "one.log"}
+	node2 := stream.Source{ServerId: "test-rt", ProductId: "rt", NodeId: "node-2", LogName: "one.log"}
+	require.NoError(t, recorder.Record(node1, model.Data{Content: "first line\n", PageMarker: 11}))
+	require.NoError(t, recorder.Record(node2, model.Data{Content: "other line\n", PageMarker: 11}))
+	require.NoError(t, recorder.Close())
+
+	lineFormatter, err := format.NewFormatter("{{.Node}}: {{.Message}}")
+	require.NoError(t, err)
+	liveLogs := NewLiveLogs().(*Data)
+	liveLogs.SetLineFormatter(lineFormatter)
+	var output bytes.Buffer
+	err = liveLogs.replay(context.Background(), &output, path, 0)
+	require.NoError(t, err)
+	require.Equal(t, "node-1: first line\nnode-2: other line\n", output.String())
+}
+
+func Test_LiveLogs_Replay_TimeRangeAndDedup(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "session.jsonl")
+	recorder, err := recording.NewRecorder(path)
+	require.NoError(t, err)
+	source := stream.Source{ServerId: "test-rt", ProductId: "rt", NodeId: "node-1", LogName: "one.log"}
+	require.NoError(t, recorder.Record(source, model.Data{Content: "2021-03-25T04:00:00Z started\n" +
+		"2021-03-25T04:30:00Z cache full\n2021-03-25T04:30:01Z cache full\n2021-03-25T04:30:02Z cache full\n", PageMarker: 124}))
+	require.NoError(t, recorder.Close())
+
+	liveLogs := NewLiveLogs().(*Data)
+	liveLogs.SetTimeRange(&timerange.Range{Since: time.Date(2021, 3, 25, 4, 30, 0, 0, time.UTC), LogLocation: time.UTC})
+	liveLogs.SetDeduplication(&dedup.Config{Rules: dedup.BuiltInRules()})
+	var output bytes.Buffer
+	err = liveLogs.replay(context.Background(), &output, path, 0)
+	require.NoError(t, err)
+	require.Equal(t, "2021-03-25T04:30:00Z cache full\n2021-03-25T04:30:02Z cache full [repeated 2 times]\n", output.String())
+}
@@ -0,0 +1,50 @@
+package livelog
+
+import (
+	"context"
+	"fmt"
+	"github.com/jfrog/live-logs/internal/relay"
+	"github.com/jfrog/live-logs/internal/stream"
+	"io/ioutil"
+)
+
+// Resolves the node ids and log file names on the server, and tails the log files into the hub until the context is
+// done, through the configured redaction. Each log file is polled once, whatever the number of clients
+// of the hub. The nodes are resolved once, the nodes which join later are not followed.
+func (s *Data) Serve(ctx context.Context, cliProductId, cliServerId, nodeId, logName string, hub *relay.Hub) error {
+	sources, err := s.resolveSources(ctx, cliProductId, cliServerId, nodeId, logName)
+	if err != nil {
+		return err
+	}
+
+	streamsCtx, cancelStreams := context.WithCancel(ctx)
+	defer cancelStreams()
+	errs := make(chan error, len(sources))
+	for _, source := range sources {
+		serviceLayer, err := s.newStreamServiceLayer(source.NodeId, source.LogName)
+		if err != nil {
+			return err
+		}
+		pipeline := stream.NewPipeline(ioutil.Discard)
+		if s.redactor != nil {
+			pipeline.AddStage(s.redactor.NewLineRedactor())
+		}
+		pipeline.AddStage(hub)
+		go func(source stream.Source) {
+			err := s.tailLines(streamsCtx, serviceLayer, pipeline)
+			if err != nil {
+				err = fmt.Errorf("%s %s: %w", source.NodeId, source.LogName, err)
+			}
+			errs <- err
+		}(source)
+	}
+
+	var firstErr error
+	for range sources {
+		if err := <-errs; err != nil && firstErr == nil {
+			firstErr = err
+			cancelStreams()
+		}
+	}
+	return firstErr
+}
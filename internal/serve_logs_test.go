@@ -0,0 +1,56 @@
+package livelog
+
+import (
+	"context"
+	"github.com/jfrog/live-logs/internal/relay"
+	"github.com/jfrog/live-logs/internal/servicelayer"
+	"github.com/stretchr/testify/require"
+	"sort"
+	"testing"
+	"time"
+)
+
+func Test_LiveLogs_Serve(t *testing.T) {
+	realServiceLayer := newServiceLayer
+	realGetAllServiceIds := getAllServiceIds
+	defer func() {
+		newServiceLayer = realServiceLayer
+		getAllServiceIds = realGetAllServiceIds
+	}()
+	getAllServiceIds = func() []string {
+		return []string{"us-rt"}
+	}
+	newServiceLayer = func(productId string) (servicelayer.ServiceLayer, error) {
+		return &fleetMockServiceLayer{
+			multiLogMockServiceLayer: multiLogMockServiceLayer{mockServiceLayer: mockServiceLayer{t: t}},
+			nodes:                    map[string][]string{"us-rt": {"us-node1", "us-node2"}},
+		}, nil
+	}
+
+	kept := func(hub *relay.Hub) []relay.Event {
+		subscription, events := hub.Subscribe(relay.Filter{}, 0)
+		hub.Unsubscribe(subscription)
+		return events
+	}
+
+	s := NewLiveLogs()
+	s.SetRefreshRateOverride(time.Millisecond)
+	hub := relay.NewHub(10)
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error)
+	go func() {
+		done <- s.Serve(ctx, "rt", "us-rt", "all", "one.log,two.log", hub)
+	}()
+	require.Eventually(t, func() bool { return len(kept(hub)) == 4 }, 10*time.Second, time.Millisecond)
+	cancel()
+	require.NoError(t, <-done)
+
+	var lines []string
+	for _, event := range kept(hub) {
+		lines = append(lines, event.Line)
+	}
+	sort.Strings(lines)
+	require.Equal(t, []string{"us-rt/us-node1/one.log", "us-rt/us-node1/two.log", "us-rt/us-node2/one.log", "us-rt/us-node2/two.log"}, lines)
+
+	require.Error(t, s.Serve(context.Background(), "rt", "us-rt", "eu-node", "one.log", hub))
+}
@@ -4,7 +4,6 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
-	cliCommands "github.com/jfrog/jfrog-cli-core/v2/common/commands"
 	"github.com/jfrog/live-logs/internal/clientlayer"
 	"github.com/jfrog/live-logs/internal/constants"
 	"github.com/jfrog/live-logs/internal/model"
@@ -71,7 +70,7 @@ func (s *ArtifactoryData) GetConfig(ctx context.Context, serverId string) (*mode
 	return &logConfig, nil
 }
 
-func (s *ArtifactoryData) getVersion(ctx context.Context, serverId string) (string, error) {
+func (s *ArtifactoryData) GetVersion(ctx context.Context, serverId string) (string, error) {
 	timeoutCtx, cancelTimeout := context.WithTimeout(ctx, defaultRequestTimeout)
 	defer cancelTimeout()
 
@@ -142,7 +141,7 @@ func (s *ArtifactoryData) GetLogData(ctx context.Context, serverId string) (logD
 }
 
 func (s *ArtifactoryData) getUrl(serverId string)(url string,_ error){
-	confDetails, err := cliCommands.GetConfig(serverId, false)
+	confDetails, err := clientlayer.GetServerDetails(serverId, false)
 	if err != nil {
 		return "", err
 	}
@@ -158,7 +157,7 @@ func (s *ArtifactoryData) ArtifactoryValidations(ctx context.Context, serverId s
 		return nil
 	}
 
-	currentVersion, err := s.getVersion(ctx, serverId)
+	currentVersion, err := s.GetVersion(ctx, serverId)
 	if err != nil {
 		return err
 	}
@@ -4,7 +4,6 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
-	cliCommands "github.com/jfrog/jfrog-cli-core/v2/common/commands"
 	"github.com/jfrog/live-logs/internal/clientlayer"
 	"github.com/jfrog/live-logs/internal/constants"
 	"github.com/jfrog/live-logs/internal/model"
@@ -111,7 +110,7 @@ func (s *DistributionData) GetLogData(ctx context.Context, serverId string) (log
 }
 
 func (s *DistributionData) getConnectionDetails(serverId string)(url string, headers map[string]string,_ error){
-	confDetails, err := cliCommands.GetConfig(serverId, false)
+	confDetails, err := clientlayer.GetServerDetails(serverId, false)
 	if err != nil {
 		return "",nil, err
 	}
@@ -130,7 +129,7 @@ func (s *DistributionData) getConnectionDetails(serverId string)(url string, hea
 	return url,headers, nil
 }
 
-func (s *DistributionData) getVersion(ctx context.Context, serverId string) (string, error) {
+func (s *DistributionData) GetVersion(ctx context.Context, serverId string) (string, error) {
 	timeoutCtx, cancelTimeout := context.WithTimeout(ctx, defaultRequestTimeout)
 	defer cancelTimeout()
 
@@ -164,7 +163,7 @@ func (s *DistributionData) DistributionValidations(ctx context.Context, serverId
 		return nil
 	}
 
-	currentVersion, err := s.getVersion(ctx, serverId)
+	currentVersion, err := s.GetVersion(ctx, serverId)
 	if err != nil {
 		return err
 	}
@@ -0,0 +1,238 @@
+package servicelayer
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/jfrog/jfrog-cli-core/v2/utils/config"
+	"github.com/jfrog/jfrog-cli-core/v2/utils/coreutils"
+	"github.com/jfrog/jfrog-client-go/auth/cert"
+	"github.com/jfrog/live-logs/internal/clientlayer"
+	"github.com/jfrog/live-logs/internal/constants"
+	"github.com/jfrog/live-logs/internal/model"
+	"github.com/jfrog/live-logs/internal/util"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+const doctorDialTimeout = 10 * time.Second
+
+// Methods initialised as variables to improved unit test coverage
+var getServerDetails = clientlayer.GetServerDetails
+var newDiagnosedService = NewService
+var lookupHost = net.DefaultResolver.LookupHost
+
+// Sends a request to the product url with the certificates and the proxy of the commands, any response shows that the
+// TLS handshake succeeded.
+var probeTls = func(ctx context.Context, productUrl string, insecureTls bool) error {
+	certsPath, err := coreutils.GetJfrogCertsDir()
+	if err != nil {
+		return err
+	}
+	transport := &http.Transport{
+		Proxy:               http.ProxyFromEnvironment,
+		DialContext:         (&net.Dialer{Timeout: doctorDialTimeout}).DialContext,
+		TLSHandshakeTimeout: doctorDialTimeout,
+	}
+	transport, err = cert.GetTransportWithLoadedCert(certsPath, insecureTls, transport)
+	if err != nil {
+		return err
+	}
+	defer transport.CloseIdleConnections()
+	client := &http.Client{Transport: transport, CheckRedirect: func(*http.Request, []*http.Request) error {
+		return http.ErrUseLastResponse
+	}}
+	req, err := http.NewRequestWithContext(ctx, http.MethodHead, productUrl, nil)
+	if err != nil {
+		return err
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	return resp.Body.Close()
+}
+
+// Runs the checks needed to read the logs of a product with the server id, in order.
+// Once a check fails, the checks depending on it are skipped.
+func Diagnose(ctx context.Context, productId, serverId string) []model.DoctorCheck {
+	d := &diagnosis{ctx: ctx, productId: productId, serverId: serverId}
+	d.run("Product id", d.checkProductId)
+	d.run("CLI config", d.checkCliConfig)
+	d.run("Product url", d.checkUrl)
+	d.run("DNS", d.checkDns)
+	d.run("TLS", d.checkTls)
+	d.run("Authentication", d.checkAuthentication)
+	d.run("Version", d.checkVersion)
+	d.run("Logs config", d.checkLogsConfig)
+	d.run("Logs data", d.checkLogsData)
+	return d.checks
+}
+
+type diagnosis struct {
+	ctx           context.Context
+	productId     string
+	serverId      string
+	product       productDetails
+	serverDetails *config.ServerDetails
+	serviceLayer  ServiceLayer
+	productUrl    *url.URL
+	logConfig     *model.Config
+
+	checks []model.DoctorCheck
+	failed bool
+}
+
+// A check returns its details on success, or its error with a remediation hint.
+type doctorCheckFunc func() (details string, hint string, err error)
+
+func (d *diagnosis) run(name string, check doctorCheckFunc) {
+	if d.failed {
+		d.checks = append(d.checks, model.DoctorCheck{Name: name, Status: model.CheckSkipped, Details: "skipped after a previous failure"})
+		return
+	}
+	details, hint, err := check()
+	if err != nil {
+		d.failed = true
+		d.checks = append(d.checks, model.DoctorCheck{Name: name, Status: model.CheckFailed, Details: err.Error(), Hint: hint})
+		return
+	}
+	d.checks = append(d.checks, model.DoctorCheck{Name: name, Status: model.CheckPassed, Details: details})
+}
+
+func (d *diagnosis) checkProductId() (string, string, error) {
+	var found bool
+	d.product, found = products[d.productId]
+	if !found {
+		return "", "use one of the product ids: " + strings.Join(util.FetchAllProductIds(), ", "), fmt.Errorf("invalid product id '%s'", d.productId)
+	}
+	serviceLayer, err := newDiagnosedService(d.productId)
+	if err != nil {
+		return "", "", err
+	}
+	d.serviceLayer = serviceLayer
+	return d.product.name, "", nil
+}
+
+func (d *diagnosis) checkCliConfig() (string, string, error) {
+	serverDetails, err := getServerDetails(d.serverId, false)
+	if err != nil {
+		return "", "list the configured server ids with 'jf c show', or add one with 'jf c add " + d.serverId + "'", err
+	}
+	d.serverDetails = serverDetails
+	return "server id " + d.serverId + " found", "", nil
+}
+
+func (d *diagnosis) checkUrl() (string, string, error) {
+	rawUrl := d.product.getUrl(d.serverDetails)
+	if rawUrl == "" {
+		return "", fmt.Sprintf("set the %s url of the server id with 'jf c edit %s', or use the platform url when adding it", d.product.name, d.serverId),
+			fmt.Errorf("the %s url is not configured in the server id: %s", d.product.name, d.serverId)
+	}
+	productUrl, err := url.Parse(rawUrl)
+	if err != nil || productUrl.Host == "" {
+		return "", fmt.Sprintf("fix the %s url of the server id with 'jf c edit %s'", d.product.name, d.serverId),
+			fmt.Errorf("invalid %s url: %s", d.product.name, rawUrl)
+	}
+	d.productUrl = productUrl
+	return rawUrl, "", nil
+}
+
+func (d *diagnosis) checkDns() (string, string, error) {
+	addresses, err := lookupHost(d.ctx, d.productUrl.Hostname())
+	if err != nil {
+		return "", "check the host name of the url, and the DNS and proxy settings of this machine", err
+	}
+	return fmt.Sprintf("%s resolved to %v", d.productUrl.Hostname(), addresses), "", nil
+}
+
+func (d *diagnosis) checkTls() (string, string, error) {
+	if d.productUrl.Scheme != "https" {
+		return "not used, the url is " + d.productUrl.Scheme, "", nil
+	}
+	err := probeTls(d.ctx, d.productUrl.String(), d.serverDetails.InsecureTls)
+	if err != nil {
+		return "", "for a self-signed certificate, add its CA to the JFrog CLI certificates directory (~/.jfrog/security/certs)", err
+	}
+	return "handshake succeeded", "", nil
+}
+
+// Artifactory supports all the authentication types, the other products only support an admin access token.
+func (d *diagnosis) checkAuthentication() (string, string, error) {
+	switch {
+	case d.serverDetails.AccessToken != "":
+		return "access token", "", nil
+	case d.productId != constants.ArtifactoryId:
+		return "", fmt.Sprintf("add an admin access token to the server id with 'jf c edit %s --access-token <token>'", d.serverId),
+			fmt.Errorf("%s only supports admin access token authentication", d.product.name)
+	case d.serverDetails.User != "" && d.serverDetails.Password != "":
+		return "basic authentication of user " + d.serverDetails.User, "", nil
+	case d.serverDetails.ClientCertPath != "":
+		return "client certificate", "", nil
+	}
+	return "", fmt.Sprintf("add credentials to the server id with 'jf c edit %s'", d.serverId),
+		fmt.Errorf("no credentials are configured in the server id: %s", d.serverId)
+}
+
+func (d *diagnosis) checkVersion() (string, string, error) {
+	if d.product.minVersion == "" {
+		return d.product.name + " does not expose its version", "", nil
+	}
+	version, err := d.serviceLayer.GetVersion(d.ctx, d.serverId)
+	if err != nil {
+		return "", requestHint(err), err
+	}
+	err = checkVersion(version, d.product.minVersion, d.product.name)
+	if err != nil {
+		return "", fmt.Sprintf("upgrade %s to %s or above, or set %s=false to skip this validation", d.product.name, d.product.minVersion, constants.VersionCheckEnv), err
+	}
+	return version, "", nil
+}
+
+func (d *diagnosis) checkLogsConfig() (string, string, error) {
+	logConfig, err := d.serviceLayer.GetConfig(d.ctx, d.serverId)
+	if err != nil {
+		return "", requestHint(err), err
+	}
+	d.logConfig = logConfig
+	return fmt.Sprintf("%d nodes, %d log files", len(logConfig.Nodes), len(logConfig.LogFileNames)), "", nil
+}
+
+func (d *diagnosis) checkLogsData() (string, string, error) {
+	if len(d.logConfig.Nodes) == 0 {
+		return "", "check that the product nodes are running and registered, the server did not report any node",
+			fmt.Errorf("no nodes reported by the server")
+	}
+	if len(d.logConfig.LogFileNames) == 0 {
+		return "", "check the log configuration of the product, the server did not report any log file",
+			fmt.Errorf("no log files reported by the server")
+	}
+	nodeId, logName := d.logConfig.Nodes[0], d.logConfig.LogFileNames[0]
+	d.serviceLayer.SetNodeId(nodeId)
+	d.serviceLayer.SetLogFileName(logName)
+	d.serviceLayer.SetLastPageMarker(0)
+	logData, err := d.serviceLayer.GetLogData(d.ctx, d.serverId)
+	if err != nil {
+		return "", requestHint(err), err
+	}
+	return fmt.Sprintf("read %d bytes of %s from node %s", len(logData.Content), logName, nodeId), "", nil
+}
+
+func requestHint(err error) string {
+	var statusErr *StatusError
+	if !errors.As(err, &statusErr) {
+		return "check the network connection to the product, and the proxy settings of this machine"
+	}
+	switch statusErr.StatusCode {
+	case http.StatusUnauthorized:
+		return "the credentials were rejected, verify them with 'jf rt ping' or update them with 'jf c edit'"
+	case http.StatusForbidden:
+		return "the user is not allowed to read the logs, an admin user or admin access token is required"
+	case http.StatusNotFound:
+		return "the endpoint was not found, verify the product url and version"
+	}
+	return "check the product logs for the cause of the failure"
+}
@@ -0,0 +1,207 @@
+package servicelayer
+
+import (
+	"context"
+	"encoding/pem"
+	"fmt"
+	"github.com/jfrog/jfrog-cli-core/v2/utils/config"
+	"github.com/jfrog/jfrog-cli-core/v2/utils/coreutils"
+	"github.com/jfrog/live-logs/internal/constants"
+	"github.com/jfrog/live-logs/internal/model"
+	"github.com/stretchr/testify/require"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+type doctorMockService struct {
+	probeMockService
+	logErr error
+	// The returned logs config, a single node and log file when nil.
+	logConfig *model.Config
+}
+
+func (s *doctorMockService) GetConfig(_ context.Context, _ string) (*model.Config, error) {
+	if s.logConfig != nil {
+		return s.logConfig, s.configErr
+	}
+	return &model.Config{Nodes: []string{"node1"}, LogFileNames: []string{"console.log"}}, s.configErr
+}
+
+func (s *doctorMockService) GetLogData(_ context.Context, _ string) (model.Data, error) {
+	return model.Data{Content: "some log"}, s.logErr
+}
+
+func Test_servicelayer_Diagnose(t *testing.T) {
+	tests := []struct {
+		name          string
+		productId     string
+		serverDetails *config.ServerDetails
+		service       *doctorMockService
+		dnsErr        error
+		tlsErr        error
+		wantStatuses  []string
+		wantFailed    string
+		wantHint      string
+	}{
+		{
+			name:          "all checks pass",
+			productId:     constants.ArtifactoryId,
+			serverDetails: &config.ServerDetails{ArtifactoryUrl: "https://acme.jfrog.io/artifactory/", User: "admin", Password: "password"},
+			service:       &doctorMockService{probeMockService: probeMockService{version: "7.41.0"}},
+			wantStatuses:  []string{"pass", "pass", "pass", "pass", "pass", "pass", "pass", "pass", "pass"},
+		},
+		{
+			name:          "invalid product id",
+			productId:     "zz",
+			serverDetails: &config.ServerDetails{ArtifactoryUrl: "https://acme.jfrog.io/artifactory/"},
+			service:       &doctorMockService{},
+			wantStatuses:  []string{"fail", "skip", "skip", "skip", "skip", "skip", "skip", "skip", "skip"},
+			wantFailed:    "Product id",
+			wantHint:      "rt, xr, mc, pl, ds",
+		},
+		{
+			name:          "missing product url",
+			productId:     constants.XrayId,
+			serverDetails: &config.ServerDetails{ArtifactoryUrl: "https://acme.jfrog.io/artifactory/"},
+			service:       &doctorMockService{},
+			wantStatuses:  []string{"pass", "pass", "fail", "skip", "skip", "skip", "skip", "skip", "skip"},
+			wantFailed:    "Product url",
+			wantHint:      "jf c edit",
+		},
+		{
+			name:          "dns failure",
+			productId:     constants.ArtifactoryId,
+			serverDetails: &config.ServerDetails{ArtifactoryUrl: "https://acme.jfrog.io/artifactory/"},
+			service:       &doctorMockService{},
+			dnsErr:        fmt.Errorf("no such host"),
+			wantStatuses:  []string{"pass", "pass", "pass", "fail", "skip", "skip", "skip", "skip", "skip"},
+			wantFailed:    "DNS",
+			wantHint:      "DNS",
+		},
+		{
+			name:          "tls failure",
+			productId:     constants.ArtifactoryId,
+			serverDetails: &config.ServerDetails{ArtifactoryUrl: "https://acme.jfrog.io/artifactory/"},
+			service:       &doctorMockService{},
+			tlsErr:        fmt.Errorf("x509: certificate signed by unknown authority"),
+			wantStatuses:  []string{"pass", "pass", "pass", "pass", "fail", "skip", "skip", "skip", "skip"},
+			wantFailed:    "TLS",
+			wantHint:      "certs",
+		},
+		{
+			name:          "basic authentication for a product other than Artifactory",
+			productId:     constants.DistributionId,
+			serverDetails: &config.ServerDetails{DistributionUrl: "http://acme.jfrog.io/distribution/", User: "admin", Password: "password"},
+			service:       &doctorMockService{},
+			wantStatuses:  []string{"pass", "pass", "pass", "pass", "pass", "fail", "skip", "skip", "skip"},
+			wantFailed:    "Authentication",
+			wantHint:      "access token",
+		},
+		{
+			name:          "unsupported version",
+			productId:     constants.ArtifactoryId,
+			serverDetails: &config.ServerDetails{ArtifactoryUrl: "https://acme.jfrog.io/artifactory/", AccessToken: "token"},
+			service:       &doctorMockService{probeMockService: probeMockService{version: "7.1.0"}},
+			wantStatuses:  []string{"pass", "pass", "pass", "pass", "pass", "pass", "fail", "skip", "skip"},
+			wantFailed:    "Version",
+			wantHint:      constants.VersionCheckEnv,
+		},
+		{
+			name:          "forbidden logs config",
+			productId:     constants.ArtifactoryId,
+			serverDetails: &config.ServerDetails{ArtifactoryUrl: "https://acme.jfrog.io/artifactory/", AccessToken: "token"},
+			service:       &doctorMockService{probeMockService: probeMockService{version: "7.41.0", configErr: errorHandle(403, []byte("forbidden"))}},
+			wantStatuses:  []string{"pass", "pass", "pass", "pass", "pass", "pass", "pass", "fail", "skip"},
+			wantFailed:    "Logs config",
+			wantHint:      "admin",
+		},
+		{
+			name:          "no nodes",
+			productId:     constants.ArtifactoryId,
+			serverDetails: &config.ServerDetails{ArtifactoryUrl: "https://acme.jfrog.io/artifactory/", AccessToken: "token"},
+			service: &doctorMockService{probeMockService: probeMockService{version: "7.41.0"},
+				logConfig: &model.Config{LogFileNames: []string{"console.log"}}},
+			wantStatuses: []string{"pass", "pass", "pass", "pass", "pass", "pass", "pass", "pass", "fail"},
+			wantFailed:   "Logs data",
+			wantHint:     "did not report any node",
+		},
+		{
+			name:          "no log files",
+			productId:     constants.ArtifactoryId,
+			serverDetails: &config.ServerDetails{ArtifactoryUrl: "https://acme.jfrog.io/artifactory/", AccessToken: "token"},
+			service: &doctorMockService{probeMockService: probeMockService{version: "7.41.0"},
+				logConfig: &model.Config{Nodes: []string{"node1"}}},
+			wantStatuses: []string{"pass", "pass", "pass", "pass", "pass", "pass", "pass", "pass", "fail"},
+			wantFailed:   "Logs data",
+			wantHint:     "did not report any log file",
+		},
+	}
+	realGetServerDetails, realNewDiagnosedService, realLookupHost, realProbeTls := getServerDetails, newDiagnosedService, lookupHost, probeTls
+	defer func() {
+		getServerDetails, newDiagnosedService, lookupHost, probeTls = realGetServerDetails, realNewDiagnosedService, realLookupHost, realProbeTls
+	}()
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			getServerDetails = func(serverId string, _ bool) (*config.ServerDetails, error) {
+				return tt.serverDetails, nil
+			}
+			newDiagnosedService = func(productId string) (ServiceLayer, error) {
+				return tt.service, nil
+			}
+			lookupHost = func(_ context.Context, host string) ([]string, error) {
+				require.Equal(t, "acme.jfrog.io", host)
+				return []string{"10.0.0.1"}, tt.dnsErr
+			}
+			probeTls = func(_ context.Context, productUrl string, _ bool) error {
+				require.True(t, strings.HasPrefix(productUrl, "https://acme.jfrog.io/"), productUrl)
+				return tt.tlsErr
+			}
+
+			checks := Diagnose(context.Background(), tt.productId, "test-server")
+			var statuses []string
+			for _, check := range checks {
+				statuses = append(statuses, check.Status)
+				if check.Status == model.CheckFailed {
+					require.Equal(t, tt.wantFailed, check.Name)
+					require.Contains(t, check.Hint, tt.wantHint)
+				}
+			}
+			require.Equal(t, tt.wantStatuses, statuses)
+		})
+	}
+}
+
+func Test_servicelayer_ProbeTls(t *testing.T) {
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusUnauthorized)
+	}))
+	defer server.Close()
+	homeDir, err := ioutil.TempDir("", "doctor")
+	require.NoError(t, err)
+	defer os.RemoveAll(homeDir)
+	realHomeDir, homeDirSet := os.LookupEnv(coreutils.HomeDir)
+	defer func() {
+		if homeDirSet {
+			_ = os.Setenv(coreutils.HomeDir, realHomeDir)
+		} else {
+			_ = os.Unsetenv(coreutils.HomeDir)
+		}
+	}()
+	require.NoError(t, os.Setenv(coreutils.HomeDir, homeDir))
+
+	// The certificate of the server is not trusted until its CA is in the certificates directory of the CLI.
+	require.Error(t, probeTls(context.Background(), server.URL, false))
+	require.NoError(t, probeTls(context.Background(), server.URL, true))
+
+	certsDir, err := coreutils.GetJfrogCertsDir()
+	require.NoError(t, err)
+	require.NoError(t, os.MkdirAll(certsDir, 0700))
+	certificate := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: server.Certificate().Raw})
+	require.NoError(t, ioutil.WriteFile(filepath.Join(certsDir, "server.pem"), certificate, 0600))
+	require.NoError(t, probeTls(context.Background(), server.URL, false))
+}
@@ -0,0 +1,84 @@
+package servicelayer
+
+import (
+	"context"
+	"github.com/jfrog/live-logs/internal/clientlayer"
+	"github.com/jfrog/live-logs/internal/constants"
+	"github.com/jfrog/live-logs/internal/testserver"
+	"github.com/stretchr/testify/require"
+	"testing"
+)
+
+var testProducts = map[string]testserver.Product{
+	constants.ArtifactoryId:  {Version: "7.41.0"},
+	constants.XrayId:         {Version: "3.52.4"},
+	constants.McId:           {},
+	constants.PipelinesId:    {Version: "1.20.0"},
+	constants.DistributionId: {Version: "2.12.0"},
+}
+
+func TestServiceLayer_FakeServer(t *testing.T) {
+	server := testserver.New(t, "some-token")
+	serverId := clientlayer.RegisterPlatform(server.URL, "some-token")
+	defer clientlayer.SetRequestsPerSecond(clientlayer.DefaultRequestsPerSecond)
+	clientlayer.SetRequestsPerSecond(0)
+
+	for productId, product := range testProducts {
+		product.Nodes = []string{"node-1", "node-2"}
+		product.LogFileNames = []string{"one.log"}
+		product.RefreshRateMillis = 500
+		server.AddProduct(productId, product)
+		server.AppendLog(productId, "node-2", "one.log", "first line\n")
+	}
+
+	for productId, product := range testProducts {
+		t.Run(productId, func(t *testing.T) {
+			serviceLayer, err := NewService(productId)
+			require.NoError(t, err)
+
+			srvConfig, err := serviceLayer.GetConfig(context.Background(), serverId)
+			require.NoError(t, err)
+			require.Equal(t, []string{"node-1", "node-2"}, srvConfig.Nodes)
+			require.Equal(t, int64(500), srvConfig.RefreshRateMillis)
+
+			if product.Version != "" {
+				version, err := serviceLayer.GetVersion(context.Background(), serverId)
+				require.NoError(t, err)
+				require.Equal(t, product.Version, version)
+			}
+
+			serviceLayer.SetNodeId("node-2")
+			serviceLayer.SetLogFileName("one.log")
+			logData, err := serviceLayer.GetLogData(context.Background(), serverId)
+			require.NoError(t, err)
+			require.Equal(t, "first line\n", logData.Content)
+			require.Equal(t, int64(11), logData.PageMarker)
+		})
+	}
+}
+
+func TestServiceLayer_FakeServer_Failures(t *testing.T) {
+	server := testserver.New(t, "some-token")
+	server.AddProduct(constants.XrayId, testserver.Product{Version: "3.52.4", Nodes: []string{"node-1"}, LogFileNames: []string{"one.log"}})
+	defer clientlayer.SetRequestsPerSecond(clientlayer.DefaultRequestsPerSecond)
+	clientlayer.SetRequestsPerSecond(0)
+
+	serviceLayer, err := NewService(constants.XrayId)
+	require.NoError(t, err)
+
+	serverId := clientlayer.RegisterPlatform(server.URL, "some-token")
+	server.SetAccessToken("other-token")
+	_, err = serviceLayer.GetConfig(context.Background(), serverId)
+	var statusErr *StatusError
+	require.ErrorAs(t, err, &statusErr)
+	require.Equal(t, 401, statusErr.StatusCode)
+
+	server.SetAccessToken("some-token")
+	server.FailRequests(constants.XrayId, 429, 1)
+	_, err = serviceLayer.GetConfig(context.Background(), serverId)
+	require.ErrorAs(t, err, &statusErr)
+	require.Equal(t, 429, statusErr.StatusCode)
+
+	_, err = serviceLayer.GetConfig(context.Background(), serverId)
+	require.NoError(t, err)
+}
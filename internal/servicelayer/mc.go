@@ -4,7 +4,6 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
-	cliCommands "github.com/jfrog/jfrog-cli-core/v2/common/commands"
 	"github.com/jfrog/live-logs/internal/clientlayer"
 	"github.com/jfrog/live-logs/internal/constants"
 	"github.com/jfrog/live-logs/internal/model"
@@ -94,7 +93,7 @@ func (s *McData) GetLogData(ctx context.Context, serverId string) (logData model
 }
 
 func (s *McData) getConnectionDetails(serverId string)(url string, headers map[string]string,_ error){
-	confDetails, err := cliCommands.GetConfig(serverId, false)
+	confDetails, err := clientlayer.GetServerDetails(serverId, false)
 	if err != nil {
 		return "",nil, err
 	}
@@ -113,6 +112,10 @@ func (s *McData) getConnectionDetails(serverId string)(url string, headers map[s
 	return url,headers, nil
 }
 
+func (s *McData) GetVersion(_ context.Context, _ string) (string, error) {
+	return "", fmt.Errorf("version information is not available for Mission Control")
+}
+
 func (s *McData) SetNodeId(nodeId string) {
 	s.nodeId = nodeId
 }
@@ -4,7 +4,6 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
-	cliCommands "github.com/jfrog/jfrog-cli-core/v2/common/commands"
 	"github.com/jfrog/live-logs/internal/clientlayer"
 	"github.com/jfrog/live-logs/internal/constants"
 	"github.com/jfrog/live-logs/internal/model"
@@ -109,7 +108,7 @@ func (s *PipelinesData) GetLogData(ctx context.Context, serverId string) (logDat
 }
 
 func (s *PipelinesData) getConnectionDetails(serverId string)(url string, headers map[string]string,_ error){
-	confDetails, err := cliCommands.GetConfig(serverId, false)
+	confDetails, err := clientlayer.GetServerDetails(serverId, false)
 	if err != nil {
 		return "",nil, err
 	}
@@ -128,7 +127,7 @@ func (s *PipelinesData) getConnectionDetails(serverId string)(url string, header
 	return url,headers, nil
 }
 
-func (s *PipelinesData) getVersion(ctx context.Context, serverId string) (string, error) {
+func (s *PipelinesData) GetVersion(ctx context.Context, serverId string) (string, error) {
 	timeoutCtx, cancelTimeout := context.WithTimeout(ctx, defaultRequestTimeout)
 	defer cancelTimeout()
 
@@ -164,7 +163,7 @@ func (s *PipelinesData) PipelinesValidations(ctx context.Context, serverId strin
 		return nil
 	}
 
-	currentVersion, err := s.getVersion(ctx, serverId)
+	currentVersion, err := s.GetVersion(ctx, serverId)
 	if err != nil {
 		return err
 	}
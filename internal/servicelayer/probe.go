@@ -0,0 +1,95 @@
+package servicelayer
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/jfrog/jfrog-cli-core/v2/utils/config"
+	"github.com/jfrog/live-logs/internal/clientlayer"
+	"github.com/jfrog/live-logs/internal/constants"
+	"github.com/jfrog/live-logs/internal/model"
+)
+
+type productDetails struct {
+	name       string
+	minVersion string
+	getUrl     func(serverDetails *config.ServerDetails) string
+}
+
+// Mission Control does not expose its version, so it has no minimum version.
+var products = map[string]productDetails{
+	constants.ArtifactoryId:  {artifactoryProductName, artifactoryMinVersionSupport, (*config.ServerDetails).GetArtifactoryUrl},
+	constants.XrayId:         {xrayProductName, xrayMinVersionSupport, (*config.ServerDetails).GetXrayUrl},
+	constants.McId:           {"Mission Control", "", (*config.ServerDetails).GetMissionControlUrl},
+	constants.PipelinesId:    {pipelinesProductName, pipelinesMinVersionSupport, (*config.ServerDetails).GetPipelinesUrl},
+	constants.DistributionId: {distributionProductName, distributionMinVersionSupport, (*config.ServerDetails).GetDistributionUrl},
+}
+
+// Checks whether the logs of a product can be read with the server id: the product url is configured, the product
+// responds, the credentials are accepted by its logs config endpoint, and its version is supported.
+func ProbeProduct(ctx context.Context, productId, serverId string) model.ProductStatus {
+	status := model.ProductStatus{ProductId: productId}
+	product, found := products[productId]
+	if !found {
+		status.Error = fmt.Sprintf("invalid product id '%s' provided", productId)
+		return status
+	}
+	status.MinVersion = product.minVersion
+
+	serverDetails, err := clientlayer.GetServerDetails(serverId, false)
+	if err != nil {
+		status.Error = err.Error()
+		return status
+	}
+	status.Url = product.getUrl(serverDetails)
+	if status.Url == "" {
+		status.Error = fmt.Sprintf("the %s url is not configured in the server id: %s", product.name, serverId)
+		return status
+	}
+	status.Configured = true
+
+	serviceLayer, err := NewService(productId)
+	if err != nil {
+		status.Error = err.Error()
+		return status
+	}
+	probeService(ctx, serviceLayer, serverId, product, &status)
+	return status
+}
+
+func probeService(ctx context.Context, serviceLayer ServiceLayer, serverId string, product productDetails, status *model.ProductStatus) {
+	if product.minVersion == "" {
+		status.SupportedVersion = true
+	} else {
+		version, err := serviceLayer.GetVersion(ctx, serverId)
+		if err != nil {
+			setProbeError(status, err)
+			return
+		}
+		status.Reachable = true
+		status.Version = version
+		err = checkVersion(version, product.minVersion, product.name)
+		if err != nil {
+			status.Error = err.Error()
+			return
+		}
+		status.SupportedVersion = true
+	}
+
+	_, err := serviceLayer.GetConfig(ctx, serverId)
+	if err != nil {
+		setProbeError(status, err)
+		return
+	}
+	status.Reachable = true
+	status.Authorized = true
+}
+
+// Any response status means the product is reachable, the authorization is only confirmed by a successful response.
+func setProbeError(status *model.ProductStatus, err error) {
+	status.Error = err.Error()
+	var statusErr *StatusError
+	if errors.As(err, &statusErr) {
+		status.Reachable = true
+	}
+}
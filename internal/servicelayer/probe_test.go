@@ -0,0 +1,86 @@
+package servicelayer
+
+import (
+	"context"
+	"fmt"
+	"github.com/jfrog/live-logs/internal/model"
+	"github.com/stretchr/testify/require"
+	"testing"
+)
+
+type probeMockService struct {
+	ArtifactoryData
+	version    string
+	versionErr error
+	configErr  error
+}
+
+func (s *probeMockService) GetVersion(_ context.Context, _ string) (string, error) {
+	return s.version, s.versionErr
+}
+
+func (s *probeMockService) GetConfig(_ context.Context, _ string) (*model.Config, error) {
+	return &model.Config{}, s.configErr
+}
+
+func Test_servicelayer_probeService(t *testing.T) {
+	artifactory := productDetails{name: artifactoryProductName, minVersion: artifactoryMinVersionSupport}
+	tests := []struct {
+		name           string
+		product        productDetails
+		service        *probeMockService
+		wantReachable  bool
+		wantAuthorized bool
+		wantSupported  bool
+		wantErr        string
+	}{
+		{
+			name:           "available",
+			product:        artifactory,
+			service:        &probeMockService{version: "7.41.0"},
+			wantReachable:  true,
+			wantAuthorized: true,
+			wantSupported:  true,
+		},
+		{
+			name:    "unreachable",
+			product: artifactory,
+			service: &probeMockService{versionErr: fmt.Errorf("connection refused")},
+			wantErr: "connection refused",
+		},
+		{
+			name:          "unauthorized",
+			product:       artifactory,
+			service:       &probeMockService{version: "7.41.0", configErr: errorHandle(401, []byte("bad credentials"))},
+			wantReachable: true,
+			wantSupported: true,
+			wantErr:       "status code: 401",
+		},
+		{
+			name:          "unsupported version",
+			product:       artifactory,
+			service:       &probeMockService{version: "7.10.0"},
+			wantReachable: true,
+			wantErr:       "minimum supported version",
+		},
+		{
+			name:           "no version information",
+			product:        productDetails{name: "Mission Control"},
+			service:        &probeMockService{versionErr: fmt.Errorf("version information is not available")},
+			wantReachable:  true,
+			wantAuthorized: true,
+			wantSupported:  true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			status := model.ProductStatus{Configured: true}
+			probeService(context.Background(), tt.service, "test-rt", tt.product, &status)
+			require.Equal(t, tt.wantReachable, status.Reachable)
+			require.Equal(t, tt.wantAuthorized, status.Authorized)
+			require.Equal(t, tt.wantSupported, status.SupportedVersion)
+			require.Equal(t, tt.wantReachable && tt.wantAuthorized && tt.wantSupported, status.IsAvailable())
+			require.Contains(t, status.Error, tt.wantErr)
+		})
+	}
+}
@@ -18,6 +18,9 @@ type ServiceLayer interface {
 	// Queries and returns the livelog data from the remote service, based on the set node id and log file name.
 	GetLogData(ctx context.Context, serverId string) (model.Data, error)
 
+	// Queries and returns the version of the remote service.
+	GetVersion(ctx context.Context, serverId string) (string, error)
+
 	// Sets the node id to use when querying the remote service for log data.
 	SetNodeId(nodeId string)
 	GetNodeId() string
@@ -62,15 +65,25 @@ func NewService(productId string) (serviceLayer ServiceLayer, err error) {
 	return serviceLayer, err
 }
 
+// Returned when the remote service responds with an error status code.
+type StatusError struct {
+	StatusCode int
+	message    string
+}
+
+func (e *StatusError) Error() string {
+	return e.message
+}
+
 func errorHandle(statusCode int, resBody []byte) error {
 	if statusCode == 200 {
 		return nil
 	}
 	if statusCode == 404 || statusCode == 400 || statusCode == 429 {
-		return fmt.Errorf("status code: %d; message: %s", statusCode, resBody)
+		return &StatusError{StatusCode: statusCode, message: fmt.Sprintf("status code: %d; message: %s", statusCode, resBody)}
 	}
 	if statusCode < 200 || statusCode >= 300 {
-		return fmt.Errorf("unexpected response; status code: %d, message: %s", statusCode, resBody)
+		return &StatusError{StatusCode: statusCode, message: fmt.Sprintf("unexpected response; status code: %d, message: %s", statusCode, resBody)}
 	}
 	return nil
 }
@@ -4,7 +4,6 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
-	cliCommands "github.com/jfrog/jfrog-cli-core/v2/common/commands"
 	"github.com/jfrog/live-logs/internal/clientlayer"
 	"github.com/jfrog/live-logs/internal/constants"
 	"github.com/jfrog/live-logs/internal/model"
@@ -112,7 +111,7 @@ func (s *XrayData) GetLogData(ctx context.Context, serverId string) (logData mod
 }
 
 func (s *XrayData) getConnectionDetails(serverId string)(url string, headers map[string]string,_ error){
-	confDetails, err := cliCommands.GetConfig(serverId, false)
+	confDetails, err := clientlayer.GetServerDetails(serverId, false)
 	if err != nil {
 		return "",nil, err
 	}
@@ -131,7 +130,7 @@ func (s *XrayData) getConnectionDetails(serverId string)(url string, headers map
 	return url,headers, nil
 }
 
-func (s *XrayData) getVersion(ctx context.Context, serverId string) (string, error) {
+func (s *XrayData) GetVersion(ctx context.Context, serverId string) (string, error) {
 	timeoutCtx, cancelTimeout := context.WithTimeout(ctx, defaultRequestTimeout)
 	defer cancelTimeout()
 
@@ -165,7 +164,7 @@ func (s *XrayData) XrayValidations(ctx context.Context, serverId string) error {
 		return nil
 	}
 
-	currentVersion, err := s.getVersion(ctx, serverId)
+	currentVersion, err := s.GetVersion(ctx, serverId)
 	if err != nil {
 		return err
 	}
@@ -0,0 +1,75 @@
+package ship
+
+import (
+	"encoding/json"
+	"github.com/jfrog/live-logs/internal/stream"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sync"
+)
+
+// The offsets of the log files up to which the lines were shipped, persisted to a JSON file so a restarted shipping
+// resumes where it stopped.
+type Checkpoints struct {
+	path string
+
+	lock    sync.Mutex
+	offsets map[string]int64
+}
+
+// Loads the checkpoints of a file, a missing file holds no checkpoint.
+func LoadCheckpoints(path string) (*Checkpoints, error) {
+	checkpoints := &Checkpoints{path: path, offsets: map[string]int64{}}
+	data, err := ioutil.ReadFile(path)
+	if os.IsNotExist(err) {
+		return checkpoints, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	err = json.Unmarshal(data, &checkpoints.offsets)
+	if err != nil {
+		return nil, err
+	}
+	return checkpoints, nil
+}
+
+// Returns the offset up to which the log file of the source was shipped.
+func (c *Checkpoints) Get(source stream.Source) (int64, bool) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	offset, found := c.offsets[checkpointKey(source)]
+	return offset, found
+}
+
+// Moves the checkpoints of the sources and persists all the checkpoints. The file is replaced at once, so it is
+// never left half written.
+func (c *Checkpoints) Commit(offsets map[stream.Source]int64) error {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	for source, offset := range offsets {
+		c.offsets[checkpointKey(source)] = offset
+	}
+	data, err := json.MarshalIndent(c.offsets, "", "  ")
+	if err != nil {
+		return err
+	}
+	tempFile, err := ioutil.TempFile(filepath.Dir(c.path), filepath.Base(c.path)+".*.tmp")
+	if err != nil {
+		return err
+	}
+	_, err = tempFile.Write(data)
+	if closeErr := tempFile.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		_ = os.Remove(tempFile.Name())
+		return err
+	}
+	return os.Rename(tempFile.Name(), c.path)
+}
+
+func checkpointKey(source stream.Source) string {
+	return source.ServerId + "/" + source.ProductId + "/" + source.NodeId + "/" + source.LogName
+}
@@ -0,0 +1,36 @@
+package ship
+
+import (
+	"github.com/jfrog/live-logs/internal/stream"
+	"github.com/stretchr/testify/require"
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+)
+
+func TestCheckpoints(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "checkpoints.json")
+	checkpoints, err := LoadCheckpoints(path)
+	require.NoError(t, err)
+	source := stream.Source{ServerId: "local-rt", ProductId: "rt", NodeId: "node1", LogName: "one.log"}
+	_, found := checkpoints.Get(source)
+	require.False(t, found)
+
+	require.NoError(t, checkpoints.Commit(map[stream.Source]int64{source: 120}))
+	reloaded, err := LoadCheckpoints(path)
+	require.NoError(t, err)
+	offset, found := reloaded.Get(source)
+	require.True(t, found)
+	require.Equal(t, int64(120), offset)
+
+	files, err := ioutil.ReadDir(filepath.Dir(path))
+	require.NoError(t, err)
+	require.Len(t, files, 1, "the temporary file must be renamed")
+}
+
+func TestLoadCheckpoints_Invalid(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "checkpoints.json")
+	require.NoError(t, ioutil.WriteFile(path, []byte("not json"), 0600))
+	_, err := LoadCheckpoints(path)
+	require.Error(t, err)
+}
@@ -0,0 +1,234 @@
+package ship
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"github.com/jfrog/live-logs/internal/format"
+	"github.com/jfrog/live-logs/internal/stream"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const otlpScopeName = "jfrog-live-logs"
+
+// The OpenTelemetry severity numbers of the log levels, see format.Level.
+var otlpSeverities = map[string]int{
+	format.TraceLevel: 1,
+	format.DebugLevel: 5,
+	format.InfoLevel:  9,
+	format.WarnLevel:  13,
+	format.ErrorLevel: 17,
+	format.FatalLevel: 21,
+}
+
+// Exports the events as OpenTelemetry log records with the OTLP/HTTP JSON encoding. The records of each server,
+// product and node share a resource, the service and request log fields become attributes of the records.
+type otlpSink struct {
+	httpSink
+	notices io.Writer
+	now     func() time.Time
+}
+
+type otlpLogsRequest struct {
+	ResourceLogs []*otlpResourceLogs `json:"resourceLogs"`
+}
+
+type otlpResourceLogs struct {
+	Resource  otlpResource     `json:"resource"`
+	ScopeLogs []*otlpScopeLogs `json:"scopeLogs"`
+}
+
+type otlpResource struct {
+	Attributes []otlpKeyValue `json:"attributes"`
+}
+
+type otlpScopeLogs struct {
+	Scope      otlpScope       `json:"scope"`
+	LogRecords []otlpLogRecord `json:"logRecords"`
+}
+
+type otlpScope struct {
+	Name string `json:"name"`
+}
+
+type otlpLogRecord struct {
+	// The 64 bit integers are strings in the JSON encoding.
+	TimeUnixNano         string         `json:"timeUnixNano"`
+	ObservedTimeUnixNano string         `json:"observedTimeUnixNano"`
+	SeverityNumber       int            `json:"severityNumber,omitempty"`
+	SeverityText         string         `json:"severityText,omitempty"`
+	Body                 otlpAnyValue   `json:"body"`
+	Attributes           []otlpKeyValue `json:"attributes"`
+	TraceId              string         `json:"traceId,omitempty"`
+}
+
+type otlpKeyValue struct {
+	Key   string       `json:"key"`
+	Value otlpAnyValue `json:"value"`
+}
+
+// A string or an integer value, the integers are decimal strings in the JSON encoding.
+type otlpAnyValue struct {
+	StringValue string
+	IntValue    string
+}
+
+func (v otlpAnyValue) MarshalJSON() ([]byte, error) {
+	if v.IntValue != "" {
+		return json.Marshal(map[string]string{"intValue": v.IntValue})
+	}
+	return json.Marshal(map[string]string{"stringValue": v.StringValue})
+}
+
+type otlpLogsResponse struct {
+	PartialSuccess *struct {
+		RejectedLogRecords json.Number `json:"rejectedLogRecords"`
+		ErrorMessage       string      `json:"errorMessage"`
+	} `json:"partialSuccess"`
+}
+
+// The origin of the records sharing a resource.
+type otlpResourceKey struct {
+	serverId  string
+	productId string
+	nodeId    string
+}
+
+func (s *otlpSink) Send(ctx context.Context, events []Event) error {
+	var request otlpLogsRequest
+	byResource := map[otlpResourceKey]*otlpScopeLogs{}
+	observed := strconv.FormatInt(s.now().UnixNano(), 10)
+	for _, event := range events {
+		key := otlpResourceKey{serverId: event.Source.ServerId, productId: event.Source.ProductId, nodeId: event.Source.NodeId}
+		scopeLogs, found := byResource[key]
+		if !found {
+			scopeLogs = &otlpScopeLogs{Scope: otlpScope{Name: otlpScopeName}}
+			byResource[key] = scopeLogs
+			request.ResourceLogs = append(request.ResourceLogs, &otlpResourceLogs{
+				Resource:  otlpResource{Attributes: otlpResourceAttributes(event.Source)},
+				ScopeLogs: []*otlpScopeLogs{scopeLogs},
+			})
+		}
+		scopeLogs.LogRecords = append(scopeLogs.LogRecords, newOtlpLogRecord(event, observed))
+	}
+	data, err := json.Marshal(request)
+	if err != nil {
+		return err
+	}
+	responseBody, err := s.post(ctx, "application/json", data)
+	if err != nil {
+		return err
+	}
+	var response otlpLogsResponse
+	if json.Unmarshal(responseBody, &response) == nil && response.PartialSuccess != nil {
+		rejected, _ := response.PartialSuccess.RejectedLogRecords.Int64()
+		if rejected > 0 {
+			fmt.Fprintf(s.notices, "%s rejected %d of %d log records: %s\n", s.url, rejected, len(events), response.PartialSuccess.ErrorMessage)
+		}
+	}
+	return nil
+}
+
+func otlpResourceAttributes(source stream.Source) []otlpKeyValue {
+	return []otlpKeyValue{
+		otlpAttribute("service.name", source.ProductId),
+		otlpAttribute("host.name", source.NodeId),
+		otlpAttribute("jfrog.server.id", source.ServerId),
+		otlpAttribute("jfrog.product.id", source.ProductId),
+		otlpAttribute("jfrog.node.id", source.NodeId),
+	}
+}
+
+func newOtlpLogRecord(event Event, observed string) otlpLogRecord {
+	record := format.Parse(stream.Line{Source: event.Source, Text: event.Text})
+	logRecord := otlpLogRecord{
+		TimeUnixNano:         strconv.FormatInt(event.Time.UnixNano(), 10),
+		ObservedTimeUnixNano: observed,
+		Body:                 otlpAnyValue{StringValue: event.Text},
+		Attributes:           []otlpKeyValue{otlpAttribute("log.file.name", event.Source.LogName)},
+		TraceId:              otlpTraceId(record.TraceId),
+	}
+	switch {
+	case record.IsService:
+		logRecord.SeverityText = strings.ToUpper(record.Level)
+		logRecord.SeverityNumber = otlpSeverities[format.Level(record.Level)]
+		logRecord.Body.StringValue = record.Message
+		logRecord.Attributes = appendOtlpAttributes(logRecord.Attributes,
+			otlpAttribute("jfrog.service", record.Service),
+			otlpAttribute("code.namespace", record.Class),
+			otlpAttribute("thread.name", record.Thread),
+		)
+	case record.IsRequest:
+		logRecord.SeverityText = requestSeverity(record.Status)
+		logRecord.SeverityNumber = otlpSeverities[logRecord.SeverityText]
+		logRecord.Attributes = appendOtlpAttributes(logRecord.Attributes,
+			otlpAttribute("client.address", record.RemoteAddress),
+			otlpAttribute("user.name", record.Username),
+			otlpAttribute("http.request.method", record.Method),
+			otlpAttribute("url.path", record.Url),
+			otlpIntAttribute("http.response.status_code", record.Status),
+			otlpIntAttribute("http.request.body.size", record.RequestSize),
+			otlpIntAttribute("http.response.body.size", record.ResponseSize),
+			otlpIntAttribute("jfrog.duration_ms", record.Duration),
+			otlpAttribute("user_agent.original", record.UserAgent),
+		)
+	}
+	return logRecord
+}
+
+func otlpAttribute(key, value string) otlpKeyValue {
+	return otlpKeyValue{Key: key, Value: otlpAnyValue{StringValue: value}}
+}
+
+// Returns an integer attribute, without a value when the value is not a positive or zero integer, such as the -1 size
+// of the requests whose size is unknown.
+func otlpIntAttribute(key, value string) otlpKeyValue {
+	number, err := strconv.ParseInt(value, 10, 64)
+	if err != nil || number < 0 {
+		return otlpKeyValue{Key: key}
+	}
+	return otlpKeyValue{Key: key, Value: otlpAnyValue{IntValue: strconv.FormatInt(number, 10)}}
+}
+
+// Appends the attributes with a value.
+func appendOtlpAttributes(attributes []otlpKeyValue, candidates ...otlpKeyValue) []otlpKeyValue {
+	for _, attribute := range candidates {
+		if attribute.Value.StringValue != "" || attribute.Value.IntValue != "" {
+			attributes = append(attributes, attribute)
+		}
+	}
+	return attributes
+}
+
+// The request logs have no level, the server errors are errors and the client errors are warnings.
+func requestSeverity(status string) string {
+	code, err := strconv.Atoi(status)
+	switch {
+	case err != nil:
+		return ""
+	case code >= 500:
+		return "ERROR"
+	case code >= 400:
+		return "WARN"
+	default:
+		return "INFO"
+	}
+}
+
+// Returns the trace id as the 32 hex digits of an OpenTelemetry trace id, the shorter trace ids are padded with
+// zeros, such as the 16 hex digit ids or the ids whose leading zeros were trimmed. An id of another form is not a
+// trace id, such as the "-" of the lines logged outside of a request, and neither is an id of zeros only.
+func otlpTraceId(traceId string) string {
+	if traceId == "" || len(traceId) > 32 || strings.Trim(traceId, "0") == "" {
+		return ""
+	}
+	for _, c := range traceId {
+		if !strings.ContainsRune("0123456789abcdefABCDEF", c) {
+			return ""
+		}
+	}
+	return strings.Repeat("0", 32-len(traceId)) + strings.ToLower(traceId)
+}
@@ -0,0 +1,222 @@
+package ship
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"github.com/jfrog/live-logs/internal/client"
+	"github.com/jfrog/live-logs/internal/clientlayer"
+	"github.com/jfrog/live-logs/internal/constants"
+	"github.com/jfrog/live-logs/internal/servicelayer"
+	"github.com/jfrog/live-logs/internal/stream"
+	"github.com/jfrog/live-logs/internal/testserver"
+	"github.com/stretchr/testify/require"
+	"io/ioutil"
+	"net/http"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestOtlpSink(t *testing.T) {
+	receiver := testserver.NewOtlpReceiver(t)
+	sink, err := NewSink(SinkConfig{Type: OtlpSink, Url: receiver.URL, Headers: map[string]string{"x-api-key": "some-key"}})
+	require.NoError(t, err)
+	serviceSource := stream.Source{ServerId: "local-rt", ProductId: "rt", NodeId: "node2", LogName: "artifactory-service.log"}
+	events := append([]Event{
+		{
+			Source: serviceSource,
+			Time:   time.Date(2021, 3, 25, 4, 0, 0, 6000000, time.UTC),
+			Text:   "2021-03-25T04:00:00.006Z [jfrt ] [WARN ] [d76675e362ffbd6a] [.s.d.b.s.g.GarbageCollector:66] [art-exec-11 ] - Starting GC",
+		},
+		{Source: serviceSource, Time: time.Date(2021, 3, 25, 4, 0, 0, 6000000, time.UTC), Text: "\tat some.Class.method(Class.java:42)"},
+	}, testEvents...)
+	require.NoError(t, sink.Send(context.Background(), events))
+
+	require.Equal(t, "some-key", receiver.Headers()[0].Get("x-api-key"))
+	records := receiver.Records()
+	require.Len(t, records, 3)
+	require.Equal(t, testserver.OtlpRecord{
+		ResourceAttributes: map[string]string{
+			"service.name":     "rt",
+			"host.name":        "node2",
+			"jfrog.server.id":  "local-rt",
+			"jfrog.product.id": "rt",
+			"jfrog.node.id":    "node2",
+		},
+		ScopeName:      "jfrog-live-logs",
+		TimeUnixNano:   "1616644800006000000",
+		SeverityNumber: 13,
+		SeverityText:   "WARN",
+		Body:           "Starting GC",
+		TraceId:        "0000000000000000d76675e362ffbd6a",
+		Attributes: map[string]string{
+			"log.file.name":  "artifactory-service.log",
+			"jfrog.service":  "jfrt",
+			"code.namespace": ".s.d.b.s.g.GarbageCollector:66",
+			"thread.name":    "art-exec-11",
+		},
+	}, records[0])
+
+	// A line which is not a log record is sent whole, without severity.
+	require.Equal(t, "\tat some.Class.method(Class.java:42)", records[1].Body)
+	require.Zero(t, records[1].SeverityNumber)
+	require.Empty(t, records[1].TraceId)
+
+	require.Equal(t, "node1", records[2].ResourceAttributes["host.name"])
+	require.Equal(t, 9, records[2].SeverityNumber)
+	require.Equal(t, testEvents[0].Text, records[2].Body)
+	require.Equal(t, "0000000000000000094109ae150da76e", records[2].TraceId)
+	require.Equal(t, "GET", records[2].Attributes["http.request.method"])
+	require.Equal(t, map[string]int64{"http.response.status_code": 200, "http.response.body.size": 0, "jfrog.duration_ms": 3}, records[2].IntAttributes)
+	require.Equal(t, "admin", records[2].Attributes["user.name"])
+}
+
+func TestOtlpSink_UserAgentRequestLine(t *testing.T) {
+	receiver := testserver.NewOtlpReceiver(t)
+	sink, err := NewSink(SinkConfig{Type: OtlpSink, Url: receiver.URL})
+	require.NoError(t, err)
+	require.NoError(t, sink.Send(context.Background(), []Event{userAgentEvent}))
+
+	records := receiver.Records()
+	require.Len(t, records, 1)
+	require.Equal(t, "INFO", records[0].SeverityText)
+	require.Equal(t, 9, records[0].SeverityNumber)
+	require.Equal(t, "0000000000000000d76675e362ffbd6a", records[0].TraceId)
+	require.Equal(t, map[string]string{
+		"log.file.name":       "artifactory-request.log",
+		"client.address":      "10.0.0.1",
+		"user.name":           "admin",
+		"http.request.method": "GET",
+		"url.path":            "/api/system/ping",
+		"user_agent.original": "JFrog-Router/7.17.0",
+	}, records[0].Attributes)
+	require.Equal(t, map[string]int64{"http.response.status_code": 200, "http.response.body.size": 2, "jfrog.duration_ms": 3}, records[0].IntAttributes)
+}
+
+func TestOtlpAnyValue_Json(t *testing.T) {
+	data, err := json.Marshal([]otlpKeyValue{otlpAttribute("url.path", "/api"), otlpIntAttribute("http.response.status_code", "404")})
+	require.NoError(t, err)
+	require.JSONEq(t, `[{"key":"url.path","value":{"stringValue":"/api"}},{"key":"http.response.status_code","value":{"intValue":"404"}}]`, string(data))
+}
+
+func TestOtlpSink_PartialSuccess(t *testing.T) {
+	receiver := testserver.NewOtlpReceiver(t)
+	sink, err := NewSink(SinkConfig{Type: OtlpSink, Url: receiver.URL})
+	require.NoError(t, err)
+	var notices bytes.Buffer
+	sink.(*otlpSink).notices = &notices
+
+	receiver.RejectRecords(1)
+	require.NoError(t, sink.Send(context.Background(), testEvents))
+	require.Empty(t, receiver.Records())
+	require.Contains(t, notices.String(), "rejected 1 of 1 log records: rejected by the test")
+}
+
+func TestRequestSeverity(t *testing.T) {
+	tests := []struct {
+		status string
+		want   string
+	}{
+		{"200", "INFO"},
+		{"302", "INFO"},
+		{"404", "WARN"},
+		{"503", "ERROR"},
+		{"-", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.status, func(t *testing.T) {
+			require.Equal(t, tt.want, requestSeverity(tt.status))
+		})
+	}
+}
+
+func TestOtlpSeverity(t *testing.T) {
+	tests := []struct {
+		level string
+		want  int
+	}{
+		{"DEBUG", 5},
+		{"INFO", 9},
+		{"WARN", 13},
+		{"WARNING", 13},
+		{"ERROR", 17},
+		{"SEVERE", 17},
+		{"FATAL", 21},
+	}
+	for _, tt := range tests {
+		t.Run(tt.level, func(t *testing.T) {
+			event := Event{Text: "2021-03-25T04:00:00.006Z [jfrt ] [" + tt.level + "] [d76675e362ffbd6a] [Class:66] [exec-1] - message"}
+			logRecord := newOtlpLogRecord(event, "0")
+			require.Equal(t, tt.level, logRecord.SeverityText)
+			require.Equal(t, tt.want, logRecord.SeverityNumber)
+		})
+	}
+}
+
+func TestOtlpTraceId(t *testing.T) {
+	tests := []struct {
+		traceId string
+		want    string
+	}{
+		{"d76675e362ffbd6a", "0000000000000000d76675e362ffbd6a"},
+		{"4BF92F3577B34DA6A3CE929D0E0E4736", "4bf92f3577b34da6a3ce929d0e0e4736"},
+		{"94109ae150da76e", "0000000000000000094109ae150da76e"},
+		{"1", "00000000000000000000000000000001"},
+		{"4bf92f3577b34da6a3ce929d0e0e47360", ""},
+		{"0000000000000000", ""},
+		{"-", ""},
+		{"not-a-trace-id!!", ""},
+		{"", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.traceId, func(t *testing.T) {
+			require.Equal(t, tt.want, otlpTraceId(tt.traceId))
+		})
+	}
+}
+
+func TestShipper_Otlp(t *testing.T) {
+	realMinRetryDelay := minRetryDelay
+	defer func() { minRetryDelay = realMinRetryDelay }()
+	minRetryDelay = time.Millisecond
+
+	server := testserver.New(t, "some-token")
+	server.AddProduct(constants.ArtifactoryId, testserver.Product{Version: "7.41.0", Nodes: []string{"node-1"}, LogFileNames: []string{"one.log"}})
+	server.AppendLog(constants.ArtifactoryId, "node-1", "one.log", "first line\nsecond line\n")
+	realRequestsPerSecond := clientlayer.GetRequestsPerSecond()
+	defer clientlayer.SetRequestsPerSecond(realRequestsPerSecond)
+	clientlayer.SetRequestsPerSecond(0)
+	serverId := clientlayer.RegisterPlatform(server.URL, "some-token")
+	logClient := client.New(constants.ArtifactoryId, serverId, servicelayer.NewService)
+	logClient.SetFixedRefreshRate(5 * time.Millisecond)
+
+	receiver := testserver.NewOtlpReceiver(t)
+	receiver.FailRequests(http.StatusServiceUnavailable, 2)
+	sink, err := NewSink(SinkConfig{Type: OtlpSink, Url: receiver.URL})
+	require.NoError(t, err)
+	checkpoints, err := LoadCheckpoints(filepath.Join(t.TempDir(), "checkpoints.json"))
+	require.NoError(t, err)
+	// The two lines fill a batch, which is sent once the receiver recovers.
+	shipper := NewShipper(sink, checkpoints, 2, time.Minute, time.UTC)
+	shipper.notices = ioutil.Discard
+
+	source := stream.Source{ServerId: serverId, ProductId: constants.ArtifactoryId, NodeId: "node-1", LogName: "one.log"}
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error)
+	go func() {
+		done <- shipper.Ship(ctx, logClient, []stream.Source{source})
+	}()
+	require.Eventually(t, func() bool {
+		offset, _ := checkpoints.Get(source)
+		return offset == 23
+	}, 10*time.Second, 5*time.Millisecond)
+	cancel()
+	require.NoError(t, <-done)
+
+	records := receiver.Records()
+	require.Len(t, records, 2)
+	require.Equal(t, "first line", records[0].Body)
+	require.Equal(t, "second line", records[1].Body)
+	require.Len(t, receiver.Headers(), 3)
+}
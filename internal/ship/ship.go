@@ -0,0 +1,236 @@
+// Package ship sends the lines of the live logs to a log management system, such as Loki, Elasticsearch, Splunk or
+// an OpenTelemetry collector.
+// The lines are sent in batches, a failed batch is sent again with a growing delay, and the offset of the last line
+// of each log file sent is persisted once its batch is accepted, so a restarted shipping resumes where it stopped.
+package ship
+
+import (
+	"context"
+	"fmt"
+	"github.com/jfrog/live-logs/internal/client"
+	"github.com/jfrog/live-logs/internal/redact"
+	"github.com/jfrog/live-logs/internal/stream"
+	"github.com/jfrog/live-logs/internal/timerange"
+	"io"
+	"os"
+	"strings"
+	"time"
+)
+
+const (
+	DefaultBatchSize     = 500
+	DefaultFlushInterval = 5 * time.Second
+)
+
+// The delays between the attempts to send a batch, overridden by the tests.
+var (
+	minRetryDelay = time.Second
+	maxRetryDelay = 30 * time.Second
+)
+
+// A shipped log line.
+type Event struct {
+	Source stream.Source
+	// The timestamp of the line, or of the line before it when it has none.
+	Time time.Time
+	Text string
+	// The offset following the line in its log file.
+	Offset int64
+}
+
+type Shipper struct {
+	sink          Sink
+	checkpoints   *Checkpoints
+	batchSize     int
+	flushInterval time.Duration
+	logLocation   *time.Location
+	redactor      *redact.Redactor
+	notices       io.Writer
+	now           func() time.Time
+	events        chan Event
+}
+
+// Creates a shipper sending batches of up to batchSize lines, and the pending lines after the flush interval.
+// The log timestamps without a time zone are read in the log location.
+func NewShipper(sink Sink, checkpoints *Checkpoints, batchSize int, flushInterval time.Duration, logLocation *time.Location) *Shipper {
+	return &Shipper{
+		sink:          sink,
+		checkpoints:   checkpoints,
+		batchSize:     batchSize,
+		flushInterval: flushInterval,
+		logLocation:   logLocation,
+		notices:       os.Stderr,
+		now:           time.Now,
+		events:        make(chan Event, batchSize),
+	}
+}
+
+// Sets the redactor applied to the lines before they are shipped, redaction is disabled when nil.
+func (s *Shipper) SetRedactor(redactor *redact.Redactor) {
+	s.redactor = redactor
+}
+
+// Ships the log files of the sources until the context is done, starting from their checkpoints, or from the
+// beginning of the log files without checkpoint. A batch which cannot be sent, other than for a temporary failure
+// such as 429 or 5xx, stops the shipping with an error.
+func (s *Shipper) Ship(ctx context.Context, logClient *client.Client, sources []stream.Source) error {
+	shipCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	errs := make(chan error, len(sources)+1)
+	go func() {
+		errs <- s.run(shipCtx)
+	}()
+	for _, source := range sources {
+		go func(source stream.Source) {
+			err := s.follow(shipCtx, logClient, source)
+			if err != nil {
+				err = fmt.Errorf("%s %s: %w", source.NodeId, source.LogName, err)
+			}
+			errs <- err
+		}(source)
+	}
+
+	var firstErr error
+	for i := 0; i < len(sources)+1; i++ {
+		if err := <-errs; err != nil && firstErr == nil {
+			firstErr = err
+			cancel()
+		}
+	}
+	return firstErr
+}
+
+// Polls a log file from its checkpoint, and queues its complete lines.
+func (s *Shipper) follow(ctx context.Context, logClient *client.Client, source stream.Source) error {
+	serviceLayer, err := logClient.NewStreamService(source.NodeId, source.LogName)
+	if err != nil {
+		return err
+	}
+	offset, _ := s.checkpoints.Get(source)
+	serviceLayer.SetLastPageMarker(offset)
+	splitter := &lineSplitter{source: source, offset: offset, logLocation: s.logLocation, now: s.now}
+	return logClient.Follow(ctx, serviceLayer, func(content []byte) error {
+		for _, event := range splitter.split(content, serviceLayer.GetLastPageMarker()) {
+			if s.redactor != nil {
+				event.Text = s.redactor.Redact(event.Text)
+			}
+			select {
+			case s.events <- event:
+			case <-ctx.Done():
+				return nil
+			}
+		}
+		return nil
+	})
+}
+
+// Sends the queued lines in batches, and moves the checkpoints once a batch is accepted.
+func (s *Shipper) run(ctx context.Context) error {
+	ticker := time.NewTicker(s.flushInterval)
+	defer ticker.Stop()
+	var batch []Event
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case event := <-s.events:
+			batch = append(batch, event)
+			if len(batch) < s.batchSize {
+				continue
+			}
+		case <-ticker.C:
+			if len(batch) == 0 {
+				continue
+			}
+		}
+		err := s.flush(ctx, batch)
+		if err != nil {
+			return err
+		}
+		batch = nil
+	}
+}
+
+// Sends a batch until it is accepted, and commits the offsets of its lines. The batch is dropped when the context
+// is done, its lines are shipped again from the checkpoints by the next shipping.
+func (s *Shipper) flush(ctx context.Context, batch []Event) error {
+	delay := minRetryDelay
+	for {
+		err := s.sink.Send(ctx, batch)
+		if err == nil {
+			break
+		}
+		if ctx.Err() != nil {
+			return nil
+		}
+		if !isRetryable(err) {
+			return err
+		}
+		fmt.Fprintf(s.notices, "Failed to ship %d lines, retrying in %s: %s\n", len(batch), delay, err)
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-time.After(delay):
+		}
+		delay *= 2
+		if delay > maxRetryDelay {
+			delay = maxRetryDelay
+		}
+	}
+	offsets := map[stream.Source]int64{}
+	for _, event := range batch {
+		offsets[event.Source] = event.Offset
+	}
+	return s.checkpoints.Commit(offsets)
+}
+
+// Splits the content of a log file into lines, keeping the offset of each line. A trailing partial line is held back
+// until its end is received.
+type lineSplitter struct {
+	source  stream.Source
+	pending []byte
+	// The offset following the received content.
+	offset      int64
+	lastTime    time.Time
+	logLocation *time.Location
+	now         func() time.Time
+}
+
+// Returns the complete lines of the content, which ends at the page marker. Content which does not follow the
+// received content belongs to a new log file, as the log file was rotated, and the held back partial line is
+// returned first.
+func (l *lineSplitter) split(content []byte, pageMarker int64) []Event {
+	var events []Event
+	if start := pageMarker - int64(len(content)); start != l.offset {
+		if len(l.pending) > 0 {
+			events = append(events, l.event(string(l.pending), l.offset))
+		}
+		l.pending = nil
+	}
+	l.pending = append(l.pending, content...)
+	l.offset = pageMarker
+
+	pendingStart := pageMarker - int64(len(l.pending))
+	lineStart := 0
+	for i, b := range l.pending {
+		if b != '\n' {
+			continue
+		}
+		text := strings.TrimSuffix(string(l.pending[lineStart:i]), "\r")
+		if text != "" {
+			events = append(events, l.event(text, pendingStart+int64(i)+1))
+		}
+		lineStart = i + 1
+	}
+	l.pending = append([]byte(nil), l.pending[lineStart:]...)
+	return events
+}
+
+func (l *lineSplitter) event(text string, offset int64) Event {
+	if lineTime, found := timerange.ParseLineTime(text, l.logLocation); found {
+		l.lastTime = lineTime
+	} else if l.lastTime.IsZero() {
+		l.lastTime = l.now()
+	}
+	return Event{Source: l.source, Time: l.lastTime, Text: text, Offset: offset}
+}
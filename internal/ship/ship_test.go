@@ -0,0 +1,137 @@
+package ship
+
+import (
+	"context"
+	"fmt"
+	"github.com/jfrog/live-logs/internal/client"
+	"github.com/jfrog/live-logs/internal/clientlayer"
+	"github.com/jfrog/live-logs/internal/constants"
+	"github.com/jfrog/live-logs/internal/servicelayer"
+	"github.com/jfrog/live-logs/internal/stream"
+	"github.com/jfrog/live-logs/internal/testserver"
+	"github.com/stretchr/testify/require"
+	"io/ioutil"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestLineSplitter(t *testing.T) {
+	start := time.Date(2021, 3, 25, 4, 0, 0, 0, time.UTC)
+	splitter := &lineSplitter{logLocation: time.UTC, now: func() time.Time { return start }}
+	texts := func(events []Event) []string {
+		var result []string
+		for _, event := range events {
+			result = append(result, fmt.Sprintf("%d %s %s", event.Offset, event.Time.Format("15:04:05"), event.Text))
+		}
+		return result
+	}
+
+	require.Equal(t, []string{"11 04:00:00 first line"}, texts(splitter.split([]byte("first line\n2021-03-25T05:00:00Z sec"), 35)))
+	require.Empty(t, splitter.split(nil, 35))
+	require.Equal(t, []string{"40 05:00:00 2021-03-25T05:00:00Z second", "56 05:00:00 \tat stack.trace"},
+		texts(splitter.split([]byte("ond\r\n\tat stack.trace\n"), 56)))
+
+	// The log file was rotated, its content starts over.
+	splitter.split([]byte("partial"), 63)
+	require.Equal(t, []string{"63 05:00:00 partial", "9 05:00:00 new file"}, texts(splitter.split([]byte("new file\n"), 9)))
+}
+
+// A sink keeping the shipped lines, failing the first requests.
+type memorySink struct {
+	lock     sync.Mutex
+	failures int
+	lines    []string
+	received chan struct{}
+}
+
+func (s *memorySink) Send(_ context.Context, events []Event) error {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	if s.failures > 0 {
+		s.failures--
+		return &retryableError{fmt.Errorf("unavailable")}
+	}
+	for _, event := range events {
+		s.lines = append(s.lines, event.Text)
+	}
+	s.received <- struct{}{}
+	return nil
+}
+
+func (s *memorySink) shipped() []string {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	return append([]string(nil), s.lines...)
+}
+
+func TestShipper(t *testing.T) {
+	realMinRetryDelay := minRetryDelay
+	defer func() { minRetryDelay = realMinRetryDelay }()
+	minRetryDelay = time.Millisecond
+
+	server := testserver.New(t, "some-token")
+	server.AddProduct(constants.ArtifactoryId, testserver.Product{Version: "7.41.0", Nodes: []string{"node-1"}, LogFileNames: []string{"one.log"}})
+	realRequestsPerSecond := clientlayer.GetRequestsPerSecond()
+	defer clientlayer.SetRequestsPerSecond(realRequestsPerSecond)
+	clientlayer.SetRequestsPerSecond(0)
+	serverId := clientlayer.RegisterPlatform(server.URL, "some-token")
+	logClient := client.New(constants.ArtifactoryId, serverId, servicelayer.NewService)
+	logClient.SetFixedRefreshRate(5 * time.Millisecond)
+	sources := []stream.Source{{ServerId: serverId, ProductId: constants.ArtifactoryId, NodeId: "node-1", LogName: "one.log"}}
+	checkpointPath := filepath.Join(t.TempDir(), "checkpoints.json")
+
+	ship := func(sink *memorySink, wantLines []string) {
+		checkpoints, err := LoadCheckpoints(checkpointPath)
+		require.NoError(t, err)
+		shipper := NewShipper(sink, checkpoints, 10, 5*time.Millisecond, time.UTC)
+		shipper.notices = ioutil.Discard
+		ctx, cancel := context.WithCancel(context.Background())
+		done := make(chan error)
+		go func() {
+			done <- shipper.Ship(ctx, logClient, sources)
+		}()
+		for len(sink.shipped()) < len(wantLines) {
+			select {
+			case <-sink.received:
+			case <-time.After(10 * time.Second):
+				t.Fatal("the lines were not shipped")
+			}
+		}
+		cancel()
+		require.NoError(t, <-done)
+		require.Equal(t, wantLines, sink.shipped())
+	}
+
+	server.AppendLog(constants.ArtifactoryId, "node-1", "one.log", "first line\nsecond line\npartial")
+	ship(&memorySink{failures: 2, received: make(chan struct{}, 10)}, []string{"first line", "second line"})
+
+	// The next shipping resumes from the checkpoint.
+	server.AppendLog(constants.ArtifactoryId, "node-1", "one.log", " line\nthird line\n")
+	ship(&memorySink{received: make(chan struct{}, 10)}, []string{"partial line", "third line"})
+}
+
+func TestShipper_PermanentFailure(t *testing.T) {
+	server := testserver.New(t, "some-token")
+	server.AddProduct(constants.ArtifactoryId, testserver.Product{Version: "7.41.0", Nodes: []string{"node-1"}, LogFileNames: []string{"one.log"}})
+	server.AppendLog(constants.ArtifactoryId, "node-1", "one.log", "first line\n")
+	serverId := clientlayer.RegisterPlatform(server.URL, "some-token")
+	logClient := client.New(constants.ArtifactoryId, serverId, servicelayer.NewService)
+	logClient.SetFixedRefreshRate(5 * time.Millisecond)
+
+	sinkServer, _ := newSinkServer(t, 400, "invalid")
+	sink, err := NewSink(SinkConfig{Type: HttpSink, Url: sinkServer.URL})
+	require.NoError(t, err)
+	checkpoints, err := LoadCheckpoints(filepath.Join(t.TempDir(), "checkpoints.json"))
+	require.NoError(t, err)
+	shipper := NewShipper(sink, checkpoints, 10, 5*time.Millisecond, time.UTC)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	err = shipper.Ship(ctx, logClient, []stream.Source{{ServerId: serverId, ProductId: constants.ArtifactoryId, NodeId: "node-1", LogName: "one.log"}})
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "answered with status 400")
+	_, found := checkpoints.Get(stream.Source{ServerId: serverId, ProductId: constants.ArtifactoryId, NodeId: "node-1", LogName: "one.log"})
+	require.False(t, found)
+}
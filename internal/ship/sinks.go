@@ -0,0 +1,379 @@
+package ship
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"github.com/jfrog/live-logs/internal/format"
+	"github.com/jfrog/live-logs/internal/stream"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	LokiSink          = "loki"
+	ElasticsearchSink = "elasticsearch"
+	SplunkSink        = "splunk"
+	HttpSink          = "http"
+	OtlpSink          = "otlp"
+
+	DefaultIndex = "jfrog-logs"
+
+	lokiPushPath  = "/loki/api/v1/push"
+	bulkPath      = "/_bulk"
+	splunkHecPath = "/services/collector/event"
+	otlpLogsPath  = "/v1/logs"
+)
+
+// Receives the batches of shipped lines.
+type Sink interface {
+	Send(ctx context.Context, events []Event) error
+}
+
+// The destination of the shipped lines.
+type SinkConfig struct {
+	// One of loki, elasticsearch, splunk, http or otlp.
+	Type string
+	// The base url of Loki, Elasticsearch, Splunk or the OTLP receiver, the push endpoint is appended to it.
+	// The full endpoint url for http.
+	Url   string
+	Token string
+	// The Elasticsearch or Splunk index.
+	Index string
+	// Additional headers sent with every request, such as the API key of an OTLP receiver.
+	Headers map[string]string
+}
+
+// Returns the names of the supported sinks.
+func SinkTypes() []string {
+	return []string{LokiSink, ElasticsearchSink, SplunkSink, HttpSink, OtlpSink}
+}
+
+func NewSink(config SinkConfig) (Sink, error) {
+	if !isSinkType(config.Type) {
+		return nil, fmt.Errorf("invalid sink [%s], expected one of %s", config.Type, strings.Join(SinkTypes(), ", "))
+	}
+	if config.Url == "" {
+		return nil, fmt.Errorf("the url of the %s sink must be set", config.Type)
+	}
+	baseUrl := strings.TrimSuffix(config.Url, "/")
+	client := &http.Client{Timeout: time.Minute}
+	switch config.Type {
+	case LokiSink:
+		return &lokiSink{newHttpSink(client, withPath(baseUrl, lokiPushPath), bearer(config.Token), config.Headers)}, nil
+	case ElasticsearchSink:
+		authorization := ""
+		if config.Token != "" {
+			authorization = "ApiKey " + config.Token
+		}
+		return &elasticsearchSink{httpSink: newHttpSink(client, withPath(baseUrl, bulkPath), authorization, config.Headers), index: indexOrDefault(config.Index)}, nil
+	case SplunkSink:
+		if config.Token == "" {
+			return nil, fmt.Errorf("the token of the %s sink must be set", config.Type)
+		}
+		return &splunkSink{httpSink: newHttpSink(client, withPath(baseUrl, splunkHecPath), "Splunk "+config.Token, config.Headers), index: config.Index}, nil
+	case OtlpSink:
+		return &otlpSink{httpSink: newHttpSink(client, withPath(baseUrl, otlpLogsPath), bearer(config.Token), config.Headers), notices: os.Stderr, now: time.Now}, nil
+	default:
+		sink := newHttpSink(client, config.Url, bearer(config.Token), config.Headers)
+		return &sink, nil
+	}
+}
+
+func isSinkType(sinkType string) bool {
+	for _, known := range SinkTypes() {
+		if sinkType == known {
+			return true
+		}
+	}
+	return false
+}
+
+func withPath(baseUrl, path string) string {
+	if strings.HasSuffix(baseUrl, path) {
+		return baseUrl
+	}
+	return baseUrl + path
+}
+
+func bearer(token string) string {
+	if token == "" {
+		return ""
+	}
+	return "Bearer " + token
+}
+
+func indexOrDefault(index string) string {
+	if index == "" {
+		return DefaultIndex
+	}
+	return index
+}
+
+// Returned for the responses which may succeed when sent again, such as 429 and 5xx.
+type retryableError struct {
+	err error
+}
+
+func (e *retryableError) Error() string {
+	return e.err.Error()
+}
+
+func isRetryable(err error) bool {
+	_, ok := err.(*retryableError)
+	return ok
+}
+
+// Sends the events as a JSON array, each event with its origin, its time, its line and its parsed fields.
+type httpSink struct {
+	client        *http.Client
+	url           string
+	authorization string
+	headers       map[string]string
+}
+
+func newHttpSink(client *http.Client, url, authorization string, headers map[string]string) httpSink {
+	return httpSink{client: client, url: url, authorization: authorization, headers: headers}
+}
+
+// Parses a comma separated list of key=value headers, the values are URL encoded, such as
+// "x-api-key=abc,x-tenant=acme". This is the format of the OTEL_EXPORTER_OTLP_HEADERS environment variable.
+func ParseHeaders(value string) (map[string]string, error) {
+	headers := map[string]string{}
+	for _, pair := range strings.Split(value, ",") {
+		if strings.TrimSpace(pair) == "" {
+			continue
+		}
+		parts := strings.SplitN(pair, "=", 2)
+		key := strings.TrimSpace(parts[0])
+		if len(parts) != 2 || key == "" {
+			return nil, fmt.Errorf("invalid header [%s], expected key=value", pair)
+		}
+		headerValue, err := url.QueryUnescape(strings.TrimSpace(parts[1]))
+		if err != nil {
+			return nil, fmt.Errorf("invalid header [%s]: %w", pair, err)
+		}
+		headers[key] = headerValue
+	}
+	return headers, nil
+}
+
+type httpEvent struct {
+	Time    time.Time         `json:"time"`
+	Server  string            `json:"server"`
+	Product string            `json:"product"`
+	Node    string            `json:"node"`
+	Log     string            `json:"log"`
+	Line    string            `json:"line"`
+	Fields  map[string]string `json:"fields"`
+}
+
+func (s *httpSink) Send(ctx context.Context, events []Event) error {
+	var body []httpEvent
+	for _, event := range events {
+		body = append(body, httpEvent{
+			Time:    event.Time,
+			Server:  event.Source.ServerId,
+			Product: event.Source.ProductId,
+			Node:    event.Source.NodeId,
+			Log:     event.Source.LogName,
+			Line:    event.Text,
+			Fields:  fieldsOf(event),
+		})
+	}
+	data, err := json.Marshal(body)
+	if err != nil {
+		return err
+	}
+	_, err = s.post(ctx, "application/json", data)
+	return err
+}
+
+// Posts the body and returns the response body, the failed requests are returned as errors.
+func (s *httpSink) post(ctx context.Context, contentType string, body []byte) ([]byte, error) {
+	request, err := http.NewRequestWithContext(ctx, http.MethodPost, s.url, bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	request.Header.Set("Content-Type", contentType)
+	for key, value := range s.headers {
+		request.Header.Set(key, value)
+	}
+	if s.authorization != "" {
+		request.Header.Set("Authorization", s.authorization)
+	}
+	response, err := s.client.Do(request)
+	if err != nil {
+		return nil, &retryableError{err}
+	}
+	defer response.Body.Close()
+	responseBody, err := ioutil.ReadAll(io.LimitReader(response.Body, 1024*1024))
+	if err != nil {
+		return nil, &retryableError{err}
+	}
+	if response.StatusCode >= 200 && response.StatusCode < 300 {
+		return responseBody, nil
+	}
+	err = fmt.Errorf("%s answered with status %d: %s", s.url, response.StatusCode, strings.TrimSpace(string(responseBody)))
+	if response.StatusCode == http.StatusTooManyRequests || response.StatusCode == http.StatusRequestTimeout || response.StatusCode >= 500 {
+		return nil, &retryableError{err}
+	}
+	return nil, err
+}
+
+// Pushes the events to the Loki push API, with a stream for each origin labeled with the server, product, node and log.
+type lokiSink struct {
+	httpSink
+}
+
+type lokiStream struct {
+	Stream map[string]string `json:"stream"`
+	Values [][2]string       `json:"values"`
+}
+
+func (s *lokiSink) Send(ctx context.Context, events []Event) error {
+	var streams []*lokiStream
+	bySource := map[stream.Source]*lokiStream{}
+	for _, event := range events {
+		lokiStream, found := bySource[event.Source]
+		if !found {
+			lokiStream = newLokiStream(event.Source)
+			bySource[event.Source] = lokiStream
+			streams = append(streams, lokiStream)
+		}
+		lokiStream.Values = append(lokiStream.Values, [2]string{strconv.FormatInt(event.Time.UnixNano(), 10), event.Text})
+	}
+	data, err := json.Marshal(map[string]interface{}{"streams": streams})
+	if err != nil {
+		return err
+	}
+	_, err = s.post(ctx, "application/json", data)
+	return err
+}
+
+func newLokiStream(source stream.Source) *lokiStream {
+	return &lokiStream{Stream: map[string]string{
+		"server":  source.ServerId,
+		"product": source.ProductId,
+		"node":    source.NodeId,
+		"log":     source.LogName,
+	}}
+}
+
+// Indexes the events with the Elasticsearch bulk API. The document id is derived from the origin and the offset of the
+// line, so a batch sent again after a failure does not duplicate the documents.
+type elasticsearchSink struct {
+	httpSink
+	index string
+}
+
+type bulkResponse struct {
+	Errors bool `json:"errors"`
+	Items  []map[string]struct {
+		Status int             `json:"status"`
+		Error  json.RawMessage `json:"error"`
+	} `json:"items"`
+}
+
+func (s *elasticsearchSink) Send(ctx context.Context, events []Event) error {
+	var body bytes.Buffer
+	encoder := json.NewEncoder(&body)
+	for _, event := range events {
+		err := encoder.Encode(map[string]interface{}{"index": map[string]string{"_index": s.index, "_id": documentId(event)}})
+		if err != nil {
+			return err
+		}
+		document := map[string]interface{}{"@timestamp": event.Time, "message": event.Text}
+		for key, value := range fieldsOf(event) {
+			if key != "time" && key != "msg" {
+				document[key] = value
+			}
+		}
+		err = encoder.Encode(document)
+		if err != nil {
+			return err
+		}
+	}
+	responseBody, err := s.post(ctx, "application/x-ndjson", body.Bytes())
+	if err != nil {
+		return err
+	}
+	var response bulkResponse
+	err = json.Unmarshal(responseBody, &response)
+	if err != nil || !response.Errors {
+		return err
+	}
+	for _, item := range response.Items {
+		for _, result := range item {
+			if result.Status >= 300 {
+				err = fmt.Errorf("%s failed to index a document with status %d: %s", s.url, result.Status, result.Error)
+				if result.Status == http.StatusTooManyRequests || result.Status >= 500 {
+					return &retryableError{err}
+				}
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func documentId(event Event) string {
+	hash := sha256.Sum256([]byte(checkpointKey(event.Source) + "@" + strconv.FormatInt(event.Offset, 10)))
+	return hex.EncodeToString(hash[:16])
+}
+
+// Sends the events to the Splunk HTTP Event Collector, with the node as host, the log file as source and the
+// product as source type.
+type splunkSink struct {
+	httpSink
+	index string
+}
+
+type splunkEvent struct {
+	// The epoch time in seconds, with the milliseconds as decimals.
+	Time       json.Number       `json:"time"`
+	Host       string            `json:"host"`
+	Source     string            `json:"source"`
+	SourceType string            `json:"sourcetype"`
+	Index      string            `json:"index,omitempty"`
+	Event      string            `json:"event"`
+	Fields     map[string]string `json:"fields"`
+}
+
+func (s *splunkSink) Send(ctx context.Context, events []Event) error {
+	var body bytes.Buffer
+	encoder := json.NewEncoder(&body)
+	for _, event := range events {
+		err := encoder.Encode(splunkEvent{
+			Time:       json.Number(fmt.Sprintf("%d.%03d", event.Time.Unix(), event.Time.Nanosecond()/int(time.Millisecond))),
+			Host:       event.Source.NodeId,
+			Source:     event.Source.LogName,
+			SourceType: event.Source.ProductId,
+			Index:      s.index,
+			Event:      event.Text,
+			Fields:     map[string]string{"server": event.Source.ServerId, "product": event.Source.ProductId, "node": event.Source.NodeId, "log": event.Source.LogName},
+		})
+		if err != nil {
+			return err
+		}
+	}
+	_, err := s.post(ctx, "application/json", body.Bytes())
+	return err
+}
+
+func fieldsOf(event Event) map[string]string {
+	fields := map[string]string{}
+	for _, field := range format.Parse(stream.Line{Source: event.Source, Text: event.Text}).Fields() {
+		fields[field.Key] = field.Value
+	}
+	return fields
+}
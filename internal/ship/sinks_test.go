@@ -0,0 +1,238 @@
+package ship
+
+import (
+	"context"
+	"encoding/json"
+	"github.com/jfrog/live-logs/internal/stream"
+	"github.com/stretchr/testify/require"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+var testEvents = []Event{
+	{
+		Source: stream.Source{ServerId: "local-rt", ProductId: "rt", NodeId: "node1", LogName: "artifactory-request.log"},
+		Time:   time.Date(2021, 3, 25, 4, 30, 34, 199000000, time.UTC),
+		Text:   "2021-03-25T04:30:34.199Z|94109ae150da76e|127.0.0.1|admin|GET|/api/release/bundles|200|-1|0|3",
+		Offset: 93,
+	},
+}
+
+// A request log line of a recent version, ending with the user agent.
+var userAgentEvent = Event{
+	Source: stream.Source{ServerId: "local-rt", ProductId: "rt", NodeId: "node1", LogName: "artifactory-request.log"},
+	Time:   time.Date(2021, 3, 25, 4, 0, 0, 6000000, time.UTC),
+	Text:   "2021-03-25T04:00:00.006Z|d76675e362ffbd6a|10.0.0.1|admin|GET|/api/system/ping|200|-1|2|3|JFrog-Router/7.17.0",
+	Offset: 108,
+}
+
+// A sink endpoint recording the last request.
+type recordedRequest struct {
+	path          string
+	authorization string
+	body          string
+}
+
+func newSinkServer(t *testing.T, statusCode int, responseBody string) (*httptest.Server, *recordedRequest) {
+	recorded := &recordedRequest{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := ioutil.ReadAll(r.Body)
+		*recorded = recordedRequest{path: r.URL.Path, authorization: r.Header.Get("Authorization"), body: string(body)}
+		w.WriteHeader(statusCode)
+		_, _ = w.Write([]byte(responseBody))
+	}))
+	t.Cleanup(server.Close)
+	return server, recorded
+}
+
+func TestSinks(t *testing.T) {
+	tests := []struct {
+		name              string
+		config            SinkConfig
+		responseBody      string
+		wantPath          string
+		wantAuthorization string
+		wantBody          string
+	}{
+		{
+			name:              "loki",
+			config:            SinkConfig{Type: LokiSink, Token: "some-token"},
+			wantPath:          "/loki/api/v1/push",
+			wantAuthorization: "Bearer some-token",
+			wantBody: `{"streams":[{"stream":{"log":"artifactory-request.log","node":"node1","product":"rt","server":"local-rt"},` +
+				`"values":[["1616646634199000000","2021-03-25T04:30:34.199Z|94109ae150da76e|127.0.0.1|admin|GET|/api/release/bundles|200|-1|0|3"]]}]}`,
+		},
+		{
+			name:              "splunk",
+			config:            SinkConfig{Type: SplunkSink, Token: "some-token", Index: "jfrog"},
+			wantPath:          "/services/collector/event",
+			wantAuthorization: "Splunk some-token",
+			wantBody: `{"time":1616646634.199,"host":"node1","source":"artifactory-request.log","sourcetype":"rt","index":"jfrog",` +
+				`"event":"2021-03-25T04:30:34.199Z|94109ae150da76e|127.0.0.1|admin|GET|/api/release/bundles|200|-1|0|3",` +
+				`"fields":{"log":"artifactory-request.log","node":"node1","product":"rt","server":"local-rt"}}` + "\n",
+		},
+		{
+			name:              "elasticsearch",
+			config:            SinkConfig{Type: ElasticsearchSink, Token: "some-key"},
+			responseBody:      `{"errors":false,"items":[]}`,
+			wantPath:          "/_bulk",
+			wantAuthorization: "ApiKey some-key",
+			wantBody: `{"index":{"_id":"` + documentId(testEvents[0]) + `","_index":"jfrog-logs"}}` + "\n" +
+				`{"@timestamp":"2021-03-25T04:30:34.199Z","duration_ms":"3","log":"artifactory-request.log","message":"2021-03-25T04:30:34.199Z|94109ae150da76e|127.0.0.1|admin|GET|/api/release/bundles|200|-1|0|3",` +
+				`"method":"GET","node":"node1","product":"rt","remote_address":"127.0.0.1","request_size":"-1","response_size":"0","server":"local-rt",` +
+				`"status":"200","trace_id":"94109ae150da76e","url":"/api/release/bundles","username":"admin"}` + "\n",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server, recorded := newSinkServer(t, http.StatusOK, tt.responseBody)
+			tt.config.Url = server.URL
+			sink, err := NewSink(tt.config)
+			require.NoError(t, err)
+			require.NoError(t, sink.Send(context.Background(), testEvents))
+			require.Equal(t, tt.wantPath, recorded.path)
+			require.Equal(t, tt.wantAuthorization, recorded.authorization)
+			require.Equal(t, tt.wantBody, recorded.body)
+		})
+	}
+}
+
+func TestHttpSink(t *testing.T) {
+	server, recorded := newSinkServer(t, http.StatusAccepted, "")
+	sink, err := NewSink(SinkConfig{Type: HttpSink, Url: server.URL + "/ingest"})
+	require.NoError(t, err)
+	require.NoError(t, sink.Send(context.Background(), testEvents))
+	require.Equal(t, "/ingest", recorded.path)
+	require.Empty(t, recorded.authorization)
+
+	var events []httpEvent
+	require.NoError(t, json.Unmarshal([]byte(recorded.body), &events))
+	require.Len(t, events, 1)
+	require.Equal(t, "node1", events[0].Node)
+	require.Equal(t, "GET", events[0].Fields["method"])
+	require.True(t, testEvents[0].Time.Equal(events[0].Time))
+}
+
+func TestSinks_RequestFields(t *testing.T) {
+	wantFields := map[string]string{"method": "GET", "url": "/api/system/ping", "status": "200", "duration_ms": "3", "user_agent": "JFrog-Router/7.17.0"}
+	tests := []struct {
+		name         string
+		config       SinkConfig
+		responseBody string
+		// Returns the fields of the sent event.
+		fields func(t *testing.T, body string) map[string]string
+	}{
+		{
+			name:   "http",
+			config: SinkConfig{Type: HttpSink},
+			fields: func(t *testing.T, body string) map[string]string {
+				var events []httpEvent
+				require.NoError(t, json.Unmarshal([]byte(body), &events))
+				require.Len(t, events, 1)
+				return events[0].Fields
+			},
+		},
+		{
+			name:         "elasticsearch",
+			config:       SinkConfig{Type: ElasticsearchSink},
+			responseBody: `{"errors":false,"items":[]}`,
+			fields: func(t *testing.T, body string) map[string]string {
+				lines := strings.Split(strings.TrimSuffix(body, "\n"), "\n")
+				require.Len(t, lines, 2)
+				var document map[string]string
+				require.NoError(t, json.Unmarshal([]byte(lines[1]), &document))
+				return document
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server, recorded := newSinkServer(t, http.StatusOK, tt.responseBody)
+			tt.config.Url = server.URL
+			sink, err := NewSink(tt.config)
+			require.NoError(t, err)
+			require.NoError(t, sink.Send(context.Background(), []Event{userAgentEvent}))
+			fields := tt.fields(t, recorded.body)
+			for key, value := range wantFields {
+				require.Equal(t, value, fields[key], key)
+			}
+		})
+	}
+}
+
+func TestSink_Errors(t *testing.T) {
+	tests := []struct {
+		name          string
+		statusCode    int
+		responseBody  string
+		wantRetryable bool
+	}{
+		{"too many requests", http.StatusTooManyRequests, "slow down", true},
+		{"unavailable", http.StatusServiceUnavailable, "", true},
+		{"bad request", http.StatusBadRequest, "invalid", false},
+		{"rejected document", http.StatusOK, `{"errors":true,"items":[{"index":{"status":400,"error":{"type":"mapper_parsing_exception"}}}]}`, false},
+		{"rejected document for load", http.StatusOK, `{"errors":true,"items":[{"index":{"status":429}}]}`, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server, _ := newSinkServer(t, tt.statusCode, tt.responseBody)
+			sink, err := NewSink(SinkConfig{Type: ElasticsearchSink, Url: server.URL})
+			require.NoError(t, err)
+			err = sink.Send(context.Background(), testEvents)
+			require.Error(t, err)
+			require.Equal(t, tt.wantRetryable, isRetryable(err))
+		})
+	}
+}
+
+func TestNewSink_Invalid(t *testing.T) {
+	_, err := NewSink(SinkConfig{Type: "kafka", Url: "http://localhost"})
+	require.EqualError(t, err, "invalid sink [kafka], expected one of loki, elasticsearch, splunk, http, otlp")
+	_, err = NewSink(SinkConfig{Type: LokiSink})
+	require.EqualError(t, err, "the url of the loki sink must be set")
+	_, err = NewSink(SinkConfig{Type: SplunkSink, Url: "http://localhost"})
+	require.True(t, strings.HasPrefix(err.Error(), "the token of the splunk sink"))
+}
+
+func TestParseHeaders(t *testing.T) {
+	tests := []struct {
+		name    string
+		value   string
+		want    map[string]string
+		wantErr string
+	}{
+		{"empty", "", map[string]string{}, ""},
+		{"single", "x-api-key=abc", map[string]string{"x-api-key": "abc"}, ""},
+		{"several", "x-api-key=abc, x-tenant=acme,", map[string]string{"x-api-key": "abc", "x-tenant": "acme"}, ""},
+		{"encoded", "Authorization=Basic%20dXNlcjpwYXNz", map[string]string{"Authorization": "Basic dXNlcjpwYXNz"}, ""},
+		{"missing value", "x-api-key", nil, "invalid header [x-api-key], expected key=value"},
+		{"missing key", "=abc", nil, "invalid header [=abc], expected key=value"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			headers, err := ParseHeaders(tt.value)
+			if tt.wantErr != "" {
+				require.EqualError(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tt.want, headers)
+		})
+	}
+}
+
+func TestSink_Headers(t *testing.T) {
+	var tenant string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		tenant = r.Header.Get("X-Scope-OrgID")
+	}))
+	defer server.Close()
+	sink, err := NewSink(SinkConfig{Type: LokiSink, Url: server.URL, Headers: map[string]string{"X-Scope-OrgID": "acme"}})
+	require.NoError(t, err)
+	require.NoError(t, sink.Send(context.Background(), testEvents))
+	require.Equal(t, "acme", tenant)
+}
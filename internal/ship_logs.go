@@ -0,0 +1,58 @@
+package livelog
+
+import (
+	"context"
+	"github.com/jfrog/live-logs/internal/ship"
+	"github.com/jfrog/live-logs/internal/stream"
+	"github.com/jfrog/live-logs/internal/util"
+)
+
+// Resolves the node ids and log file names on the server, and ships the log files with the shipper, see ship.Shipper.
+// The nodes are resolved once, the nodes which join later are not shipped.
+func (s *Data) Ship(ctx context.Context, cliProductId, cliServerId, nodeId, logName string, shipper *ship.Shipper) error {
+	sources, err := s.resolveSources(ctx, cliProductId, cliServerId, nodeId, logName)
+	if err != nil {
+		return err
+	}
+	shipper.SetRedactor(s.redactor)
+	return shipper.Ship(ctx, s.client(), sources)
+}
+
+// Selects the product and server, and returns a source for each selected node id and log file name of the server.
+func (s *Data) resolveSources(ctx context.Context, cliProductId, cliServerId, nodeId, logName string) ([]stream.Source, error) {
+	err := util.ValidateArgument("product id", cliProductId, util.FetchAllProductIds())
+	if err != nil {
+		return nil, err
+	}
+	err = util.ValidateArgument("server id", cliServerId, getAllServiceIds())
+	if err != nil {
+		return nil, err
+	}
+	s.SetProductId(cliProductId)
+	s.SetServiceId(cliServerId)
+	err = s.SetServiceLayer(cliProductId)
+	if err != nil {
+		return nil, err
+	}
+	srvConfig, err := s.GetServiceLayer().GetConfig(ctx, cliServerId)
+	if err != nil {
+		return nil, err
+	}
+	s.SetLogsRefreshRate(util.MillisToDuration(srvConfig.RefreshRateMillis))
+	nodeIds, err := util.ParseSelection("node id", nodeId, srvConfig.Nodes)
+	if err != nil {
+		return nil, err
+	}
+	logNames, err := util.ParseSelection("log name", logName, srvConfig.LogFileNames)
+	if err != nil {
+		return nil, err
+	}
+
+	var sources []stream.Source
+	for _, selectedNodeId := range nodeIds {
+		for _, selectedLogName := range logNames {
+			sources = append(sources, stream.Source{ServerId: cliServerId, ProductId: cliProductId, NodeId: selectedNodeId, LogName: selectedLogName})
+		}
+	}
+	return sources, nil
+}
@@ -0,0 +1,92 @@
+package stream
+
+import (
+	"bytes"
+	"time"
+	"unicode/utf8"
+)
+
+// Splits consecutive chunks of log data into complete lines.
+// A trailing partial line is held back until a following chunk completes it, or until it is explicitly flushed.
+type LineBuffer struct {
+	source       Source
+	pending      []byte
+	pendingSince time.Time
+	// Whether the start of the pending line was already emitted as a partial line.
+	continued bool
+}
+
+func NewLineBuffer(source Source) *LineBuffer {
+	return &LineBuffer{source: source}
+}
+
+// Adds a chunk and returns the lines it completes.
+func (b *LineBuffer) Write(chunk []byte) []Line {
+	if len(chunk) == 0 {
+		return nil
+	}
+	if len(b.pending) == 0 {
+		b.pendingSince = time.Now()
+	}
+	b.pending = append(b.pending, chunk...)
+
+	var lines []Line
+	for {
+		newLineIndex := bytes.IndexByte(b.pending, '\n')
+		if newLineIndex < 0 {
+			break
+		}
+		lines = append(lines, Line{Source: b.source, Text: string(b.pending[:newLineIndex]), Continuation: b.continued})
+		b.continued = false
+		b.pending = b.pending[newLineIndex+1:]
+	}
+	if len(b.pending) == 0 {
+		b.pending = nil
+	} else if len(lines) > 0 {
+		b.pendingSince = time.Now()
+	}
+	return lines
+}
+
+// Returns the held back partial line, if it has been waiting for at least the given timeout.
+// An incomplete multi-byte UTF-8 sequence at its end is kept, as its remaining bytes are expected in the next chunk.
+func (b *LineBuffer) FlushStale(timeout time.Duration) []Line {
+	if len(b.pending) == 0 || time.Since(b.pendingSince) < timeout {
+		return nil
+	}
+	completeLength := len(b.pending) - incompleteRuneLength(b.pending)
+	if completeLength == 0 {
+		return nil
+	}
+	line := Line{Source: b.source, Text: string(b.pending[:completeLength]), Partial: true, Continuation: b.continued}
+	b.continued = true
+	b.pending = b.pending[completeLength:]
+	b.pendingSince = time.Now()
+	return []Line{line}
+}
+
+// Returns whatever is held back, used once no more chunks are expected.
+func (b *LineBuffer) Flush() []Line {
+	if len(b.pending) == 0 {
+		return nil
+	}
+	line := Line{Source: b.source, Text: string(b.pending), Partial: true, Continuation: b.continued}
+	b.continued = false
+	b.pending = nil
+	return []Line{line}
+}
+
+// Returns the number of trailing bytes that start a multi-byte UTF-8 sequence which is not complete yet.
+func incompleteRuneLength(data []byte) int {
+	for i := 1; i < utf8.UTFMax && i <= len(data); i++ {
+		tail := data[len(data)-i:]
+		if !utf8.RuneStart(tail[0]) {
+			continue
+		}
+		if utf8.FullRune(tail) {
+			return 0
+		}
+		return i
+	}
+	return 0
+}
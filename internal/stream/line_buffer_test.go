@@ -0,0 +1,113 @@
+package stream
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+	"time"
+)
+
+func TestLineBufferWrite(t *testing.T) {
+	tests := []struct {
+		name        string
+		chunks      []string
+		wantLines   []string
+		wantPending string
+	}{
+		{
+			name:      "complete lines",
+			chunks:    []string{"one\ntwo\n"},
+			wantLines: []string{"one", "two"},
+		},
+		{
+			name:        "partial line held back",
+			chunks:      []string{"one\ntw"},
+			wantLines:   []string{"one"},
+			wantPending: "tw",
+		},
+		{
+			name:      "partial line completed by the next chunk",
+			chunks:    []string{"one\ntw", "o\nthree\n"},
+			wantLines: []string{"one", "two", "three"},
+		},
+		{
+			name:      "chunk without new line",
+			chunks:    []string{"o", "n", "e\n"},
+			wantLines: []string{"one"},
+		},
+		{
+			name:      "empty lines",
+			chunks:    []string{"\n\none\n"},
+			wantLines: []string{"", "", "one"},
+		},
+		{
+			name:      "multi-byte sequence split across chunks",
+			chunks:    []string{"caf\xc3", "\xa9\n"},
+			wantLines: []string{"café"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			buffer := NewLineBuffer(Source{NodeId: "node1"})
+			var gotLines []string
+			for _, chunk := range tt.chunks {
+				for _, line := range buffer.Write([]byte(chunk)) {
+					assert.Equal(t, "node1", line.Source.NodeId)
+					assert.False(t, line.Partial)
+					gotLines = append(gotLines, line.Text)
+				}
+			}
+			assert.Equal(t, tt.wantLines, gotLines)
+			assert.Equal(t, tt.wantPending, string(buffer.pending))
+		})
+	}
+}
+
+func TestLineBufferFlushStale(t *testing.T) {
+	buffer := NewLineBuffer(Source{})
+	buffer.Write([]byte("one\ntw"))
+	assert.Empty(t, buffer.FlushStale(time.Hour))
+
+	lines := buffer.FlushStale(0)
+	assert.Equal(t, []Line{{Text: "tw", Partial: true}}, lines)
+	assert.Empty(t, buffer.FlushStale(0))
+
+	assert.Equal(t, []Line{{Text: "o", Continuation: true}, {Text: "three"}}, buffer.Write([]byte("o\nthree\n")))
+	assert.Empty(t, buffer.pending)
+}
+
+func TestLineBufferFlushStaleKeepsIncompleteRune(t *testing.T) {
+	buffer := NewLineBuffer(Source{})
+	buffer.Write([]byte("caf\xc3"))
+	assert.Equal(t, []Line{{Text: "caf", Partial: true}}, buffer.FlushStale(0))
+	assert.Empty(t, buffer.FlushStale(0))
+
+	assert.Equal(t, []Line{{Text: "é", Continuation: true}}, buffer.Write([]byte("\xa9\n")))
+}
+
+func TestLineBufferFlush(t *testing.T) {
+	buffer := NewLineBuffer(Source{})
+	assert.Empty(t, buffer.Flush())
+	buffer.Write([]byte("one\ncaf\xc3"))
+	assert.Equal(t, []Line{{Text: "caf\xc3", Partial: true}}, buffer.Flush())
+	assert.Empty(t, buffer.Flush())
+}
+
+func TestIncompleteRuneLength(t *testing.T) {
+	tests := []struct {
+		name string
+		data string
+		want int
+	}{
+		{name: "ascii", data: "abc", want: 0},
+		{name: "complete two bytes", data: "caf\xc3\xa9", want: 0},
+		{name: "missing one of two bytes", data: "caf\xc3", want: 1},
+		{name: "missing one of three bytes", data: "a\xe2\x82", want: 2},
+		{name: "missing three of four bytes", data: "a\xf0", want: 1},
+		{name: "complete four bytes", data: "a\xf0\x9f\x98\x80", want: 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, incompleteRuneLength([]byte(tt.data)))
+		})
+	}
+}
@@ -0,0 +1,219 @@
+package stream
+
+import (
+	"errors"
+	"io"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Identifies the origin of a log line.
+type Source struct {
+	ServerId  string
+	ProductId string
+	NodeId    string
+	LogName   string
+}
+
+type Line struct {
+	Source Source
+	// The line content, without its trailing new line.
+	Text string
+	// Set when the line is emitted without a trailing new line, either as it was flushed before its end was
+	// received, in which case the rest of it follows as a separate line, or as the log data does not end with one.
+	Partial bool
+	// Set on the rest of a line which was emitted before its end was received.
+	Continuation bool
+}
+
+// A Stage processes every line before it is written, it may modify the line or drop it by returning false.
+type Stage interface {
+	Process(line *Line) bool
+}
+
+// Implemented by the stages which can tell that all the following lines would be dropped, such as a time range
+// filter once the lines are past the end of the range.
+type Ender interface {
+	Ended() bool
+}
+
+// Implemented by the stages which hold lines back, such as a deduplicator holding back the repeats of a line. The
+// released lines are processed by the following stages, before the line which released them.
+type Releaser interface {
+	// Returns the lines released by the last processed line.
+	Released() []Line
+	// Returns all the lines still held back, once the stream is over.
+	Flush() []Line
+}
+
+// Implemented by the releasers which also release lines while no line is processed, such as a deduplicator releasing
+// the repeats of a burst of lines followed by silence.
+type StaleReleaser interface {
+	// Returns the lines which should no longer be held back, the lines idle for at least the timeout are released.
+	FlushStale(timeout time.Duration) []Line
+}
+
+// Returned by Pipeline.Write once a stage ended the stream, the reading of the stream can stop.
+var ErrEnded = errors.New("stream ended")
+
+// Adapts a function into a Stage.
+type StageFunc func(line *Line) bool
+
+func (f StageFunc) Process(line *Line) bool {
+	return f(line)
+}
+
+// Passes each line through the stages in order and writes the lines that were not dropped to the output.
+type Pipeline struct {
+	output io.Writer
+	stages []Stage
+}
+
+func NewPipeline(output io.Writer, stages ...Stage) *Pipeline {
+	return &Pipeline{
+		output: output,
+		stages: stages,
+	}
+}
+
+func (p *Pipeline) AddStage(stage Stage) {
+	p.stages = append(p.stages, stage)
+}
+
+// Writes the lines which are not dropped by the stages. ErrEnded is returned once a stage ended the stream, the
+// remaining lines are not written.
+func (p *Pipeline) Write(lines ...Line) error {
+	for _, line := range lines {
+		if err := p.write(0, line); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Writes the lines still held back by the stages, once the stream is over.
+func (p *Pipeline) Flush() error {
+	for i, stage := range p.stages {
+		releaser, ok := stage.(Releaser)
+		if !ok {
+			continue
+		}
+		for _, line := range releaser.Flush() {
+			if err := p.write(i+1, line); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// Writes the lines the stages no longer hold back while the stream is idle, see StaleReleaser.
+func (p *Pipeline) FlushStale(timeout time.Duration) error {
+	for i, stage := range p.stages {
+		releaser, ok := stage.(StaleReleaser)
+		if !ok {
+			continue
+		}
+		for _, line := range releaser.FlushStale(timeout) {
+			if err := p.write(i+1, line); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// Passes the line through the stages from the first given one, and writes it unless it is dropped.
+func (p *Pipeline) write(firstStage int, line Line) error {
+	for i := firstStage; i < len(p.stages); i++ {
+		kept := p.stages[i].Process(&line)
+		if releaser, ok := p.stages[i].(Releaser); ok {
+			for _, released := range releaser.Released() {
+				if err := p.write(i+1, released); err != nil {
+					return err
+				}
+			}
+		}
+		if !kept {
+			if p.ended() {
+				return ErrEnded
+			}
+			return nil
+		}
+	}
+	text := line.Text
+	if !line.Partial {
+		text += "\n"
+	}
+	_, err := io.WriteString(p.output, text)
+	return err
+}
+
+func (p *Pipeline) ended() bool {
+	for _, stage := range p.stages {
+		if ender, ok := stage.(Ender); ok && ender.Ended() {
+			return true
+		}
+	}
+	return false
+}
+
+// Prefixes every line with the selected parts of its source, to tell apart the lines of several streams.
+type Labeler struct {
+	Server bool
+	Node   bool
+	Log    bool
+}
+
+// Marks the labeled rest of a line which was written before its end was received.
+const continuationMarker = "... "
+
+// The lines of several streams share the output, so a labeled line written before its end was received is terminated,
+// rather than letting another stream write in the middle of it, and its rest is labeled as a continuation.
+func (l Labeler) Process(line *Line) bool {
+	label := l.Label(line.Source)
+	if label == "" {
+		return true
+	}
+	if line.Continuation {
+		label += continuationMarker
+	}
+	line.Text = label + line.Text
+	line.Partial = false
+	return true
+}
+
+// Returns the prefix of the lines of the source, such as "[node1 one.log] ", empty when no part is selected.
+func (l Labeler) Label(source Source) string {
+	var parts []string
+	if l.Server {
+		parts = append(parts, source.ServerId)
+	}
+	if l.Node {
+		parts = append(parts, source.NodeId)
+	}
+	if l.Log {
+		parts = append(parts, source.LogName)
+	}
+	if len(parts) == 0 {
+		return ""
+	}
+	return "[" + strings.Join(parts, " ") + "] "
+}
+
+// Serializes the writes of several streams sharing the same output, so that their lines are not interleaved.
+type SyncWriter struct {
+	lock   sync.Mutex
+	output io.Writer
+}
+
+func NewSyncWriter(output io.Writer) *SyncWriter {
+	return &SyncWriter{output: output}
+}
+
+func (w *SyncWriter) Write(p []byte) (int, error) {
+	w.lock.Lock()
+	defer w.lock.Unlock()
+	return w.output.Write(p)
+}
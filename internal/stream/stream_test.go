@@ -0,0 +1,154 @@
+package stream
+
+import (
+	"bytes"
+	"github.com/stretchr/testify/assert"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestPipelineWrite(t *testing.T) {
+	out := &bytes.Buffer{}
+	pipeline := NewPipeline(out)
+	pipeline.AddStage(StageFunc(func(line *Line) bool {
+		return !strings.Contains(line.Text, "DEBUG")
+	}))
+	pipeline.AddStage(StageFunc(func(line *Line) bool {
+		line.Text = strings.ToUpper(line.Text)
+		return true
+	}))
+
+	err := pipeline.Write(
+		Line{Text: "info one"},
+		Line{Text: "DEBUG two"},
+		Line{Text: "info thr", Partial: true},
+		Line{Text: "ee"},
+	)
+	assert.NoError(t, err)
+	assert.Equal(t, "INFO ONE\nINFO THREE\n", out.String())
+}
+
+// Drops the lines from the stop line on, and ends the stream.
+type stopStage struct {
+	stop    string
+	stopped bool
+}
+
+func (s *stopStage) Process(line *Line) bool {
+	s.stopped = s.stopped || line.Text == s.stop
+	return !s.stopped
+}
+
+func (s *stopStage) Ended() bool {
+	return s.stopped
+}
+
+func TestPipelineWrite_Ended(t *testing.T) {
+	out := &bytes.Buffer{}
+	pipeline := NewPipeline(out, &stopStage{stop: "stop"})
+	err := pipeline.Write(Line{Text: "one"}, Line{Text: "stop"}, Line{Text: "two"})
+	assert.Equal(t, ErrEnded, err)
+	assert.Equal(t, "one\n", out.String())
+}
+
+// Holds back the lines starting with a '+', and releases them once another line is processed.
+type holdStage struct {
+	held     []Line
+	released []Line
+}
+
+func (h *holdStage) Process(line *Line) bool {
+	if strings.HasPrefix(line.Text, "+") {
+		h.held = append(h.held, *line)
+		return false
+	}
+	h.released, h.held = h.held, nil
+	return true
+}
+
+func (h *holdStage) Released() []Line {
+	released := h.released
+	h.released = nil
+	return released
+}
+
+func (h *holdStage) Flush() []Line {
+	held := h.held
+	h.held = nil
+	return held
+}
+
+func (h *holdStage) FlushStale(time.Duration) []Line {
+	return h.Flush()
+}
+
+func TestPipelineWrite_Released(t *testing.T) {
+	out := &bytes.Buffer{}
+	upper := StageFunc(func(line *Line) bool {
+		line.Text = strings.ToUpper(line.Text)
+		return true
+	})
+	pipeline := NewPipeline(out, upper, &holdStage{}, StageFunc(func(line *Line) bool {
+		line.Text = "> " + line.Text
+		return true
+	}))
+	assert.NoError(t, pipeline.Write(Line{Text: "one"}, Line{Text: "+two"}, Line{Text: "+three"}, Line{Text: "four"}))
+	assert.Equal(t, "> ONE\n> +TWO\n> +THREE\n> FOUR\n", out.String())
+
+	out.Reset()
+	assert.NoError(t, pipeline.Write(Line{Text: "+five"}))
+	assert.Equal(t, "", out.String())
+	assert.NoError(t, pipeline.Flush())
+	assert.Equal(t, "> +FIVE\n", out.String())
+
+	out.Reset()
+	assert.NoError(t, pipeline.Write(Line{Text: "+six"}))
+	assert.NoError(t, pipeline.FlushStale(time.Second))
+	assert.Equal(t, "> +SIX\n", out.String())
+}
+
+func TestLabeler(t *testing.T) {
+	source := Source{ServerId: "local-rt", NodeId: "node1", LogName: "one.log"}
+	tests := []struct {
+		name    string
+		labeler Labeler
+		want    string
+	}{
+		{name: "no label", labeler: Labeler{}, want: "text"},
+		{name: "node", labeler: Labeler{Node: true}, want: "[node1] text"},
+		{name: "node and log", labeler: Labeler{Node: true, Log: true}, want: "[node1 one.log] text"},
+		{name: "all", labeler: Labeler{Server: true, Node: true, Log: true}, want: "[local-rt node1 one.log] text"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			line := Line{Source: source, Text: "text"}
+			assert.True(t, tt.labeler.Process(&line))
+			assert.Equal(t, tt.want, line.Text)
+		})
+	}
+}
+
+func TestLabeler_PartialLines(t *testing.T) {
+	out := &bytes.Buffer{}
+	output := NewSyncWriter(out)
+	labeler := Labeler{Node: true}
+	node1 := NewLineBuffer(Source{NodeId: "node1"})
+	node2 := NewLineBuffer(Source{NodeId: "node2"})
+	node1Pipeline := NewPipeline(output, labeler)
+	node2Pipeline := NewPipeline(output, labeler)
+
+	node1.Write([]byte("tw"))
+	assert.NoError(t, node1Pipeline.Write(node1.FlushStale(0)...))
+	assert.NoError(t, node2Pipeline.Write(node2.Write([]byte("one\n"))...))
+	assert.NoError(t, node1Pipeline.Write(node1.Write([]byte("o\nthree\n"))...))
+	assert.Equal(t, "[node1] tw\n[node2] one\n[node1] ... o\n[node1] three\n", out.String())
+
+	// Without a label, the rest of the line is written right after its start.
+	out.Reset()
+	node1.Write([]byte("fo"))
+	pipeline := NewPipeline(output, Labeler{})
+	assert.NoError(t, pipeline.Write(node1.FlushStale(0)...))
+	assert.NoError(t, pipeline.Write(node1.Write([]byte("ur\n"))...))
+	assert.Equal(t, "four\n", out.String())
+}
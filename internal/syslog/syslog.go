@@ -0,0 +1,482 @@
+// Package syslog forwards the log lines to a syslog server over UDP, TCP or TLS, as RFC 5424 messages carrying the
+// origin of each line in their structured data.
+package syslog
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"github.com/jfrog/live-logs/internal/format"
+	"github.com/jfrog/live-logs/internal/stream"
+	"io"
+	"io/ioutil"
+	"net"
+	"net/url"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	// The message is the whole line, with the informational severity.
+	RawMode = "raw"
+	// The severity, the time and the message are parsed from the service and request log lines, and their other
+	// fields are added to the structured data.
+	ParsedMode = "parsed"
+
+	DefaultFacility = "local0"
+
+	// JFrog has no enterprise number registered with IANA, the structured data IDs use the example enterprise
+	// number of RFC 5612.
+	enterpriseNumber = "32473"
+	originSdId       = "jfrog@" + enterpriseNumber
+	fieldsSdId       = "jfrogLog@" + enterpriseNumber
+
+	udpPort = "514"
+	tcpPort = "514"
+	tlsPort = "6514"
+)
+
+// The delays applied to the connection and the size of the queue, overridden by the tests.
+var (
+	dialTimeout  = 10 * time.Second
+	writeTimeout = 5 * time.Second
+	// The minimum delay between two connection attempts, after the server could not be reached.
+	reconnectDelay = 5 * time.Second
+	// The maximum time to send the queued messages once the forwarder is closed.
+	closeTimeout = 5 * time.Second
+	// The number of messages waiting to be sent, the following lines are dropped while the queue is full.
+	queueSize = 1024
+)
+
+var facilities = map[string]int{
+	"kern": 0, "user": 1, "mail": 2, "daemon": 3, "auth": 4, "syslog": 5, "lpr": 6, "news": 7,
+	"uucp": 8, "cron": 9, "authpriv": 10, "ftp": 11, "ntp": 12, "security": 13, "console": 14, "solaris-cron": 15,
+	"local0": 16, "local1": 17, "local2": 18, "local3": 19, "local4": 20, "local5": 21, "local6": 22, "local7": 23,
+}
+
+// The syslog severities.
+const (
+	severityCritical      = 2
+	severityError         = 3
+	severityWarning       = 4
+	severityInformational = 6
+	severityDebug         = 7
+)
+
+// The syslog severities of the log levels, see format.Level.
+var levelSeverities = map[string]int{
+	format.TraceLevel: severityDebug,
+	format.DebugLevel: severityDebug,
+	format.InfoLevel:  severityInformational,
+	format.WarnLevel:  severityWarning,
+	format.ErrorLevel: severityError,
+	format.FatalLevel: severityCritical,
+}
+
+// The destination and the shape of the forwarded messages.
+type Config struct {
+	// The address of the server, such as udp://siem:514, tcp://siem:514 or tls://siem:6514.
+	Url string
+	// One of raw or parsed.
+	Mode string
+	// The APP-NAME of the messages, "jfrog-" followed by the product id when empty.
+	AppName string
+	// The facility name, such as user or local0.
+	Facility string
+	// The PEM file of the certificate authorities trusted over TLS, the system ones are trusted when empty.
+	CaFile string
+}
+
+// Returns the names of the forwarding modes.
+func Modes() []string {
+	return []string{RawMode, ParsedMode}
+}
+
+// A pipeline stage sending each line to a syslog server, the lines are passed on unchanged.
+// The messages are queued and sent in the background, so a slow or hung syslog server does not hold up the log
+// output. A line which cannot be sent, or which does not fit in the queue, is dropped, and the server is reconnected
+// with the following lines.
+type Forwarder struct {
+	parsed   bool
+	appName  string
+	facility int
+	// Whether each message is prefixed with its length, as the stream transports require.
+	octetCounting bool
+	dial          func() (net.Conn, error)
+	notices       io.Writer
+	now           func() time.Time
+
+	lock sync.Mutex
+	// The time of the last timestamp of each source, in parsed mode.
+	lastTimes map[stream.Source]time.Time
+	queue     chan []byte
+	closed    bool
+	// The number of lines dropped since the queue is full.
+	overflowed int
+	// Closed once the queued messages were sent.
+	done chan struct{}
+
+	// The sending state, owned by the goroutine sending the queued messages.
+	lastDial time.Time
+	failing  bool
+	dropped  int
+
+	// Guards the connection, which is closed by Close when the queued messages cannot be sent in time.
+	connLock sync.Mutex
+	conn     net.Conn
+	aborted  bool
+
+	noticesLock sync.Mutex
+}
+
+// Creates a forwarder connected to the syslog server of the config.
+func NewForwarder(config Config) (*Forwarder, error) {
+	if config.Mode == "" {
+		config.Mode = RawMode
+	}
+	if config.Mode != RawMode && config.Mode != ParsedMode {
+		return nil, fmt.Errorf("invalid syslog mode [%s], expected one of %s", config.Mode, strings.Join(Modes(), ", "))
+	}
+	if config.Facility == "" {
+		config.Facility = DefaultFacility
+	}
+	facility, ok := facilities[config.Facility]
+	if !ok {
+		return nil, fmt.Errorf("invalid syslog facility [%s], expected one of %s", config.Facility, strings.Join(facilityNames(), ", "))
+	}
+	dial, octetCounting, err := newDialer(config)
+	if err != nil {
+		return nil, err
+	}
+	forwarder := &Forwarder{
+		parsed:        config.Mode == ParsedMode,
+		appName:       config.AppName,
+		facility:      facility,
+		octetCounting: octetCounting,
+		dial:          dial,
+		notices:       os.Stderr,
+		now:           time.Now,
+		lastTimes:     map[stream.Source]time.Time{},
+	}
+	forwarder.conn, err = dial()
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to the syslog server %s: %w", config.Url, err)
+	}
+	forwarder.lastDial = forwarder.now()
+	forwarder.start()
+	return forwarder, nil
+}
+
+// Starts sending the queued messages.
+func (f *Forwarder) start() {
+	f.queue = make(chan []byte, queueSize)
+	f.done = make(chan struct{})
+	go func() {
+		defer close(f.done)
+		for message := range f.queue {
+			f.send(message)
+		}
+	}()
+}
+
+func facilityNames() []string {
+	var names []string
+	for name := range facilities {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Returns the function connecting to the server, and whether the transport is a stream.
+func newDialer(config Config) (func() (net.Conn, error), bool, error) {
+	serverUrl, err := url.Parse(config.Url)
+	if err != nil || serverUrl.Host == "" {
+		return nil, false, fmt.Errorf("invalid syslog url [%s], expected such as udp://siem:514, tcp://siem:514 or tls://siem:6514", config.Url)
+	}
+	address := serverUrl.Host
+	withPort := func(port string) string {
+		if serverUrl.Port() == "" {
+			return net.JoinHostPort(serverUrl.Hostname(), port)
+		}
+		return address
+	}
+	switch serverUrl.Scheme {
+	case "udp":
+		address = withPort(udpPort)
+		return func() (net.Conn, error) { return net.DialTimeout("udp", address, dialTimeout) }, false, nil
+	case "tcp":
+		address = withPort(tcpPort)
+		return func() (net.Conn, error) { return net.DialTimeout("tcp", address, dialTimeout) }, true, nil
+	case "tls":
+		address = withPort(tlsPort)
+		tlsConfig := &tls.Config{ServerName: serverUrl.Hostname(), MinVersion: tls.VersionTLS12}
+		if config.CaFile != "" {
+			pem, err := ioutil.ReadFile(config.CaFile)
+			if err != nil {
+				return nil, false, err
+			}
+			tlsConfig.RootCAs = x509.NewCertPool()
+			if !tlsConfig.RootCAs.AppendCertsFromPEM(pem) {
+				return nil, false, fmt.Errorf("no certificate was found in %s", config.CaFile)
+			}
+		}
+		return func() (net.Conn, error) {
+			return tls.DialWithDialer(&net.Dialer{Timeout: dialTimeout}, "tcp", address, tlsConfig)
+		}, true, nil
+	default:
+		return nil, false, fmt.Errorf("invalid syslog url [%s], the scheme must be udp, tcp or tls", config.Url)
+	}
+}
+
+func (f *Forwarder) Process(line *stream.Line) bool {
+	if line.Text == "" {
+		return true
+	}
+	f.lock.Lock()
+	defer f.lock.Unlock()
+	if f.closed {
+		return true
+	}
+	select {
+	case f.queue <- f.message(*line):
+		if f.overflowed > 0 {
+			f.notice("Forwarding to the syslog server caught up, %d lines were dropped\n", f.overflowed)
+			f.overflowed = 0
+		}
+	default:
+		if f.overflowed == 0 {
+			f.notice("The syslog server does not keep up, the log lines are dropped until it catches up\n")
+		}
+		f.overflowed++
+	}
+	return true
+}
+
+// Sends the queued messages, for the close timeout at most, and closes the connection to the server.
+func (f *Forwarder) Close() error {
+	f.lock.Lock()
+	if !f.closed {
+		f.closed = true
+		close(f.queue)
+	}
+	f.lock.Unlock()
+
+	select {
+	case <-f.done:
+	case <-time.After(closeTimeout):
+		// The server does not keep up, the remaining messages are dropped.
+	}
+	f.connLock.Lock()
+	defer f.connLock.Unlock()
+	f.aborted = true
+	if f.conn == nil {
+		return nil
+	}
+	err := f.conn.Close()
+	f.conn = nil
+	return err
+}
+
+func (f *Forwarder) notice(format string, args ...interface{}) {
+	f.noticesLock.Lock()
+	defer f.noticesLock.Unlock()
+	fmt.Fprintf(f.notices, format, args...)
+}
+
+// Writes the message, reconnecting once when the connection was closed by the server. The failures are reported
+// once, along with the number of dropped lines when the forwarding recovers.
+func (f *Forwarder) send(message []byte) {
+	if f.octetCounting {
+		message = append([]byte(strconv.Itoa(len(message))+" "), message...)
+	}
+	connected, err := f.write(message)
+	if err != nil && connected {
+		// The server may have closed an idle connection, which only shows on the next write.
+		f.closeConn()
+		_, err = f.write(message)
+	}
+	if err != nil {
+		f.closeConn()
+		f.dropped++
+		if !f.failing {
+			f.failing = true
+			f.notice("Failed to forward the log lines to the syslog server, they are dropped until it is reachable: %s\n", err)
+		}
+		return
+	}
+	if f.failing {
+		f.failing = false
+		f.notice("Forwarding to the syslog server resumed, %d lines were dropped\n", f.dropped)
+		f.dropped = 0
+	}
+}
+
+// Writes the message, connecting first when there is no connection. Returns whether the message was written to an
+// existing connection.
+func (f *Forwarder) write(message []byte) (bool, error) {
+	conn, connected, err := f.connection()
+	if err != nil {
+		return false, err
+	}
+	err = conn.SetWriteDeadline(f.now().Add(writeTimeout))
+	if err == nil {
+		_, err = conn.Write(message)
+	}
+	return connected, err
+}
+
+// Returns the connection to the server, and whether it was already connected.
+func (f *Forwarder) connection() (net.Conn, bool, error) {
+	f.connLock.Lock()
+	defer f.connLock.Unlock()
+	if f.aborted {
+		return nil, false, fmt.Errorf("the forwarder is closed")
+	}
+	if f.conn != nil {
+		return f.conn, true, nil
+	}
+	if f.now().Sub(f.lastDial) < reconnectDelay {
+		return nil, false, fmt.Errorf("waiting to reconnect")
+	}
+	f.lastDial = f.now()
+	conn, err := f.dial()
+	if err != nil {
+		return nil, false, err
+	}
+	f.conn = conn
+	return conn, false, nil
+}
+
+func (f *Forwarder) closeConn() {
+	f.connLock.Lock()
+	defer f.connLock.Unlock()
+	if f.conn != nil {
+		_ = f.conn.Close()
+		f.conn = nil
+	}
+}
+
+// Formats the line as an RFC 5424 message,
+// <PRI>1 TIMESTAMP HOSTNAME APP-NAME PROCID MSGID [STRUCTURED-DATA] MSG
+// with the node id as hostname, and the origin of the line in the structured data.
+func (f *Forwarder) message(line stream.Line) []byte {
+	severity := severityInformational
+	timestamp := f.now()
+	msgId := "-"
+	text := line.Text
+	var fields []format.Field
+	if f.parsed {
+		record := format.Parse(line)
+		if lineTime, err := time.Parse(time.RFC3339Nano, record.Time); err == nil {
+			f.lastTimes[line.Source] = lineTime
+		}
+		if lineTime, found := f.lastTimes[line.Source]; found {
+			// A line without a timestamp, such as a stack trace line, belongs to the line before it.
+			timestamp = lineTime
+		}
+		switch {
+		case record.IsService:
+			msgId = "service"
+			if levelSeverity, found := levelSeverities[format.Level(record.Level)]; found {
+				severity = levelSeverity
+			}
+			text = record.Message
+		case record.IsRequest:
+			msgId = "request"
+			severity = requestSeverity(record.Status)
+		}
+		for _, field := range record.Fields() {
+			switch field.Key {
+			case "time", "server", "product", "node", "log", "msg":
+			default:
+				fields = append(fields, field)
+			}
+		}
+	}
+
+	appName := f.appName
+	if appName == "" {
+		appName = "jfrog-" + line.Source.ProductId
+	}
+	message := []byte{'<'}
+	message = strconv.AppendInt(message, int64(f.facility*8+severity), 10)
+	message = append(message, ">1 "...)
+	message = append(message, timestamp.UTC().Format("2006-01-02T15:04:05.000000Z")...)
+	message = append(message, ' ')
+	message = append(message, headerField(line.Source.NodeId, 255)...)
+	message = append(message, ' ')
+	message = append(message, headerField(appName, 48)...)
+	message = append(message, " - "...)
+	message = append(message, msgId...)
+	message = append(message, ' ')
+	message = appendElement(message, originSdId, []format.Field{
+		{Key: "server", Value: line.Source.ServerId},
+		{Key: "product", Value: line.Source.ProductId},
+		{Key: "node", Value: line.Source.NodeId},
+		{Key: "log", Value: line.Source.LogName},
+	})
+	if len(fields) > 0 {
+		message = appendElement(message, fieldsSdId, fields)
+	}
+	message = append(message, ' ')
+	message = append(message, text...)
+	return message
+}
+
+// The request logs have no level, the server errors are errors and the client errors are warnings.
+func requestSeverity(status string) int {
+	code, err := strconv.Atoi(status)
+	switch {
+	case err == nil && code >= 500:
+		return severityError
+	case err == nil && code >= 400:
+		return severityWarning
+	default:
+		return severityInformational
+	}
+}
+
+// Returns a header field, made of printable US-ASCII characters only, or the nil value "-" when empty.
+func headerField(value string, maxLength int) string {
+	if value == "" {
+		return "-"
+	}
+	field := []byte(value)
+	for i, c := range field {
+		if c < 33 || c > 126 {
+			field[i] = '_'
+		}
+	}
+	if len(field) > maxLength {
+		field = field[:maxLength]
+	}
+	return string(field)
+}
+
+// Appends a structured data element, the empty parameters are left out. The '"', '\' and ']' characters of the
+// values are escaped.
+func appendElement(message []byte, sdId string, params []format.Field) []byte {
+	message = append(message, '[')
+	message = append(message, sdId...)
+	for _, param := range params {
+		if param.Value == "" {
+			continue
+		}
+		message = append(message, ' ')
+		message = append(message, param.Key...)
+		message = append(message, `="`...)
+		for _, c := range []byte(param.Value) {
+			if c == '"' || c == '\\' || c == ']' {
+				message = append(message, '\\')
+			}
+			message = append(message, c)
+		}
+		message = append(message, '"')
+	}
+	return append(message, ']')
+}
@@ -0,0 +1,341 @@
+package syslog
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"github.com/jfrog/live-logs/internal/stream"
+	"github.com/stretchr/testify/require"
+	"io"
+	"io/ioutil"
+	"math/big"
+	"net"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+var (
+	testNow       = time.Date(2021, 3, 25, 5, 0, 0, 0, time.UTC)
+	serviceSource = stream.Source{ServerId: "local-rt", ProductId: "rt", NodeId: "node1", LogName: "artifactory-service.log"}
+	requestSource = stream.Source{ServerId: "local-rt", ProductId: "rt", NodeId: "node1", LogName: "artifactory-request.log"}
+)
+
+const (
+	serviceLine = "2021-03-25T04:00:00.006Z [jfrt ] [WARN ] [d76675e362ffbd6a] [.s.d.b.s.g.GarbageCollector:66] [art-exec-11 ] - Starting GC"
+	requestLine = "2021-03-25T04:30:34.199Z|94109ae150da76e|127.0.0.1|admin|GET|/api/release/bundles|503|-1|0|3"
+	// A request line of the recent versions, ending with the user agent.
+	userAgentRequestLine = "2021-03-25T04:30:34.199Z|94109ae150da76e|127.0.0.1|admin|GET|/api/system/ping|404|-1|0|3|JFrog-Router/7.17.0"
+	severeLine           = "2021-03-25T04:00:00.006Z [jfmd ] [SEVERE] [d76675e362ffbd6a] [MetadataServer:42] [main] - Failed to start"
+)
+
+func TestForwarder_Message(t *testing.T) {
+	tests := []struct {
+		name     string
+		parsed   bool
+		appName  string
+		lines    []stream.Line
+		wantLast string
+	}{
+		{
+			name:  "raw",
+			lines: []stream.Line{{Source: serviceSource, Text: serviceLine}},
+			wantLast: `<134>1 2021-03-25T05:00:00.000000Z node1 jfrog-rt - - ` +
+				`[jfrog@32473 server="local-rt" product="rt" node="node1" log="artifactory-service.log"] ` + serviceLine,
+		},
+		{
+			name:    "raw with app name",
+			appName: "artifactory",
+			lines:   []stream.Line{{Source: stream.Source{ProductId: "rt", NodeId: "node 1"}, Text: "first line"}},
+			wantLast: `<134>1 2021-03-25T05:00:00.000000Z node_1 artifactory - - ` +
+				`[jfrog@32473 product="rt" node="node 1"] first line`,
+		},
+		{
+			name:   "parsed service line",
+			parsed: true,
+			lines:  []stream.Line{{Source: serviceSource, Text: serviceLine}},
+			wantLast: `<132>1 2021-03-25T04:00:00.006000Z node1 jfrog-rt - service ` +
+				`[jfrog@32473 server="local-rt" product="rt" node="node1" log="artifactory-service.log"]` +
+				`[jfrogLog@32473 level="WARN" service="jfrt" trace_id="d76675e362ffbd6a" class=".s.d.b.s.g.GarbageCollector:66" thread="art-exec-11"] ` +
+				`Starting GC`,
+		},
+		{
+			name:   "parsed stack trace line",
+			parsed: true,
+			lines:  []stream.Line{{Source: serviceSource, Text: serviceLine}, {Source: serviceSource, Text: "\tat some.Class.method(Class.java:42)"}},
+			wantLast: `<134>1 2021-03-25T04:00:00.006000Z node1 jfrog-rt - - ` +
+				`[jfrog@32473 server="local-rt" product="rt" node="node1" log="artifactory-service.log"] ` +
+				"\tat some.Class.method(Class.java:42)",
+		},
+		{
+			name:   "parsed request line",
+			parsed: true,
+			lines:  []stream.Line{{Source: requestSource, Text: requestLine}},
+			wantLast: `<131>1 2021-03-25T04:30:34.199000Z node1 jfrog-rt - request ` +
+				`[jfrog@32473 server="local-rt" product="rt" node="node1" log="artifactory-request.log"]` +
+				`[jfrogLog@32473 trace_id="94109ae150da76e" remote_address="127.0.0.1" username="admin" method="GET" ` +
+				`url="/api/release/bundles" status="503" request_size="-1" response_size="0" duration_ms="3"] ` + requestLine,
+		},
+		{
+			name:   "parsed request line with user agent",
+			parsed: true,
+			lines:  []stream.Line{{Source: requestSource, Text: userAgentRequestLine}},
+			wantLast: `<132>1 2021-03-25T04:30:34.199000Z node1 jfrog-rt - request ` +
+				`[jfrog@32473 server="local-rt" product="rt" node="node1" log="artifactory-request.log"]` +
+				`[jfrogLog@32473 trace_id="94109ae150da76e" remote_address="127.0.0.1" username="admin" method="GET" ` +
+				`url="/api/system/ping" status="404" request_size="-1" response_size="0" duration_ms="3" user_agent="JFrog-Router/7.17.0"] ` +
+				userAgentRequestLine,
+		},
+		{
+			name:   "parsed severe line",
+			parsed: true,
+			lines:  []stream.Line{{Source: serviceSource, Text: severeLine}},
+			wantLast: `<131>1 2021-03-25T04:00:00.006000Z node1 jfrog-rt - service ` +
+				`[jfrog@32473 server="local-rt" product="rt" node="node1" log="artifactory-service.log"]` +
+				`[jfrogLog@32473 level="SEVERE" service="jfmd" trace_id="d76675e362ffbd6a" class="MetadataServer:42" thread="main"] ` +
+				`Failed to start`,
+		},
+		{
+			name:   "escaped structured data",
+			parsed: true,
+			lines:  []stream.Line{{Source: stream.Source{ProductId: "rt", LogName: `a"b\c]d`}, Text: "line"}},
+			wantLast: `<134>1 2021-03-25T05:00:00.000000Z - jfrog-rt - - ` +
+				`[jfrog@32473 product="rt" log="a\"b\\c\]d"] line`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			forwarder := &Forwarder{parsed: tt.parsed, appName: tt.appName, facility: facilities["local0"],
+				now: func() time.Time { return testNow }, lastTimes: map[stream.Source]time.Time{}}
+			var message []byte
+			for _, line := range tt.lines {
+				message = forwarder.message(line)
+			}
+			require.Equal(t, tt.wantLast, string(message))
+		})
+	}
+}
+
+func TestForwarder_Udp(t *testing.T) {
+	listener, err := net.ListenPacket("udp", "127.0.0.1:0")
+	require.NoError(t, err)
+	defer listener.Close()
+
+	forwarder, err := NewForwarder(Config{Url: "udp://" + listener.LocalAddr().String(), Facility: "user"})
+	require.NoError(t, err)
+	defer forwarder.Close()
+	line := stream.Line{Source: serviceSource, Text: serviceLine}
+	require.True(t, forwarder.Process(&line))
+	require.Equal(t, serviceLine, line.Text)
+
+	buffer := make([]byte, 4096)
+	require.NoError(t, listener.SetReadDeadline(time.Now().Add(10*time.Second)))
+	n, _, err := listener.ReadFrom(buffer)
+	require.NoError(t, err)
+	require.True(t, strings.HasPrefix(string(buffer[:n]), "<14>1 "), string(buffer[:n]))
+	require.True(t, strings.HasSuffix(string(buffer[:n]), "] "+serviceLine), string(buffer[:n]))
+}
+
+// A syslog server receiving the octet counted messages of its connections.
+type streamServer struct {
+	listener net.Listener
+	messages chan string
+	conns    chan net.Conn
+}
+
+func newStreamServer(t *testing.T, listener net.Listener) *streamServer {
+	server := &streamServer{listener: listener, messages: make(chan string, 10), conns: make(chan net.Conn, 10)}
+	t.Cleanup(func() { _ = listener.Close() })
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			server.conns <- conn
+			go server.read(conn)
+		}
+	}()
+	return server
+}
+
+func (s *streamServer) read(conn net.Conn) {
+	reader := bufio.NewReader(conn)
+	for {
+		length, err := reader.ReadString(' ')
+		if err != nil {
+			return
+		}
+		size, err := strconv.Atoi(strings.TrimSpace(length))
+		if err != nil {
+			return
+		}
+		message := make([]byte, size)
+		if _, err = io.ReadFull(reader, message); err != nil {
+			return
+		}
+		s.messages <- string(message)
+	}
+}
+
+func (s *streamServer) next(t *testing.T) string {
+	select {
+	case message := <-s.messages:
+		return message
+	case <-time.After(10 * time.Second):
+		t.Fatal("no message was received")
+		return ""
+	}
+}
+
+func TestForwarder_Tcp(t *testing.T) {
+	realReconnectDelay := reconnectDelay
+	defer func() { reconnectDelay = realReconnectDelay }()
+	reconnectDelay = 0
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	server := newStreamServer(t, listener)
+	forwarder, err := NewForwarder(Config{Url: "tcp://" + listener.Addr().String(), Mode: ParsedMode})
+	require.NoError(t, err)
+	defer forwarder.Close()
+	notices := &lockedBuffer{}
+	forwarder.notices = notices
+
+	line := stream.Line{Source: requestSource, Text: requestLine}
+	forwarder.Process(&line)
+	require.True(t, strings.HasPrefix(server.next(t), "<131>1 2021-03-25T04:30:34.199000Z node1 jfrog-rt - request "))
+
+	// The server is gone, the lines are dropped, and forwarded again once it is back.
+	address := listener.Addr().String()
+	require.NoError(t, listener.Close())
+	(<-server.conns).Close()
+	for i := 0; i < 100 && notices.String() == ""; i++ {
+		forwarder.Process(&line)
+		time.Sleep(10 * time.Millisecond)
+	}
+	require.Contains(t, notices.String(), "Failed to forward the log lines to the syslog server")
+
+	listener, err = net.Listen("tcp", address)
+	require.NoError(t, err)
+	server = newStreamServer(t, listener)
+	line = stream.Line{Source: serviceSource, Text: serviceLine}
+	forwarder.Process(&line)
+	require.True(t, strings.HasSuffix(server.next(t), "] Starting GC"))
+	require.Eventually(t, func() bool {
+		return strings.Contains(notices.String(), "Forwarding to the syslog server resumed")
+	}, 5*time.Second, 10*time.Millisecond)
+}
+
+func TestForwarder_HungServer(t *testing.T) {
+	realQueueSize, realCloseTimeout := queueSize, closeTimeout
+	defer func() { queueSize, closeTimeout = realQueueSize, realCloseTimeout }()
+	queueSize, closeTimeout = 4, 100*time.Millisecond
+
+	// The server never reads, the writes block until the forwarder is closed.
+	client, server := net.Pipe()
+	defer server.Close()
+	notices := &lockedBuffer{}
+	forwarder := &Forwarder{facility: facilities["local0"], dial: func() (net.Conn, error) { return client, nil },
+		notices: notices, now: time.Now, lastTimes: map[stream.Source]time.Time{}}
+	forwarder.start()
+
+	line := stream.Line{Source: serviceSource, Text: serviceLine}
+	start := time.Now()
+	for i := 0; i < 100; i++ {
+		require.True(t, forwarder.Process(&line))
+	}
+	require.Less(t, int64(time.Since(start)), int64(time.Second), "the lines were held up by the server")
+	require.Contains(t, notices.String(), "The syslog server does not keep up")
+
+	require.NoError(t, forwarder.Close())
+	// The lines processed once closed are passed on.
+	require.True(t, forwarder.Process(&line))
+}
+
+// A buffer written by the forwarder while the test reads it.
+type lockedBuffer struct {
+	lock   sync.Mutex
+	buffer bytes.Buffer
+}
+
+func (b *lockedBuffer) Write(p []byte) (int, error) {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+	return b.buffer.Write(p)
+}
+
+func (b *lockedBuffer) String() string {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+	return b.buffer.String()
+}
+
+func TestForwarder_Tls(t *testing.T) {
+	certificate, caFile := newCertificate(t)
+	listener, err := tls.Listen("tcp", "127.0.0.1:0", &tls.Config{Certificates: []tls.Certificate{certificate}})
+	require.NoError(t, err)
+	server := newStreamServer(t, listener)
+
+	_, err = NewForwarder(Config{Url: "tls://" + listener.Addr().String()})
+	require.Error(t, err)
+
+	forwarder, err := NewForwarder(Config{Url: "tls://" + listener.Addr().String(), CaFile: caFile})
+	require.NoError(t, err)
+	defer forwarder.Close()
+	line := stream.Line{Source: serviceSource, Text: "first line"}
+	forwarder.Process(&line)
+	require.True(t, strings.HasSuffix(server.next(t), "] first line"))
+}
+
+// Creates a self signed certificate for 127.0.0.1, and returns it along with the path of its PEM file.
+func newCertificate(t *testing.T) (tls.Certificate, string) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "syslog"},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		IPAddresses:           []net.IP{net.ParseIP("127.0.0.1")},
+		KeyUsage:              x509.KeyUsageDigitalSignature | x509.KeyUsageCertSign,
+		ExtKeyUsage:           []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+		BasicConstraintsValid: true,
+		IsCA:                  true,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	require.NoError(t, err)
+	caFile := filepath.Join(t.TempDir(), "ca.pem")
+	require.NoError(t, ioutil.WriteFile(caFile, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}), 0600))
+	return tls.Certificate{Certificate: [][]byte{der}, PrivateKey: key}, caFile
+}
+
+func TestNewForwarder_Invalid(t *testing.T) {
+	tests := []struct {
+		name    string
+		config  Config
+		wantErr string
+	}{
+		{"mode", Config{Url: "udp://localhost:514", Mode: "json"}, "invalid syslog mode [json], expected one of raw, parsed"},
+		{"facility", Config{Url: "udp://localhost:514", Facility: "local9"}, "invalid syslog facility [local9]"},
+		{"scheme", Config{Url: "http://localhost:514"}, "invalid syslog url [http://localhost:514], the scheme must be udp, tcp or tls"},
+		{"url", Config{Url: "localhost"}, "invalid syslog url [localhost]"},
+		{"ca file", Config{Url: "tls://localhost", CaFile: filepath.Join(t.TempDir(), "missing.pem")}, "open "},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := NewForwarder(tt.config)
+			require.Error(t, err)
+			require.True(t, strings.HasPrefix(err.Error(), tt.wantErr), err.Error())
+		})
+	}
+}
@@ -0,0 +1,189 @@
+package testserver
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+)
+
+const otlpLogsPath = "/v1/logs"
+
+// A log record received by the OTLP receiver, flattened with the attributes of its resource.
+type OtlpRecord struct {
+	ResourceAttributes map[string]string
+	ScopeName          string
+	TimeUnixNano       string
+	SeverityNumber     int
+	SeverityText       string
+	Body               string
+	TraceId            string
+	Attributes         map[string]string
+	// The integer attributes, nil when there is none.
+	IntAttributes map[string]int64
+}
+
+// A stand-in for an OpenTelemetry collector, receiving the logs on the OTLP/HTTP JSON endpoint /v1/logs.
+// Failures are scripted with FailRequests, and the records of a request are rejected with RejectRecords.
+type OtlpReceiver struct {
+	*httptest.Server
+
+	lock     sync.Mutex
+	records  []OtlpRecord
+	headers  []http.Header
+	failure  *failure
+	rejected int
+}
+
+// The OTLP/HTTP JSON encoding of the logs export request, limited to the string and integer values.
+type otlpRequest struct {
+	ResourceLogs []struct {
+		Resource struct {
+			Attributes []otlpKeyValue `json:"attributes"`
+		} `json:"resource"`
+		ScopeLogs []struct {
+			Scope struct {
+				Name string `json:"name"`
+			} `json:"scope"`
+			LogRecords []struct {
+				TimeUnixNano   string `json:"timeUnixNano"`
+				SeverityNumber int    `json:"severityNumber"`
+				SeverityText   string `json:"severityText"`
+				Body           struct {
+					StringValue string `json:"stringValue"`
+				} `json:"body"`
+				Attributes []otlpKeyValue `json:"attributes"`
+				TraceId    string         `json:"traceId"`
+			} `json:"logRecords"`
+		} `json:"scopeLogs"`
+	} `json:"resourceLogs"`
+}
+
+type otlpKeyValue struct {
+	Key   string `json:"key"`
+	Value struct {
+		StringValue string      `json:"stringValue"`
+		IntValue    json.Number `json:"intValue"`
+	} `json:"value"`
+}
+
+// Starts an OTLP receiver, it is closed when the test ends.
+func NewOtlpReceiver(t testing.TB) *OtlpReceiver {
+	r := &OtlpReceiver{}
+	r.Server = httptest.NewServer(http.HandlerFunc(r.handle))
+	t.Cleanup(r.Close)
+	return r
+}
+
+// Answers the following requests with the status code, such as 429 or 503.
+func (r *OtlpReceiver) FailRequests(statusCode, count int) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	r.failure = &failure{statusCode: statusCode, remaining: count}
+}
+
+// Rejects the given number of records of the next request with a partial success, the other records are kept.
+func (r *OtlpReceiver) RejectRecords(count int) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	r.rejected = count
+}
+
+// Returns the records received so far.
+func (r *OtlpReceiver) Records() []OtlpRecord {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	return append([]OtlpRecord(nil), r.records...)
+}
+
+// Returns the headers of the requests received so far, including the failed requests.
+func (r *OtlpReceiver) Headers() []http.Header {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	return append([]http.Header(nil), r.headers...)
+}
+
+func (r *OtlpReceiver) handle(w http.ResponseWriter, request *http.Request) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	if request.URL.Path != otlpLogsPath || request.Method != http.MethodPost {
+		http.NotFound(w, request)
+		return
+	}
+	r.headers = append(r.headers, request.Header.Clone())
+	if r.failure != nil && r.failure.remaining > 0 {
+		r.failure.remaining--
+		writeError(w, r.failure.statusCode, http.StatusText(r.failure.statusCode))
+		return
+	}
+	if request.Header.Get("Content-Type") != "application/json" {
+		writeError(w, http.StatusUnsupportedMediaType, "only the JSON encoding is supported")
+		return
+	}
+	var body otlpRequest
+	if err := json.NewDecoder(request.Body).Decode(&body); err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	received := 0
+	for _, resourceLogs := range body.ResourceLogs {
+		resourceAttributes := attributeMap(resourceLogs.Resource.Attributes)
+		for _, scopeLogs := range resourceLogs.ScopeLogs {
+			for _, logRecord := range scopeLogs.LogRecords {
+				received++
+				if received <= r.rejected {
+					continue
+				}
+				r.records = append(r.records, OtlpRecord{
+					ResourceAttributes: resourceAttributes,
+					ScopeName:          scopeLogs.Scope.Name,
+					TimeUnixNano:       logRecord.TimeUnixNano,
+					SeverityNumber:     logRecord.SeverityNumber,
+					SeverityText:       logRecord.SeverityText,
+					Body:               logRecord.Body.StringValue,
+					TraceId:            logRecord.TraceId,
+					Attributes:         attributeMap(logRecord.Attributes),
+					IntAttributes:      intAttributeMap(logRecord.Attributes),
+				})
+			}
+		}
+	}
+	if r.rejected > 0 {
+		rejected := r.rejected
+		if rejected > received {
+			rejected = received
+		}
+		r.rejected = 0
+		writeJson(w, map[string]interface{}{"partialSuccess": map[string]interface{}{
+			"rejectedLogRecords": rejected,
+			"errorMessage":       "rejected by the test",
+		}})
+		return
+	}
+	writeJson(w, map[string]interface{}{})
+}
+
+func attributeMap(attributes []otlpKeyValue) map[string]string {
+	values := map[string]string{}
+	for _, attribute := range attributes {
+		if attribute.Value.IntValue == "" {
+			values[attribute.Key] = attribute.Value.StringValue
+		}
+	}
+	return values
+}
+
+func intAttributeMap(attributes []otlpKeyValue) map[string]int64 {
+	var values map[string]int64
+	for _, attribute := range attributes {
+		if value, err := attribute.Value.IntValue.Int64(); err == nil {
+			if values == nil {
+				values = map[string]int64{}
+			}
+			values[attribute.Key] = value
+		}
+	}
+	return values
+}
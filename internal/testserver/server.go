@@ -0,0 +1,262 @@
+// Package testserver provides a fake JFrog platform, serving the system version and the logs endpoints of every
+// product, to test the plugin end to end through its HTTP client.
+package testserver
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/jfrog/live-logs/internal/constants"
+	"github.com/jfrog/live-logs/internal/model"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+const (
+	cliHomeDirEnv  = "JFROG_CLI_HOME_DIR"
+	logsConfigPath = "system/logs/config"
+	logsDataPath   = "system/logs/data"
+)
+
+// The url path of a product under the platform url, and the endpoint and json field of its version.
+type productRoute struct {
+	path         string
+	versionPath  string
+	versionField string
+}
+
+var productRoutes = map[string]productRoute{
+	constants.ArtifactoryId:  {path: "/artifactory/api/", versionPath: "system/version", versionField: "version"},
+	constants.XrayId:         {path: "/xray/api/v1/", versionPath: "system/version", versionField: "xray_version"},
+	constants.McId:           {path: "/mc/api/v1/"},
+	constants.PipelinesId:    {path: "/pipelines/api/v1/", versionPath: "system/info", versionField: "version"},
+	constants.DistributionId: {path: "/distribution/api/v1/", versionPath: "system/info", versionField: "version"},
+}
+
+// The state of a product served by the fake platform.
+type Product struct {
+	Version           string
+	Nodes             []string
+	LogFileNames      []string
+	RefreshRateMillis int64
+	// The maximum size of the log content returned by a single request, unlimited when zero.
+	PageSize int
+}
+
+// A scripted response, replacing the regular responses of a product.
+type failure struct {
+	statusCode int
+	remaining  int
+}
+
+// A fake JFrog platform. The products are configured with AddProduct, and the log files grow with AppendLog.
+// Failures, delays and authentication failures are scripted with FailRequests, SetDelay and SetAccessToken.
+type Server struct {
+	*httptest.Server
+
+	// Closed when the server is closing, to release the delayed responses.
+	closing chan struct{}
+
+	lock        sync.Mutex
+	accessToken string
+	delay       time.Duration
+	products    map[string]*Product
+	logs        map[string][]byte
+	failures    map[string]*failure
+	requests    map[string]int
+}
+
+// Starts a fake platform accepting the given access token, it is closed when the test ends.
+// The JFrog CLI home directory is moved to a temporary directory meanwhile, to leave the files the JFrog CLI client
+// creates out of the user home directory.
+func New(t testing.TB, accessToken string) *Server {
+	setCliHomeDir(t)
+	s := &Server{
+		accessToken: accessToken,
+		products:    map[string]*Product{},
+		logs:        map[string][]byte{},
+		failures:    map[string]*failure{},
+		requests:    map[string]int{},
+		closing:     make(chan struct{}),
+	}
+	s.Server = httptest.NewServer(http.HandlerFunc(s.handle))
+	t.Cleanup(func() {
+		close(s.closing)
+		s.Close()
+	})
+	return s
+}
+
+// Serves the logs endpoints of a product, its log files are initially empty.
+func (s *Server) AddProduct(productId string, product Product) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	s.products[productId] = &product
+}
+
+// Appends content to a log file, as the product would write it.
+func (s *Server) AppendLog(productId, nodeId, logName, content string) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	key := logKey(productId, nodeId, logName)
+	s.logs[key] = append(s.logs[key], content...)
+}
+
+// Replaces a log file with a new one holding the given content, as the log rotation does.
+func (s *Server) RotateLog(productId, nodeId, logName, content string) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	s.logs[logKey(productId, nodeId, logName)] = []byte(content)
+}
+
+// Answers the following requests of a product with the status code, such as 429 or 503.
+func (s *Server) FailRequests(productId string, statusCode, count int) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	s.failures[productId] = &failure{statusCode: statusCode, remaining: count}
+}
+
+// Delays every response, to emulate a slow server.
+func (s *Server) SetDelay(delay time.Duration) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	s.delay = delay
+}
+
+// Changes the accepted access token, the requests carrying another token are answered with 401.
+func (s *Server) SetAccessToken(accessToken string) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	s.accessToken = accessToken
+}
+
+// Returns the number of requests received by a product endpoint, such as "system/logs/data".
+func (s *Server) Requests(productId, endpoint string) int {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	return s.requests[productId+" "+endpoint]
+}
+
+func setCliHomeDir(t testing.TB) {
+	realCliHomeDir, isSet := os.LookupEnv(cliHomeDirEnv)
+	t.Cleanup(func() {
+		if isSet {
+			_ = os.Setenv(cliHomeDirEnv, realCliHomeDir)
+			return
+		}
+		_ = os.Unsetenv(cliHomeDirEnv)
+	})
+	_ = os.Setenv(cliHomeDirEnv, t.TempDir())
+}
+
+func logKey(productId, nodeId, logName string) string {
+	return productId + "/" + nodeId + "/" + logName
+}
+
+func (s *Server) handle(w http.ResponseWriter, r *http.Request) {
+	s.lock.Lock()
+	delay := s.delay
+	s.lock.Unlock()
+	if delay > 0 {
+		select {
+		case <-time.After(delay):
+		case <-r.Context().Done():
+			return
+		case <-s.closing:
+			return
+		}
+	}
+
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	productId, endpoint, ok := s.route(r.URL.Path)
+	if !ok {
+		http.NotFound(w, r)
+		return
+	}
+	s.requests[productId+" "+endpoint]++
+	if r.Header.Get("Authorization") != "Bearer "+s.accessToken {
+		writeError(w, http.StatusUnauthorized, "Bad credentials")
+		return
+	}
+	if productFailure := s.failures[productId]; productFailure != nil && productFailure.remaining > 0 {
+		productFailure.remaining--
+		writeError(w, productFailure.statusCode, http.StatusText(productFailure.statusCode))
+		return
+	}
+
+	product := s.products[productId]
+	switch endpoint {
+	case productRoutes[productId].versionPath:
+		writeJson(w, map[string]string{productRoutes[productId].versionField: product.Version})
+	case logsConfigPath:
+		writeJson(w, model.Config{Nodes: product.Nodes, LogFileNames: product.LogFileNames, RefreshRateMillis: product.RefreshRateMillis})
+	case logsDataPath:
+		s.handleLogData(w, r, productId, product)
+	default:
+		http.NotFound(w, r)
+	}
+}
+
+// Returns the product and its endpoint matching the url path, only the configured products are served.
+func (s *Server) route(urlPath string) (productId, endpoint string, ok bool) {
+	for id, route := range productRoutes {
+		if !strings.HasPrefix(urlPath, route.path) {
+			continue
+		}
+		if _, ok := s.products[id]; !ok {
+			return "", "", false
+		}
+		return id, strings.TrimPrefix(urlPath, route.path), true
+	}
+	return "", "", false
+}
+
+// Returns the log content following the file size of the request. A file size beyond the end of the log file, which
+// means the log file was rotated, restarts from the beginning of the new log file.
+func (s *Server) handleLogData(w http.ResponseWriter, r *http.Request, productId string, product *Product) {
+	nodeId := r.Header.Get(constants.NodeIdHeader)
+	logName := r.URL.Query().Get("id")
+	if !contains(product.Nodes, nodeId) || !contains(product.LogFileNames, logName) {
+		writeError(w, http.StatusNotFound, fmt.Sprintf("log file %s was not found on node %s", logName, nodeId))
+		return
+	}
+	fileSize, err := strconv.ParseInt(r.URL.Query().Get("file_size"), 10, 64)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "invalid file_size")
+		return
+	}
+	content := s.logs[logKey(productId, nodeId, logName)]
+	if fileSize > int64(len(content)) {
+		fileSize = 0
+	}
+	page := content[fileSize:]
+	if product.PageSize > 0 && len(page) > product.PageSize {
+		page = page[:product.PageSize]
+	}
+	writeJson(w, model.Data{Content: string(page), PageMarker: fileSize + int64(len(page))})
+}
+
+func writeJson(w http.ResponseWriter, body interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(body)
+}
+
+func writeError(w http.ResponseWriter, statusCode int, message string) {
+	w.WriteHeader(statusCode)
+	_, _ = w.Write([]byte(message))
+}
+
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,134 @@
+// Package timerange selects the log lines by the timestamp they start with.
+package timerange
+
+import (
+	"fmt"
+	"github.com/jfrog/live-logs/internal/stream"
+	"strings"
+	"sync"
+	"time"
+)
+
+// The layouts of the timestamps without a time zone, accepted both in the log lines and in the flags.
+var localLayouts = []string{
+	"2006-01-02T15:04:05.999999999",
+	"2006-01-02 15:04:05.999999999",
+	"2006-01-02T15:04",
+	"2006-01-02 15:04",
+	"2006-01-02",
+}
+
+// The layouts of the times of day accepted in the flags, referring to the current day.
+var timeOfDayLayouts = []string{"15:04:05", "15:04"}
+
+// Parses a bound of a time range. The value is either a duration before now such as 20m or 1h30m, an RFC3339
+// timestamp, or a date and time without a time zone, such as "2021-03-25 04:30" or "04:30" for today, in the
+// given location.
+func ParseTime(value string, now time.Time, location *time.Location) (time.Time, error) {
+	value = strings.TrimSpace(value)
+	if duration, err := time.ParseDuration(value); err == nil {
+		return now.Add(-duration), nil
+	}
+	if parsed, err := time.Parse(time.RFC3339Nano, value); err == nil {
+		return parsed, nil
+	}
+	for _, layout := range localLayouts {
+		if parsed, err := time.ParseInLocation(layout, value, location); err == nil {
+			return parsed, nil
+		}
+	}
+	localNow := now.In(location)
+	for _, layout := range timeOfDayLayouts {
+		if parsed, err := time.ParseInLocation(layout, value, location); err == nil {
+			return time.Date(localNow.Year(), localNow.Month(), localNow.Day(),
+				parsed.Hour(), parsed.Minute(), parsed.Second(), 0, location), nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("invalid time [%s], expected a duration such as 20m, or a timestamp such as 2021-03-25T04:30:00Z, \"2021-03-25 04:30\" or 04:30", value)
+}
+
+// Parses the timestamp a log line starts with, a timestamp without a time zone is in the given location.
+// The service logs start with a timestamp followed by a space, and the request logs with a timestamp followed by a '|'.
+func ParseLineTime(text string, location *time.Location) (time.Time, bool) {
+	end := strings.IndexAny(text, " |")
+	if end < 0 {
+		end = len(text)
+	}
+	if lineTime, err := time.Parse(time.RFC3339Nano, text[:end]); err == nil {
+		return lineTime, true
+	}
+	candidates := []string{text[:end]}
+	// A date followed by the time of day, such as "2021-03-25 04:30:34,199".
+	if end < len(text) && text[end] == ' ' {
+		if next := strings.IndexAny(text[end+1:], " |"); next >= 0 {
+			candidates = append(candidates, text[:end+1+next])
+		} else {
+			candidates = append(candidates, text)
+		}
+	}
+	for i := len(candidates) - 1; i >= 0; i-- {
+		// The comma as decimal separator is only parsed since Go 1.17.
+		candidate := strings.Replace(candidates[i], ",", ".", 1)
+		for _, layout := range localLayouts[:2] {
+			if lineTime, err := time.ParseInLocation(layout, candidate, location); err == nil {
+				return lineTime, true
+			}
+		}
+	}
+	return time.Time{}, false
+}
+
+// A time range, the zero Since or Until leaves the range open on that side.
+type Range struct {
+	Since time.Time
+	Until time.Time
+	// The location of the log timestamps without a time zone.
+	LogLocation *time.Location
+}
+
+// Creates a pipeline stage keeping the lines of the range.
+func (r Range) NewFilter() *Filter {
+	return &Filter{timeRange: r, lastTimes: map[stream.Source]time.Time{}}
+}
+
+// Keeps the lines within the time range. A line without a timestamp, such as a stack trace line, belongs to the
+// line before it. The filter ends the stream once a line of every source passed the end of the range.
+type Filter struct {
+	timeRange Range
+
+	lock      sync.Mutex
+	lastTimes map[stream.Source]time.Time
+	ended     map[stream.Source]bool
+}
+
+func (f *Filter) Process(line *stream.Line) bool {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+	location := f.timeRange.LogLocation
+	if location == nil {
+		location = time.UTC
+	}
+	if lineTime, found := ParseLineTime(line.Text, location); found {
+		f.lastTimes[line.Source] = lineTime
+	}
+	lineTime, found := f.lastTimes[line.Source]
+	if !found {
+		// The lines before the first timestamp belong to an unknown time.
+		return f.timeRange.Since.IsZero()
+	}
+	if !f.timeRange.Until.IsZero() && lineTime.After(f.timeRange.Until) {
+		if f.ended == nil {
+			f.ended = map[stream.Source]bool{}
+		}
+		f.ended[line.Source] = true
+		return false
+	}
+	return f.timeRange.Since.IsZero() || !lineTime.Before(f.timeRange.Since)
+}
+
+// Returns whether the following lines are all past the end of the range, as the logs are written in time order.
+func (f *Filter) Ended() bool {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+	return len(f.ended) > 0 && len(f.ended) == len(f.lastTimes)
+}
@@ -0,0 +1,106 @@
+package timerange
+
+import (
+	"github.com/jfrog/live-logs/internal/stream"
+	"github.com/stretchr/testify/require"
+	"testing"
+	"time"
+)
+
+func TestParseTime(t *testing.T) {
+	paris, err := time.LoadLocation("Europe/Paris")
+	require.NoError(t, err)
+	now := time.Date(2021, 3, 25, 12, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name    string
+		value   string
+		want    time.Time
+		wantErr bool
+	}{
+		{name: "duration", value: "20m", want: now.Add(-20 * time.Minute)},
+		{name: "rfc3339", value: "2021-03-25T04:30:00+02:00", want: time.Date(2021, 3, 25, 2, 30, 0, 0, time.UTC)},
+		{name: "local date and time", value: "2021-03-25 04:30", want: time.Date(2021, 3, 25, 4, 30, 0, 0, paris)},
+		{name: "local date", value: "2021-03-24", want: time.Date(2021, 3, 24, 0, 0, 0, 0, paris)},
+		{name: "time of day", value: "04:30", want: time.Date(2021, 3, 25, 4, 30, 0, 0, paris)},
+		{name: "invalid", value: "yesterday", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseTime(tt.value, now, paris)
+			if tt.wantErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			require.True(t, tt.want.Equal(got), "want %s, got %s", tt.want, got)
+		})
+	}
+}
+
+func TestParseLineTime(t *testing.T) {
+	tests := []struct {
+		name      string
+		text      string
+		want      time.Time
+		wantFound bool
+	}{
+		{"service log", "2021-03-25T04:00:00.006Z [jfrt ] [INFO ] message", time.Date(2021, 3, 25, 4, 0, 0, 6000000, time.UTC), true},
+		{"request log", "2021-03-25T04:30:34.199Z|94109ae150da76e|127.0.0.1", time.Date(2021, 3, 25, 4, 30, 34, 199000000, time.UTC), true},
+		{"without time zone", "2021-03-25T04:00:00.006 [INFO] message", time.Date(2021, 3, 25, 4, 0, 0, 6000000, time.FixedZone("UTC+1", 3600)), true},
+		{"date and time with comma", "2021-03-25 04:00:00,006 INFO message", time.Date(2021, 3, 25, 4, 0, 0, 6000000, time.FixedZone("UTC+1", 3600)), true},
+		{"stack trace", "\tat org.artifactory.Main", time.Time{}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, found := ParseLineTime(tt.text, time.FixedZone("UTC+1", 3600))
+			require.Equal(t, tt.wantFound, found)
+			require.True(t, tt.want.Equal(got), "want %s, got %s", tt.want, got)
+		})
+	}
+}
+
+func TestFilter(t *testing.T) {
+	source := stream.Source{NodeId: "node1", LogName: "one.log"}
+	filter := Range{
+		Since: time.Date(2021, 3, 25, 4, 0, 0, 0, time.UTC),
+		Until: time.Date(2021, 3, 25, 5, 0, 0, 0, time.UTC),
+	}.NewFilter()
+
+	var kept []string
+	for _, text := range []string{
+		"before any timestamp",
+		"2021-03-25T03:59:59.000Z [ERROR] too early",
+		"\tat too.early",
+		"2021-03-25T04:00:00.000Z [ERROR] in range",
+		"\tat in.range",
+		"2021-03-25T05:00:00.000Z [INFO] last",
+	} {
+		line := stream.Line{Source: source, Text: text}
+		if filter.Process(&line) {
+			kept = append(kept, text)
+		}
+		require.False(t, filter.Ended())
+	}
+	require.Equal(t, []string{"2021-03-25T04:00:00.000Z [ERROR] in range", "\tat in.range", "2021-03-25T05:00:00.000Z [INFO] last"}, kept)
+
+	line := stream.Line{Source: source, Text: "2021-03-25T05:00:00.001Z [INFO] too late"}
+	require.False(t, filter.Process(&line))
+	require.True(t, filter.Ended())
+}
+
+func TestFilter_SeveralSources(t *testing.T) {
+	node1 := stream.Source{NodeId: "node1"}
+	node2 := stream.Source{NodeId: "node2"}
+	filter := Range{Until: time.Date(2021, 3, 25, 5, 0, 0, 0, time.UTC)}.NewFilter()
+
+	line := stream.Line{Source: node1, Text: "no timestamp"}
+	require.True(t, filter.Process(&line))
+	line = stream.Line{Source: node1, Text: "2021-03-25T06:00:00.000Z too late"}
+	require.False(t, filter.Process(&line))
+	line = stream.Line{Source: node2, Text: "2021-03-25T04:00:00.000Z in range"}
+	require.True(t, filter.Process(&line))
+	require.False(t, filter.Ended())
+	line = stream.Line{Source: node2, Text: "2021-03-25T06:00:00.000Z too late"}
+	require.False(t, filter.Process(&line))
+	require.True(t, filter.Ended())
+}
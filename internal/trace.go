@@ -0,0 +1,158 @@
+package livelog
+
+import (
+	"context"
+	"fmt"
+	"github.com/jfrog/live-logs/internal/stream"
+	"github.com/jfrog/live-logs/internal/timerange"
+	"github.com/jfrog/live-logs/internal/util"
+	"io"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// A log line containing the searched trace id.
+type traceLine struct {
+	source  stream.Source
+	service string
+	time    time.Time
+	text    string
+}
+
+// Searches every log file of every node of the products for the lines containing the trace id, and displays them
+// grouped by service, each group in time order. The groups are ordered by their first line.
+// A product or log file which cannot be read is reported, and does not abort the search.
+func (s *Data) Trace(ctx context.Context, cliServerId, traceId string, productIds []string) error {
+	err := util.ValidateArgument("server id", cliServerId, getAllServiceIds())
+	if err != nil {
+		return err
+	}
+	if strings.TrimSpace(traceId) == "" {
+		return fmt.Errorf("trace id must be set")
+	}
+	s.SetServiceId(cliServerId)
+
+	var lines []traceLine
+	for _, productId := range productIds {
+		productLines, err := s.traceProduct(ctx, productId, traceId)
+		if err != nil {
+			if len(productIds) == 1 {
+				return err
+			}
+			fmt.Fprintf(os.Stderr, "Skipping %s: %s\n", productId, err)
+			continue
+		}
+		lines = append(lines, productLines...)
+	}
+	if len(lines) == 0 {
+		return fmt.Errorf("no log lines were found for the trace id %s", traceId)
+	}
+	return writeTrace(os.Stdout, lines)
+}
+
+func (s *Data) traceProduct(ctx context.Context, productId, traceId string) ([]traceLine, error) {
+	// The lines are searched before they are redacted, only the printed lines are redacted.
+	product := &Data{productId: productId, serviceId: s.GetServiceId()}
+	err := product.SetServiceLayer(productId)
+	if err != nil {
+		return nil, err
+	}
+	srvConfig, err := product.GetServiceLayer().GetConfig(ctx, product.GetServiceId())
+	if err != nil {
+		return nil, err
+	}
+
+	var sources []stream.Source
+	for _, nodeId := range srvConfig.Nodes {
+		for _, logName := range srvConfig.LogFileNames {
+			sources = append(sources, stream.Source{ServerId: product.GetServiceId(), ProductId: productId, NodeId: nodeId, LogName: logName})
+		}
+	}
+
+	jobs := make(chan stream.Source)
+	var linesLock sync.Mutex
+	var lines []traceLine
+	var wg sync.WaitGroup
+	for i := 0; i < DefaultBundleConcurrency; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for source := range jobs {
+				content, err := product.downloadFullLog(ctx, source.NodeId, source.LogName)
+				if err != nil {
+					fmt.Fprintf(os.Stderr, "Skipping %s %s %s: %s\n", productId, source.NodeId, source.LogName, err)
+					continue
+				}
+				matches := findTraceLines(string(content), traceId, source)
+				if s.redactor != nil {
+					for i := range matches {
+						matches[i].text = s.redactor.Redact(matches[i].text)
+					}
+				}
+				linesLock.Lock()
+				lines = append(lines, matches...)
+				linesLock.Unlock()
+			}
+		}()
+	}
+	for _, source := range sources {
+		jobs <- source
+	}
+	close(jobs)
+	wg.Wait()
+	return lines, nil
+}
+
+// Returns the lines containing the trace id. A line without a timestamp gets the timestamp of the line before it.
+func findTraceLines(content, traceId string, source stream.Source) []traceLine {
+	var matches []traceLine
+	var lastTime time.Time
+	for _, text := range strings.Split(content, "\n") {
+		if lineTime, found := timerange.ParseLineTime(text, time.UTC); found {
+			lastTime = lineTime
+		}
+		if strings.Contains(text, traceId) {
+			matches = append(matches, traceLine{source: source, service: serviceOf(source.LogName), time: lastTime, text: text})
+		}
+	}
+	return matches
+}
+
+// The service is the prefix of the log file name, for example "access" for "access-request.log".
+func serviceOf(logName string) string {
+	service := strings.TrimSuffix(logName, ".log")
+	if index := strings.Index(service, "-"); index > 0 {
+		return service[:index]
+	}
+	return service
+}
+
+func writeTrace(output io.Writer, lines []traceLine) error {
+	sort.SliceStable(lines, func(i, j int) bool {
+		return lines[i].time.Before(lines[j].time)
+	})
+	var services []string
+	groups := map[string][]traceLine{}
+	for _, line := range lines {
+		if _, found := groups[line.service]; !found {
+			services = append(services, line.service)
+		}
+		groups[line.service] = append(groups[line.service], line)
+	}
+	for _, service := range services {
+		_, err := fmt.Fprintf(output, "=== %s ===\n", service)
+		if err != nil {
+			return err
+		}
+		for _, line := range groups[service] {
+			_, err = fmt.Fprintf(output, "[%s %s %s] %s\n", line.source.ProductId, line.source.NodeId, line.source.LogName, line.text)
+			if err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,99 @@
+package livelog
+
+import (
+	"context"
+	"github.com/jfrog/live-logs/internal/constants"
+	"github.com/jfrog/live-logs/internal/model"
+	"github.com/jfrog/live-logs/internal/redact"
+	"github.com/jfrog/live-logs/internal/servicelayer"
+	"github.com/jfrog/live-logs/internal/stream"
+	"github.com/stretchr/testify/require"
+	"io/ioutil"
+	"os"
+	"regexp"
+	"testing"
+)
+
+var traceLogs = map[string]string{
+	"node1/artifactory-request.log": "2021-03-25T04:00:01.513Z|abc123|127.0.0.1|admin|GET|/api/system/ping|200|-1|0|3\n" +
+		"2021-03-25T04:00:02.000Z|def456|127.0.0.1|admin|GET|/api/repositories|200|-1|0|3\n",
+	"node1/artifactory-service.log": "2021-03-25T04:00:01.400Z [jfrt ] [INFO ] [abc123] [RequestHandler:12] - Handling ping\n",
+	"node2/access-request.log":      "2021-03-25T04:00:01.450Z|abc123|127.0.0.1|admin|POST|/api/v1/tokens|200|-1|0|5\n",
+}
+
+type traceMockServiceLayer struct {
+	multiLogMockServiceLayer
+}
+
+func (s *traceMockServiceLayer) GetLogData(_ context.Context, _ string) (model.Data, error) {
+	if s.lastPageMarker > 0 {
+		return model.Data{PageMarker: s.lastPageMarker}, nil
+	}
+	content := traceLogs[s.expectNodeId+"/"+s.expectLogFileName]
+	return model.Data{Content: content, PageMarker: int64(len(content))}, nil
+}
+
+func Test_LiveLogs_Trace(t *testing.T) {
+	realServiceLayer := newServiceLayer
+	realGetAllServiceIds := getAllServiceIds
+	defer func() {
+		newServiceLayer = realServiceLayer
+		getAllServiceIds = realGetAllServiceIds
+	}()
+	getAllServiceIds = func() []string {
+		return []string{"test-rt"}
+	}
+	newServiceLayer = func(productId string) (servicelayer.ServiceLayer, error) {
+		return &traceMockServiceLayer{multiLogMockServiceLayer{mockServiceLayer{
+			t:                 t,
+			getConfigResponse: &model.Config{Nodes: []string{"node1", "node2"}, LogFileNames: []string{"artifactory-request.log", "artifactory-service.log", "access-request.log"}},
+		}}}, nil
+	}
+
+	rescueStdout := os.Stdout
+	r, w, _ := os.Pipe()
+	os.Stdout = w
+
+	s := &Data{}
+	err := s.Trace(context.Background(), "test-rt", "abc123", []string{constants.ArtifactoryId})
+
+	w.Close()
+	out, _ := ioutil.ReadAll(r)
+	os.Stdout = rescueStdout
+
+	require.NoError(t, err)
+	require.Equal(t, "=== artifactory ===\n"+
+		"[rt node1 artifactory-service.log] 2021-03-25T04:00:01.400Z [jfrt ] [INFO ] [abc123] [RequestHandler:12] - Handling ping\n"+
+		"[rt node1 artifactory-request.log] 2021-03-25T04:00:01.513Z|abc123|127.0.0.1|admin|GET|/api/system/ping|200|-1|0|3\n"+
+		"=== access ===\n"+
+		"[rt node2 access-request.log] 2021-03-25T04:00:01.450Z|abc123|127.0.0.1|admin|POST|/api/v1/tokens|200|-1|0|5\n", string(out))
+
+	err = s.Trace(context.Background(), "test-rt", "unknown", []string{constants.ArtifactoryId})
+	require.Error(t, err)
+
+	// The trace id is searched in the raw lines, and only the printed lines are redacted.
+	r, w, _ = os.Pipe()
+	os.Stdout = w
+	s = &Data{pipelineOptions: pipelineOptions{redactor: redact.NewRedactor([]redact.Rule{{Name: "trace", Pattern: regexp.MustCompile(`abc\d+`)}}, false, "")}}
+	err = s.Trace(context.Background(), "test-rt", "abc123", []string{constants.ArtifactoryId})
+	w.Close()
+	out, _ = ioutil.ReadAll(r)
+	os.Stdout = rescueStdout
+
+	require.NoError(t, err)
+	require.Contains(t, string(out), "[rt node1 artifactory-service.log] 2021-03-25T04:00:01.400Z [jfrt ] [INFO ] [[REDACTED]] [RequestHandler:12] - Handling ping\n")
+	require.NotContains(t, string(out), "abc123")
+}
+
+func Test_findTraceLines(t *testing.T) {
+	source := stream.Source{NodeId: "node1", LogName: "artifactory-service.log"}
+	content := "2021-03-25T04:00:01.400Z [jfrt ] [ERROR] [abc123] - Failed\n" +
+		"\tat org.example.Handler(abc123)\n" +
+		"2021-03-25T04:00:03.000Z [jfrt ] [INFO ] [def456] - Other\n"
+	lines := findTraceLines(content, "abc123", source)
+	require.Len(t, lines, 2)
+	require.Equal(t, "artifactory", lines[0].service)
+	require.Equal(t, lines[0].time, lines[1].time)
+	require.False(t, lines[1].time.IsZero())
+}
+
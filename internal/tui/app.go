@@ -0,0 +1,420 @@
+package tui
+
+import (
+	"context"
+	"fmt"
+	"github.com/jfrog/live-logs/internal/model"
+	"io"
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+const (
+	highlightStart = "\x1b[7m"
+	highlightEnd   = "\x1b[27m"
+	boldStart      = "\x1b[1m"
+	styleReset     = "\x1b[0m"
+	tabReplacement = "    "
+	helpMessage    = "o open  s switch node  x close  p pause  / search  n/N next/previous  tab focus  r reload  q quit"
+)
+
+type mode int
+
+const (
+	modeNormal mode = iota
+	modeSearch
+	modeSelectNode
+	modeSelectLog
+)
+
+// Returns the available nodes and log files.
+type DiscoverFunc func(ctx context.Context) (*model.Config, error)
+
+// Streams a node and log file into the output until the context is done.
+type OpenStreamFunc func(ctx context.Context, nodeId, logName string, output io.Writer) error
+
+// The state of the terminal UI, updated by the key presses and rendered into screen rows.
+// It is only accessed by the UI loop, the panes handle the concurrent writes of their streams.
+type App struct {
+	ctx      context.Context
+	discover DiscoverFunc
+	open     OpenStreamFunc
+	title    string
+
+	config  *model.Config
+	panes   []*Pane
+	focused int
+	// The number of content rows of the focused pane in the last render, used for paging.
+	focusedRows int
+
+	mode   mode
+	input  string
+	search *regexp.Regexp
+	status string
+
+	menuItems []string
+	menuIndex int
+	// The node selected in the node menu, when the log menu follows it.
+	selectedNode string
+	// Set when the node menu replaces the node of the focused pane.
+	switchingNode bool
+
+	quit bool
+}
+
+func NewApp(ctx context.Context, title string, discover DiscoverFunc, open OpenStreamFunc) *App {
+	return &App{
+		ctx:         ctx,
+		title:       title,
+		discover:    discover,
+		open:        open,
+		focusedRows: 1,
+	}
+}
+
+// Reloads the available nodes and log files, for example after nodes were added or replaced.
+func (a *App) reloadConfig() {
+	config, err := a.discover(a.ctx)
+	if err != nil {
+		a.status = "Failed to load the nodes and log files: " + err.Error()
+		return
+	}
+	a.config = config
+	a.status = fmt.Sprintf("Found %d nodes and %d log files", len(config.Nodes), len(config.LogFileNames))
+}
+
+func (a *App) openPane(nodeId, logName string) {
+	pane := newPane(nodeId, logName)
+	a.startStream(pane)
+	a.panes = append(a.panes, pane)
+	a.focused = len(a.panes) - 1
+}
+
+func (a *App) startStream(pane *Pane) {
+	ctx, cancel := context.WithCancel(a.ctx)
+	pane.cancel = cancel
+	nodeId, logName := pane.NodeId, pane.LogName
+	go func() {
+		err := a.open(ctx, nodeId, logName, pane)
+		if err != nil && ctx.Err() == nil {
+			pane.setError(err)
+		}
+	}()
+}
+
+func (a *App) closePane(index int) {
+	a.panes[index].cancel()
+	a.panes = append(a.panes[:index], a.panes[index+1:]...)
+	if a.focused >= len(a.panes) && a.focused > 0 {
+		a.focused = len(a.panes) - 1
+	}
+}
+
+func (a *App) closeAllPanes() {
+	for len(a.panes) > 0 {
+		a.closePane(len(a.panes) - 1)
+	}
+}
+
+// Replaces the node of the focused pane, the log file name and the scroll back are kept.
+func (a *App) switchNode(nodeId string) {
+	pane := a.panes[a.focused]
+	pane.cancel()
+	pane.NodeId = nodeId
+	pane.setError(nil)
+	pane.Write([]byte(fmt.Sprintf("\n--- switched to node %s ---\n", nodeId)))
+	a.startStream(pane)
+}
+
+func (a *App) focusedPane() *Pane {
+	if len(a.panes) == 0 {
+		return nil
+	}
+	return a.panes[a.focused]
+}
+
+func (a *App) showMenu(menuMode mode, items []string) {
+	if len(items) == 0 {
+		a.status = "Nothing to select, press 'r' to reload the nodes and log files"
+		return
+	}
+	a.mode = menuMode
+	a.menuItems = items
+	a.menuIndex = 0
+}
+
+func (a *App) HandleKey(key Key) {
+	switch a.mode {
+	case modeSearch:
+		a.handleSearchKey(key)
+	case modeSelectNode, modeSelectLog:
+		a.handleMenuKey(key)
+	default:
+		a.handleNormalKey(key)
+	}
+}
+
+func (a *App) handleNormalKey(key Key) {
+	pane := a.focusedPane()
+	switch key.Code {
+	case KeyCtrlC:
+		a.quit = true
+	case KeyTab:
+		if len(a.panes) > 0 {
+			a.focused = (a.focused + 1) % len(a.panes)
+		}
+	case KeyUp, KeyDown, KeyPgUp, KeyPgDn, KeyHome, KeyEnd:
+		if pane != nil {
+			a.scroll(pane, key.Code)
+		}
+	case KeyRune:
+		a.handleCommand(key.Rune, pane)
+	}
+}
+
+func (a *App) handleCommand(command rune, pane *Pane) {
+	a.status = ""
+	switch command {
+	case 'q':
+		a.quit = true
+	case 'o':
+		if a.config != nil {
+			a.switchingNode = false
+			a.showMenu(modeSelectNode, a.config.Nodes)
+		}
+	case 'r':
+		a.reloadConfig()
+	case '/':
+		a.mode = modeSearch
+		a.input = ""
+	case 'k':
+		a.handleNormalKey(Key{Code: KeyUp})
+	case 'j':
+		a.handleNormalKey(Key{Code: KeyDown})
+	case 'g':
+		a.handleNormalKey(Key{Code: KeyHome})
+	case 'G':
+		a.handleNormalKey(Key{Code: KeyEnd})
+	}
+	if pane == nil {
+		return
+	}
+	switch command {
+	case 's':
+		if a.config != nil {
+			a.switchingNode = true
+			a.showMenu(modeSelectNode, a.config.Nodes)
+		}
+	case 'x':
+		a.closePane(a.focused)
+	case 'p', ' ':
+		pane.togglePause()
+	case 'n', 'N':
+		if a.search == nil {
+			a.status = "No search, press '/' to search"
+		} else if !pane.search(a.search, command == 'N') {
+			a.status = "No more matches"
+		}
+	}
+}
+
+func (a *App) scroll(pane *Pane, code KeyCode) {
+	rows := a.focusedRows
+	switch code {
+	case KeyUp:
+		pane.scrollBy(1, rows)
+	case KeyDown:
+		pane.scrollBy(-1, rows)
+	case KeyPgUp:
+		pane.scrollBy(rows, rows)
+	case KeyPgDn:
+		pane.scrollBy(-rows, rows)
+	case KeyHome:
+		pane.scrollToTop(rows)
+	case KeyEnd:
+		pane.scrollToBottom()
+	}
+}
+
+func (a *App) handleSearchKey(key Key) {
+	switch key.Code {
+	case KeyEnter:
+		a.mode = modeNormal
+		if a.input == "" {
+			a.search = nil
+			return
+		}
+		a.search = regexp.MustCompile("(?i)" + regexp.QuoteMeta(a.input))
+		if pane := a.focusedPane(); pane != nil && !pane.search(a.search, false) {
+			a.status = "No matches for " + a.input
+		}
+	case KeyEsc, KeyCtrlC:
+		a.mode = modeNormal
+		a.search = nil
+	case KeyBackspace:
+		if len(a.input) > 0 {
+			runes := []rune(a.input)
+			a.input = string(runes[:len(runes)-1])
+		}
+	case KeyRune:
+		a.input += string(key.Rune)
+	}
+}
+
+func (a *App) handleMenuKey(key Key) {
+	switch key.Code {
+	case KeyUp:
+		if a.menuIndex > 0 {
+			a.menuIndex--
+		}
+	case KeyDown:
+		if a.menuIndex < len(a.menuItems)-1 {
+			a.menuIndex++
+		}
+	case KeyEsc, KeyCtrlC:
+		a.mode = modeNormal
+	case KeyRune:
+		switch key.Rune {
+		case 'k':
+			a.handleMenuKey(Key{Code: KeyUp})
+		case 'j':
+			a.handleMenuKey(Key{Code: KeyDown})
+		case 'q':
+			a.mode = modeNormal
+		}
+	case KeyEnter:
+		selected := a.menuItems[a.menuIndex]
+		if a.mode == modeSelectLog {
+			a.mode = modeNormal
+			a.openPane(a.selectedNode, selected)
+			return
+		}
+		if a.switchingNode {
+			a.mode = modeNormal
+			a.switchNode(selected)
+			return
+		}
+		a.selectedNode = selected
+		a.showMenu(modeSelectLog, a.config.LogFileNames)
+	}
+}
+
+// Renders the screen into the given number of rows, each one fitting the given width.
+func (a *App) Render(width, height int) []string {
+	rows := make([]string, 0, height)
+	rows = append(rows, boldStart+fit(" "+a.title+" | "+helpMessage, width)+styleReset)
+	areaHeight := height - 2
+	switch {
+	case a.mode == modeSelectNode || a.mode == modeSelectLog:
+		rows = append(rows, a.renderMenu(width, areaHeight)...)
+	case len(a.panes) == 0:
+		rows = append(rows, fit(" No open panes, press 'o' to open a node and log file", width))
+	default:
+		rows = append(rows, a.renderPanes(width, areaHeight)...)
+	}
+	for len(rows) < height-1 {
+		rows = append(rows, "")
+	}
+	return append(rows, a.renderStatus(width))
+}
+
+func (a *App) renderMenu(width, height int) []string {
+	header := " Select a node"
+	if a.mode == modeSelectLog {
+		header = " Select a log file of node " + a.selectedNode
+	}
+	rows := []string{boldStart + fit(header+" (enter to select, esc to cancel)", width) + styleReset}
+	itemRows := height - 1
+	first := 0
+	if a.menuIndex >= itemRows {
+		first = a.menuIndex - itemRows + 1
+	}
+	for i := first; i < len(a.menuItems) && i < first+itemRows; i++ {
+		item := fit("   "+a.menuItems[i], width)
+		if i == a.menuIndex {
+			item = highlightStart + fit(" > "+a.menuItems[i], width) + highlightEnd
+		}
+		rows = append(rows, item)
+	}
+	return rows
+}
+
+// Splits the area between the panes, each pane has a title row followed by its content rows.
+func (a *App) renderPanes(width, height int) []string {
+	var rows []string
+	paneHeight := height / len(a.panes)
+	for i, pane := range a.panes {
+		currentHeight := paneHeight
+		if i == len(a.panes)-1 {
+			currentHeight = height - paneHeight*(len(a.panes)-1)
+		}
+		if currentHeight < 1 {
+			continue
+		}
+		rows = append(rows, a.renderPaneTitle(pane, i == a.focused, width))
+		contentRows := currentHeight - 1
+		if i == a.focused && contentRows > 0 {
+			a.focusedRows = contentRows
+		}
+		paneRows := pane.view(contentRows)
+		for _, line := range paneRows {
+			rows = append(rows, a.highlight(fit(line, width)))
+		}
+		for j := len(paneRows); j < contentRows; j++ {
+			rows = append(rows, "")
+		}
+	}
+	return rows
+}
+
+func (a *App) renderPaneTitle(pane *Pane, focused bool, width int) string {
+	title := " " + pane.NodeId + " | " + pane.LogName
+	paused, newLines, scroll, err := pane.status()
+	if paused {
+		title += " | paused, " + strconv.Itoa(newLines) + " new lines"
+	}
+	if scroll > 0 {
+		title += " | " + strconv.Itoa(scroll) + " lines below"
+	}
+	if err != nil {
+		title += " | error: " + err.Error()
+	}
+	if focused {
+		return highlightStart + fit(title, width) + highlightEnd
+	}
+	return boldStart + fit(title, width) + styleReset
+}
+
+func (a *App) renderStatus(width int) string {
+	if a.mode == modeSearch {
+		return fit("/"+a.input, width)
+	}
+	return fit(a.status, width)
+}
+
+func (a *App) highlight(text string) string {
+	if a.search == nil {
+		return text
+	}
+	return a.search.ReplaceAllStringFunc(text, func(match string) string {
+		return highlightStart + match + highlightEnd
+	})
+}
+
+// Expands the tabs, drops the other control characters and cuts the text to the given width.
+func fit(text string, width int) string {
+	text = strings.ReplaceAll(text, "\t", tabReplacement)
+	text = strings.Map(func(r rune) rune {
+		if unicode.IsControl(r) {
+			return -1
+		}
+		return r
+	}, text)
+	runes := []rune(text)
+	if len(runes) > width {
+		return string(runes[:width])
+	}
+	return text
+}
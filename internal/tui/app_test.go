@@ -0,0 +1,123 @@
+package tui
+
+import (
+	"context"
+	"fmt"
+	"github.com/jfrog/live-logs/internal/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"io"
+	"strings"
+	"testing"
+	"time"
+)
+
+func newTestApp(t *testing.T) (*App, chan string) {
+	opened := make(chan string, 10)
+	discover := func(ctx context.Context) (*model.Config, error) {
+		return &model.Config{Nodes: []string{"node1", "node2"}, LogFileNames: []string{"one.log", "two.log"}}, nil
+	}
+	open := func(ctx context.Context, nodeId, logName string, output io.Writer) error {
+		opened <- nodeId + "/" + logName
+		_, err := fmt.Fprintf(output, "first line of %s/%s\n", nodeId, logName)
+		<-ctx.Done()
+		return err
+	}
+	app := NewApp(context.Background(), "rt local", discover, open)
+	app.reloadConfig()
+	return app, opened
+}
+
+func typeKeys(app *App, text string) {
+	for _, key := range ParseKeys([]byte(text)) {
+		app.HandleKey(key)
+	}
+}
+
+func waitForStream(t *testing.T, opened chan string) string {
+	select {
+	case stream := <-opened:
+		return stream
+	case <-time.After(time.Second):
+		require.Fail(t, "the stream was not opened")
+		return ""
+	}
+}
+
+func TestAppOpenPanes(t *testing.T) {
+	app, opened := newTestApp(t)
+	defer app.closeAllPanes()
+
+	// Select the second node and the first log file.
+	typeKeys(app, "o\x1b[B\r\r")
+	assert.Equal(t, "node2/one.log", waitForStream(t, opened))
+	typeKeys(app, "o\r\x1b[B\r")
+	assert.Equal(t, "node1/two.log", waitForStream(t, opened))
+
+	require.Len(t, app.panes, 2)
+	assert.Equal(t, 1, app.focused)
+	typeKeys(app, "\t")
+	assert.Equal(t, 0, app.focused)
+
+	typeKeys(app, "x")
+	require.Len(t, app.panes, 1)
+	assert.Equal(t, "node1", app.panes[0].NodeId)
+}
+
+func TestAppSwitchNode(t *testing.T) {
+	app, opened := newTestApp(t)
+	defer app.closeAllPanes()
+
+	typeKeys(app, "o\r\r")
+	assert.Equal(t, "node1/one.log", waitForStream(t, opened))
+	typeKeys(app, "s\x1b[B\r")
+	assert.Equal(t, "node2/one.log", waitForStream(t, opened))
+	require.Len(t, app.panes, 1)
+	assert.Equal(t, "node2", app.panes[0].NodeId)
+}
+
+func TestAppRender(t *testing.T) {
+	app, opened := newTestApp(t)
+	defer app.closeAllPanes()
+
+	rows := app.Render(60, 10)
+	assert.Len(t, rows, 10)
+	assert.Contains(t, rows[1], "No open panes")
+
+	typeKeys(app, "o\r\r")
+	waitForStream(t, opened)
+	assert.Eventually(t, func() bool {
+		return strings.Contains(strings.Join(app.Render(60, 10), "\n"), "first line of node1/one.log")
+	}, time.Second, 10*time.Millisecond)
+
+	typeKeys(app, "/LINE\r")
+	rows = app.Render(60, 10)
+	assert.Contains(t, strings.Join(rows, "\n"), highlightStart+"line"+highlightEnd)
+	for _, row := range rows {
+		assert.LessOrEqual(t, len([]rune(stripStyles(row))), 60)
+	}
+}
+
+func TestAppRenderMenu(t *testing.T) {
+	app, _ := newTestApp(t)
+	typeKeys(app, "o\x1b[B")
+	rows := strings.Join(app.Render(60, 10), "\n")
+	assert.Contains(t, rows, "Select a node")
+	assert.Contains(t, rows, "> node2")
+
+	typeKeys(app, "\x1b")
+	assert.Equal(t, modeNormal, app.mode)
+}
+
+func TestFit(t *testing.T) {
+	assert.Equal(t, "a    b", fit("a\tb", 10))
+	assert.Equal(t, "abc", fit("abcdef", 3))
+	assert.Equal(t, "ab", fit("a\rb", 3))
+}
+
+func stripStyles(row string) string {
+	for _, style := range []string{highlightStart, highlightEnd, boldStart, styleReset} {
+		row = strings.ReplaceAll(row, style, "")
+	}
+	return row
+}
@@ -0,0 +1,92 @@
+package tui
+
+import (
+	"unicode/utf8"
+)
+
+type KeyCode int
+
+const (
+	KeyRune KeyCode = iota
+	KeyEnter
+	KeyEsc
+	KeyTab
+	KeyBackspace
+	KeyUp
+	KeyDown
+	KeyPgUp
+	KeyPgDn
+	KeyHome
+	KeyEnd
+	KeyCtrlC
+)
+
+type Key struct {
+	Code KeyCode
+	// The typed character, set for KeyRune only.
+	Rune rune
+}
+
+var escapeSequences = map[string]KeyCode{
+	"[A":  KeyUp,
+	"[B":  KeyDown,
+	"OA":  KeyUp,
+	"OB":  KeyDown,
+	"[5~": KeyPgUp,
+	"[6~": KeyPgDn,
+	"[H":  KeyHome,
+	"[F":  KeyEnd,
+	"OH":  KeyHome,
+	"OF":  KeyEnd,
+	"[1~": KeyHome,
+	"[4~": KeyEnd,
+}
+
+// Parses the bytes read from a terminal in raw mode into keys, unknown escape sequences are ignored.
+func ParseKeys(data []byte) []Key {
+	var keys []Key
+	for len(data) > 0 {
+		switch data[0] {
+		case '\x1b':
+			code, length := parseEscapeSequence(data[1:])
+			if length < 0 {
+				// An unknown sequence, skip the rest of the read.
+				return keys
+			}
+			keys = append(keys, Key{Code: code})
+			data = data[1+length:]
+			continue
+		case '\r', '\n':
+			keys = append(keys, Key{Code: KeyEnter})
+		case '\t':
+			keys = append(keys, Key{Code: KeyTab})
+		case '\x7f', '\b':
+			keys = append(keys, Key{Code: KeyBackspace})
+		case '\x03':
+			keys = append(keys, Key{Code: KeyCtrlC})
+		default:
+			r, size := utf8.DecodeRune(data)
+			if r != utf8.RuneError && r >= ' ' {
+				keys = append(keys, Key{Code: KeyRune, Rune: r})
+			}
+			data = data[size:]
+			continue
+		}
+		data = data[1:]
+	}
+	return keys
+}
+
+// Returns the key of the escape sequence following the escape character and the sequence length.
+// A lone escape character is the escape key, -1 is returned for an unknown sequence.
+func parseEscapeSequence(data []byte) (KeyCode, int) {
+	if len(data) == 0 || (data[0] != '[' && data[0] != 'O') {
+		return KeyEsc, 0
+	}
+	for sequence, code := range escapeSequences {
+		if len(data) >= len(sequence) && string(data[:len(sequence)]) == sequence {
+			return code, len(sequence)
+		}
+	}
+	return KeyEsc, -1
+}
@@ -0,0 +1,33 @@
+package tui
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestParseKeys(t *testing.T) {
+	tests := []struct {
+		name string
+		data string
+		want []Key
+	}{
+		{name: "runes", data: "ab", want: []Key{{Code: KeyRune, Rune: 'a'}, {Code: KeyRune, Rune: 'b'}}},
+		{name: "multi-byte rune", data: "é", want: []Key{{Code: KeyRune, Rune: 'é'}}},
+		{name: "enter", data: "\r", want: []Key{{Code: KeyEnter}}},
+		{name: "tab", data: "\t", want: []Key{{Code: KeyTab}}},
+		{name: "backspace", data: "\x7f", want: []Key{{Code: KeyBackspace}}},
+		{name: "ctrl c", data: "\x03", want: []Key{{Code: KeyCtrlC}}},
+		{name: "escape", data: "\x1b", want: []Key{{Code: KeyEsc}}},
+		{name: "arrows", data: "\x1b[A\x1b[B", want: []Key{{Code: KeyUp}, {Code: KeyDown}}},
+		{name: "application mode arrows", data: "\x1bOA", want: []Key{{Code: KeyUp}}},
+		{name: "pages", data: "\x1b[5~\x1b[6~", want: []Key{{Code: KeyPgUp}, {Code: KeyPgDn}}},
+		{name: "home and end", data: "\x1b[H\x1b[4~", want: []Key{{Code: KeyHome}, {Code: KeyEnd}}},
+		{name: "rune after sequence", data: "\x1b[Aq", want: []Key{{Code: KeyUp}, {Code: KeyRune, Rune: 'q'}}},
+		{name: "unknown sequence", data: "a\x1b[99zq", want: []Key{{Code: KeyRune, Rune: 'a'}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, ParseKeys([]byte(tt.data)))
+		})
+	}
+}
@@ -0,0 +1,167 @@
+package tui
+
+import (
+	"context"
+	"regexp"
+	"strings"
+	"sync"
+)
+
+// The number of lines kept for scroll back, older lines are discarded.
+const maxPaneLines = 10000
+
+// Displays a single node and log file stream, it is written to concurrently by the stream and read when rendering.
+type Pane struct {
+	NodeId  string
+	LogName string
+
+	lock sync.Mutex
+	// The received lines, the last one is not terminated yet when partial is set.
+	lines   []string
+	partial bool
+	// The number of lines between the bottom of the view and the last visible line, 0 follows the stream.
+	scroll int
+	paused bool
+	// The number of lines visible while paused.
+	pausedLines int
+	// The number of rows of the last view, to keep the scroll within the lines when the old lines are discarded.
+	rows   int
+	err    error
+	cancel context.CancelFunc
+}
+
+func newPane(nodeId, logName string) *Pane {
+	return &Pane{NodeId: nodeId, LogName: logName}
+}
+
+func (p *Pane) Write(data []byte) (int, error) {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+
+	newLines := strings.Split(string(data), "\n")
+	if p.partial && len(p.lines) > 0 {
+		p.lines[len(p.lines)-1] += newLines[0]
+		newLines = newLines[1:]
+	}
+	// The last element is empty when the data ends with a new line.
+	p.partial = newLines[len(newLines)-1] != ""
+	if !p.partial {
+		newLines = newLines[:len(newLines)-1]
+	}
+	p.lines = append(p.lines, newLines...)
+
+	if overflow := len(p.lines) - maxPaneLines; overflow > 0 {
+		p.lines = p.lines[overflow:]
+		p.pausedLines -= overflow
+		if p.pausedLines < 0 {
+			p.pausedLines = 0
+		}
+		// The discarded lines may be shown by a paused pane scrolled to the top.
+		p.clampScroll(p.rows)
+	}
+	return len(data), nil
+}
+
+func (p *Pane) setError(err error) {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+	p.err = err
+}
+
+func (p *Pane) togglePause() {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+	p.paused = !p.paused
+	p.pausedLines = len(p.lines)
+}
+
+// The lines currently displayed by the pane, a paused pane does not show the lines received since it was paused.
+func (p *Pane) visibleLines() []string {
+	if p.paused {
+		return p.lines[:p.pausedLines]
+	}
+	return p.lines
+}
+
+func (p *Pane) scrollBy(delta, rows int) {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+	p.scroll += delta
+	p.clampScroll(rows)
+}
+
+func (p *Pane) scrollToTop(rows int) {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+	p.scroll = len(p.visibleLines())
+	p.clampScroll(rows)
+}
+
+func (p *Pane) scrollToBottom() {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+	p.scroll = 0
+}
+
+func (p *Pane) clampScroll(rows int) {
+	maxScroll := len(p.visibleLines()) - rows
+	if p.scroll > maxScroll {
+		p.scroll = maxScroll
+	}
+	if p.scroll < 0 {
+		p.scroll = 0
+	}
+}
+
+// Scrolls to the closest matching line above the view, or below it when forward is set.
+// The matching line becomes the last line of the view, false is returned when no line matches.
+func (p *Pane) search(pattern *regexp.Regexp, forward bool) bool {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+	lines := p.visibleLines()
+	lastVisible := len(lines) - 1 - p.scroll
+	if lastVisible < 0 {
+		lastVisible = 0
+	}
+	if lastVisible >= len(lines) {
+		lastVisible = len(lines) - 1
+	}
+	step := -1
+	if forward {
+		step = 1
+	}
+	for i := lastVisible + step; i >= 0 && i < len(lines); i += step {
+		if pattern.MatchString(lines[i]) {
+			p.scroll = len(lines) - 1 - i
+			return true
+		}
+	}
+	return false
+}
+
+// Returns the lines to display in the given number of rows.
+func (p *Pane) view(rows int) []string {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+	p.rows = rows
+	p.clampScroll(rows)
+	lines := p.visibleLines()
+	end := len(lines) - p.scroll
+	if end < 0 {
+		end = 0
+	}
+	start := end - rows
+	if start < 0 {
+		start = 0
+	}
+	return lines[start:end]
+}
+
+func (p *Pane) status() (paused bool, newLines int, scroll int, err error) {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+	if p.paused {
+		newLines = len(p.lines) - p.pausedLines
+	}
+	return p.paused, newLines, p.scroll, p.err
+}
@@ -0,0 +1,87 @@
+package tui
+
+import (
+	"github.com/stretchr/testify/assert"
+	"regexp"
+	"strconv"
+	"testing"
+)
+
+func TestPaneWrite(t *testing.T) {
+	pane := newPane("node1", "one.log")
+	pane.Write([]byte("one\ntw"))
+	pane.Write([]byte("o\nthree\n"))
+	assert.Equal(t, []string{"one", "two", "three"}, pane.lines)
+	assert.False(t, pane.partial)
+}
+
+func TestPaneWriteDiscardsOldLines(t *testing.T) {
+	pane := newPane("node1", "one.log")
+	for i := 0; i < maxPaneLines+5; i++ {
+		pane.Write([]byte(strconv.Itoa(i) + "\n"))
+	}
+	assert.Len(t, pane.lines, maxPaneLines)
+	assert.Equal(t, "5", pane.lines[0])
+}
+
+func TestPaneView(t *testing.T) {
+	pane := newPane("node1", "one.log")
+	pane.Write([]byte("1\n2\n3\n4\n5\n"))
+	assert.Equal(t, []string{"4", "5"}, pane.view(2))
+
+	pane.scrollBy(2, 2)
+	assert.Equal(t, []string{"2", "3"}, pane.view(2))
+
+	pane.scrollBy(10, 2)
+	assert.Equal(t, []string{"1", "2"}, pane.view(2))
+
+	pane.scrollToBottom()
+	assert.Equal(t, []string{"4", "5"}, pane.view(2))
+}
+
+func TestPanePause(t *testing.T) {
+	pane := newPane("node1", "one.log")
+	pane.Write([]byte("1\n2\n"))
+	pane.togglePause()
+	pane.Write([]byte("3\n"))
+	assert.Equal(t, []string{"1", "2"}, pane.view(5))
+	paused, newLines, _, _ := pane.status()
+	assert.True(t, paused)
+	assert.Equal(t, 1, newLines)
+
+	pane.togglePause()
+	assert.Equal(t, []string{"1", "2", "3"}, pane.view(5))
+}
+
+func TestPanePauseDiscardsOldLines(t *testing.T) {
+	pane := newPane("node1", "one.log")
+	for i := 0; i < maxPaneLines; i++ {
+		pane.Write([]byte(strconv.Itoa(i) + "\n"))
+	}
+	pane.togglePause()
+	pane.scrollToTop(20)
+	assert.Equal(t, "0", pane.view(20)[0])
+	for i := maxPaneLines; i < maxPaneLines+100; i++ {
+		pane.Write([]byte(strconv.Itoa(i) + "\n"))
+	}
+	// The view stays at the top of the remaining lines.
+	view := pane.view(20)
+	assert.Len(t, view, 20)
+	assert.Equal(t, "100", view[0])
+	assert.True(t, pane.search(regexp.MustCompile("^150$"), true))
+	assert.Equal(t, "150", pane.view(20)[19])
+}
+
+func TestPaneSearch(t *testing.T) {
+	pane := newPane("node1", "one.log")
+	pane.Write([]byte("error 1\ninfo\nerror 2\ninfo\ninfo\n"))
+	pattern := regexp.MustCompile("error")
+
+	assert.True(t, pane.search(pattern, false))
+	assert.Equal(t, []string{"error 2"}, pane.view(1))
+	assert.True(t, pane.search(pattern, false))
+	assert.Equal(t, []string{"error 1"}, pane.view(1))
+	assert.False(t, pane.search(pattern, false))
+	assert.True(t, pane.search(pattern, true))
+	assert.Equal(t, []string{"error 2"}, pane.view(1))
+}
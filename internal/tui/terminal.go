@@ -0,0 +1,98 @@
+package tui
+
+import (
+	"bufio"
+	"fmt"
+	"golang.org/x/term"
+	"os"
+	"strconv"
+	"time"
+)
+
+const (
+	renderInterval    = 200 * time.Millisecond
+	enterAltScreen    = "\x1b[?1049h"
+	exitAltScreen     = "\x1b[?1049l"
+	hideCursor        = "\x1b[?25l"
+	showCursor        = "\x1b[?25h"
+	clearToLineEnd    = "\x1b[K"
+	defaultTermWidth  = 80
+	defaultTermHeight = 24
+)
+
+// Runs the UI on the terminal until the user quits or the context is done.
+// The terminal is switched to raw mode and to the alternate screen, both are restored on return.
+func (a *App) Run(input, output *os.File) error {
+	inputFd := int(input.Fd())
+	if !term.IsTerminal(inputFd) || !term.IsTerminal(int(output.Fd())) {
+		return fmt.Errorf("the terminal UI requires an interactive terminal")
+	}
+	oldState, err := term.MakeRaw(inputFd)
+	if err != nil {
+		return err
+	}
+	defer term.Restore(inputFd, oldState)
+
+	writer := bufio.NewWriter(output)
+	writer.WriteString(enterAltScreen + hideCursor)
+	defer func() {
+		writer.WriteString(showCursor + exitAltScreen)
+		writer.Flush()
+	}()
+
+	keyPresses := make(chan []byte)
+	go readInput(input, keyPresses)
+
+	a.reloadConfig()
+	if a.config != nil {
+		a.showMenu(modeSelectNode, a.config.Nodes)
+	}
+	defer a.closeAllPanes()
+
+	ticker := time.NewTicker(renderInterval)
+	defer ticker.Stop()
+	for !a.quit {
+		err = a.draw(writer, int(output.Fd()))
+		if err != nil {
+			return err
+		}
+		select {
+		case <-a.ctx.Done():
+			return nil
+		case data, ok := <-keyPresses:
+			if !ok {
+				return nil
+			}
+			for _, key := range ParseKeys(data) {
+				a.HandleKey(key)
+			}
+		case <-ticker.C:
+		}
+	}
+	return nil
+}
+
+func readInput(input *os.File, keyPresses chan<- []byte) {
+	defer close(keyPresses)
+	buf := make([]byte, 256)
+	for {
+		n, err := input.Read(buf)
+		if err != nil {
+			return
+		}
+		data := make([]byte, n)
+		copy(data, buf[:n])
+		keyPresses <- data
+	}
+}
+
+func (a *App) draw(writer *bufio.Writer, outputFd int) error {
+	width, height, err := term.GetSize(outputFd)
+	if err != nil || width <= 0 || height <= 0 {
+		width, height = defaultTermWidth, defaultTermHeight
+	}
+	for i, row := range a.Render(width, height) {
+		writer.WriteString("\x1b[" + strconv.Itoa(i+1) + ";1H" + row + styleReset + clearToLineEnd)
+	}
+	return writer.Flush()
+}
@@ -6,8 +6,18 @@ import (
 	"fmt"
 	"github.com/jfrog/live-logs/internal/constants"
 	"github.com/manifoldco/promptui"
+	"path"
 	"strings"
 	"time"
+	"unicode"
+)
+
+const (
+	multiSelectDone       = "Done"
+	multiSelectAll        = "Select all"
+	multiSelectNone       = "Clear all"
+	multiSelectMenuSize   = 10
+	multiSelectFixedItems = 2
 )
 
 func InSlice(values []string, wantedVal string) bool {
@@ -72,3 +82,141 @@ func RunInteractiveMenu(selectionHeader string, selectionLabel string, values []
 	return res, err
 }
 
+// Returns true when all the characters of the pattern appear in the value in the same order, ignoring case.
+func FuzzyMatch(pattern, value string) bool {
+	valueRunes := []rune(strings.ToLower(value))
+	valueIndex := 0
+	for _, patternRune := range strings.ToLower(pattern) {
+		if unicode.IsSpace(patternRune) {
+			continue
+		}
+		for valueIndex < len(valueRunes) && valueRunes[valueIndex] != patternRune {
+			valueIndex++
+		}
+		if valueIndex == len(valueRunes) {
+			return false
+		}
+		valueIndex++
+	}
+	return true
+}
+
+// Parses a comma separated list of values, where "all" stands for all the values, and validates each of them.
+// A value with wildcards, such as "prod-*", stands for all the values it matches.
+func ParseSelection(argumentName string, selection string, allValues []string) ([]string, error) {
+	if selection == constants.AllValues {
+		if len(allValues) == 0 {
+			return nil, fmt.Errorf("no %v found", argumentName)
+		}
+		return allValues, nil
+	}
+	var values []string
+	for _, value := range strings.Split(selection, constants.ValuesSeparator) {
+		value = strings.TrimSpace(value)
+		matches, err := matchSelection(argumentName, value, allValues)
+		if err != nil {
+			return nil, err
+		}
+		for _, match := range matches {
+			if !InSlice(values, match) {
+				values = append(values, match)
+			}
+		}
+	}
+	return values, nil
+}
+
+func matchSelection(argumentName string, value string, allValues []string) ([]string, error) {
+	if !strings.ContainsAny(value, "*?[") {
+		return []string{value}, ValidateArgument(argumentName, value, allValues)
+	}
+	var matches []string
+	for _, candidate := range allValues {
+		isMatch, err := path.Match(value, candidate)
+		if err != nil {
+			return nil, fmt.Errorf("invalid %v pattern [%v]: %w", argumentName, value, err)
+		}
+		if isMatch {
+			matches = append(matches, candidate)
+		}
+	}
+	if len(matches) == 0 {
+		return nil, fmt.Errorf("no %v matches [%v], consider using one of the following %v values [%v]", argumentName, value, argumentName, SliceToCsv(allValues))
+	}
+	return matches, nil
+}
+
+// Formats selected values back into the form parsed by ParseSelection.
+func FormatSelection(selectedValues []string, allValues []string) string {
+	if len(selectedValues) > 1 && len(selectedValues) == len(allValues) {
+		return constants.AllValues
+	}
+	return strings.Join(selectedValues, constants.ValuesSeparator)
+}
+
+// Displays a menu where several values are toggled, until "Done" is chosen with at least one selected value.
+// Typing "/" searches the values with a fuzzy match.
+func RunInteractiveMultiSelectMenu(selectionHeader string, selectionLabel string, values []string) ([]string, error) {
+	if selectionHeader != "" {
+		fmt.Println(selectionHeader)
+	}
+	selected := make([]bool, len(values))
+	cursorPos := multiSelectFixedItems
+	for {
+		items := []string{multiSelectDone, multiSelectAll}
+		if countSelected(selected) == len(values) {
+			items[1] = multiSelectNone
+		}
+		for i, value := range values {
+			mark := "[ ] "
+			if selected[i] {
+				mark = "[x] "
+			}
+			items = append(items, mark+value)
+		}
+		selectMenu := promptui.Select{
+			Label:        selectionLabel + " (enter to toggle, / to search)",
+			Items:        items,
+			Size:         multiSelectMenuSize,
+			HideSelected: true,
+			Searcher: func(input string, index int) bool {
+				return FuzzyMatch(input, items[index])
+			},
+		}
+		index, _, err := selectMenu.RunCursorAt(cursorPos, cursorPos-cursorPos%multiSelectMenuSize)
+		if err != nil {
+			return nil, err
+		}
+		cursorPos = index
+		switch {
+		case index == 0:
+			if countSelected(selected) > 0 {
+				var result []string
+				for i, value := range values {
+					if selected[i] {
+						result = append(result, value)
+					}
+				}
+				fmt.Println(promptui.IconGood + " " + strings.Join(result, ", "))
+				return result, nil
+			}
+		case index == 1:
+			selectAll := countSelected(selected) != len(values)
+			for i := range selected {
+				selected[i] = selectAll
+			}
+		default:
+			selected[index-multiSelectFixedItems] = !selected[index-multiSelectFixedItems]
+		}
+	}
+}
+
+func countSelected(selected []bool) int {
+	count := 0
+	for _, isSelected := range selected {
+		if isSelected {
+			count++
+		}
+	}
+	return count
+}
@@ -111,3 +111,119 @@ func TestInSlice(t *testing.T) {
 		})
 	}
 }
+
+func TestFuzzyMatch(t *testing.T) {
+	tests := []struct {
+		name        string
+		pattern     string
+		value       string
+		wantedMatch bool
+	}{
+		{
+			name:        "empty pattern",
+			pattern:     "",
+			value:       "artifactory-service.log",
+			wantedMatch: true,
+		},
+		{
+			name:        "sub string",
+			pattern:     "service",
+			value:       "artifactory-service.log",
+			wantedMatch: true,
+		},
+		{
+			name:        "sub sequence ignoring case",
+			pattern:     "ARTreq",
+			value:       "artifactory-request.log",
+			wantedMatch: true,
+		},
+		{
+			name:        "ignoring spaces",
+			pattern:     "art req",
+			value:       "artifactory-request.log",
+			wantedMatch: true,
+		},
+		{
+			name:        "wrong order",
+			pattern:     "reqart",
+			value:       "artifactory-request.log",
+			wantedMatch: false,
+		},
+		{
+			name:        "missing character",
+			pattern:     "xray",
+			value:       "artifactory-request.log",
+			wantedMatch: false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.wantedMatch, FuzzyMatch(tt.pattern, tt.value))
+		})
+	}
+}
+
+func TestParseSelection(t *testing.T) {
+	tests := []struct {
+		name         string
+		selection    string
+		allValues    []string
+		wantedValues []string
+		wantErr      bool
+	}{
+		{
+			name:         "single value",
+			selection:    "a",
+			allValues:    []string{"a", "b"},
+			wantedValues: []string{"a"},
+		},
+		{
+			name:         "multiple values",
+			selection:    "b, a,b",
+			allValues:    []string{"a", "b", "c"},
+			wantedValues: []string{"b", "a"},
+		},
+		{
+			name:         "all values",
+			selection:    "all",
+			allValues:    []string{"a", "b"},
+			wantedValues: []string{"a", "b"},
+		},
+		{
+			name:      "unknown value",
+			selection: "a,d",
+			allValues: []string{"a", "b"},
+			wantErr:   true,
+		},
+		{
+			name:         "wildcard",
+			selection:    "prod-*,dev-eu",
+			allValues:    []string{"prod-us", "dev-eu", "prod-eu", "dev-us"},
+			wantedValues: []string{"prod-us", "prod-eu", "dev-eu"},
+		},
+		{
+			name:      "wildcard without matches",
+			selection: "qa-*",
+			allValues: []string{"prod-us", "dev-eu"},
+			wantErr:   true,
+		},
+		{
+			name:      "all without values",
+			selection: "all",
+			wantErr:   true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			values, err := ParseSelection("node id", tt.selection, tt.allValues)
+			assert.Equal(t, tt.wantErr, err != nil)
+			assert.Equal(t, tt.wantedValues, values)
+		})
+	}
+}
+
+func TestFormatSelection(t *testing.T) {
+	assert.Equal(t, "a", FormatSelection([]string{"a"}, []string{"a"}))
+	assert.Equal(t, "a,c", FormatSelection([]string{"a", "c"}, []string{"a", "b", "c"}))
+	assert.Equal(t, "all", FormatSelection([]string{"a", "b"}, []string{"a", "b"}))
+}
@@ -0,0 +1,144 @@
+package livelogs
+
+import (
+	"context"
+	"github.com/jfrog/live-logs/internal/clientlayer"
+	"github.com/jfrog/live-logs/internal/testserver"
+	"github.com/stretchr/testify/require"
+	"io/ioutil"
+	"strings"
+	"testing"
+	"time"
+)
+
+func newTestClient(t *testing.T) (*Client, *testserver.Server) {
+	server := testserver.New(t, "some-token")
+	server.AddProduct(ArtifactoryId, testserver.Product{
+		Version:      "7.41.0",
+		Nodes:        []string{"node-1"},
+		LogFileNames: []string{"one.log"},
+		PageSize:     8,
+	})
+	realRequestsPerSecond := clientlayer.GetRequestsPerSecond()
+	t.Cleanup(func() { clientlayer.SetRequestsPerSecond(realRequestsPerSecond) })
+	clientlayer.SetRequestsPerSecond(0)
+
+	logClient, err := NewClientWithToken(ArtifactoryId, server.URL, "some-token")
+	require.NoError(t, err)
+	logClient.SetRefreshRate(5 * time.Millisecond)
+	return logClient, server
+}
+
+func TestClient_Snapshot_FakeServer(t *testing.T) {
+	logClient, server := newTestClient(t)
+	server.AppendLog(ArtifactoryId, "node-1", "one.log", "first line\nsecond line\n")
+
+	logReader, err := logClient.Snapshot(context.Background(), "node-1", "one.log")
+	require.NoError(t, err)
+	content, err := ioutil.ReadAll(logReader)
+	require.NoError(t, err)
+	require.Equal(t, "first line\nsecond line\n", string(content))
+}
+
+func TestClient_Stream_FakeServer(t *testing.T) {
+	logClient, server := newTestClient(t)
+	server.AppendLog(ArtifactoryId, "node-1", "one.log", "first line\n")
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	logStream := logClient.Stream(ctx, "node-1", "one.log")
+	readLine := func() string {
+		select {
+		case line := <-logStream.Lines():
+			return line.Text
+		case <-ctx.Done():
+			t.Fatal("no line was received")
+			return ""
+		}
+	}
+
+	require.Equal(t, "first line", readLine())
+	server.AppendLog(ArtifactoryId, "node-1", "one.log", "second line\n")
+	require.Equal(t, "second line", readLine())
+	server.RotateLog(ArtifactoryId, "node-1", "one.log", "rotated\n")
+	require.Equal(t, "rotated", readLine())
+
+	cancel()
+	for range logStream.Lines() {
+	}
+	require.NoError(t, logStream.Err())
+}
+
+func TestClient_Stream_FakeServer_Failures(t *testing.T) {
+	tests := []struct {
+		name    string
+		script  func(server *testserver.Server)
+		wantErr string
+	}{
+		{
+			name:    "too many requests",
+			script:  func(server *testserver.Server) { server.FailRequests(ArtifactoryId, 429, 1) },
+			wantErr: "status code: 429",
+		},
+		{
+			name:    "bad credentials",
+			script:  func(server *testserver.Server) { server.SetAccessToken("other-token") },
+			wantErr: "status code: 401",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			logClient, server := newTestClient(t)
+			tt.script(server)
+
+			ctx, cancel := context.WithTimeout(context.Background(), 200*time.Millisecond)
+			defer cancel()
+			logStream := logClient.Stream(ctx, "node-1", "one.log")
+			for range logStream.Lines() {
+			}
+			require.Error(t, logStream.Err())
+			require.Contains(t, logStream.Err().Error(), tt.wantErr)
+		})
+	}
+}
+
+func TestClient_Stream_FakeServer_Slow(t *testing.T) {
+	logClient, server := newTestClient(t)
+	server.AppendLog(ArtifactoryId, "node-1", "one.log", "first line\n")
+	server.SetDelay(time.Minute)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	start := time.Now()
+	logStream := logClient.Stream(ctx, "node-1", "one.log")
+	for range logStream.Lines() {
+		t.Fatal("no line was expected")
+	}
+	require.NoError(t, logStream.Err())
+	require.Less(t, int64(time.Since(start)), int64(10*time.Second))
+}
+
+func TestClient_Stream_FakeServer_StopReading(t *testing.T) {
+	server := testserver.New(t, "some-token")
+	server.AddProduct(ArtifactoryId, testserver.Product{Version: "7.41.0", Nodes: []string{"node-1"}, LogFileNames: []string{"one.log"}})
+	realRequestsPerSecond := clientlayer.GetRequestsPerSecond()
+	defer clientlayer.SetRequestsPerSecond(realRequestsPerSecond)
+	clientlayer.SetRequestsPerSecond(0)
+	server.AppendLog(ArtifactoryId, "node-1", "one.log", strings.Repeat("line\n", 1000))
+	logClient, err := NewClientWithToken(ArtifactoryId, server.URL, "some-token")
+	require.NoError(t, err)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	logStream := logClient.Stream(ctx, "node-1", "one.log")
+	require.Eventually(t, func() bool { return len(logStream.Lines()) == cap(logStream.Lines()) }, 10*time.Second, time.Millisecond)
+
+	// The stream ends once the context is done, without waiting for the rest of the lines to be read.
+	cancel()
+	time.Sleep(50 * time.Millisecond)
+	count := 0
+	for range logStream.Lines() {
+		count++
+	}
+	require.Less(t, count, 1000)
+	require.NoError(t, logStream.Err())
+}
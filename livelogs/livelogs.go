@@ -0,0 +1,146 @@
+// Package livelogs reads the live logs of the JFrog products, with the log client the live-logs plugin commands are
+// built on.
+//
+// A client is created for a product on a server, either from a JFrog CLI server id or from a platform url and
+// access token. It lists the nodes and log files, reads the current content of a log file, and follows a log file
+// as a stream of lines until its context is done:
+//
+//	client, err := livelogs.NewClient(livelogs.ArtifactoryId, "my-server")
+//	...
+//	logStream := client.Stream(ctx, "node-1", "artifactory-service.log")
+//	for line := range logStream.Lines() {
+//		fmt.Println(line.Text)
+//	}
+//	err = logStream.Err()
+package livelogs
+
+import (
+	"context"
+	"fmt"
+	cliCommands "github.com/jfrog/jfrog-cli-core/v2/common/commands"
+	"github.com/jfrog/live-logs/internal/client"
+	"github.com/jfrog/live-logs/internal/clientlayer"
+	"github.com/jfrog/live-logs/internal/constants"
+	"github.com/jfrog/live-logs/internal/model"
+	"github.com/jfrog/live-logs/internal/servicelayer"
+	"github.com/jfrog/live-logs/internal/stream"
+	"github.com/jfrog/live-logs/internal/util"
+	"io"
+	"time"
+)
+
+const (
+	ArtifactoryId  = constants.ArtifactoryId
+	McId           = constants.McId
+	XrayId         = constants.XrayId
+	PipelinesId    = constants.PipelinesId
+	DistributionId = constants.DistributionId
+
+	// The maximum number of requests per second sent to each server by default, see SetRequestsPerSecond.
+	DefaultRequestsPerSecond = clientlayer.DefaultRequestsPerSecond
+)
+
+type (
+	// The lines of a followed log file, see Client.Stream.
+	LogStream = client.LogStream
+	// The nodes and log files of a product, and the refresh rate advised by the server in milliseconds.
+	Config = model.Config
+	// A line of a log file, along with its origin.
+	Line = stream.Line
+	// The server, product, node and log file a line comes from.
+	Source = stream.Source
+)
+
+// Method initialised as a variable to improved unit test coverage
+var getAllServerIds = cliCommands.GetAllServerIds
+
+// Returns the ids of the supported products.
+func ProductIds() []string {
+	return util.FetchAllProductIds()
+}
+
+// Sets the maximum number of requests per second sent to each server, shared by all the clients of the server, zero
+// disables the limit. The requests are limited to DefaultRequestsPerSecond unless it is set.
+func SetRequestsPerSecond(rate float64) {
+	clientlayer.SetRequestsPerSecond(rate)
+}
+
+// Creates a client for a product, on a server id of the JFrog CLI config.
+func NewClient(productId, serverId string) (*Client, error) {
+	err := util.ValidateArgument("product id", productId, ProductIds())
+	if err != nil {
+		return nil, err
+	}
+	err = util.ValidateArgument("server id", serverId, getAllServerIds())
+	if err != nil {
+		return nil, err
+	}
+	return newClient(productId, serverId), nil
+}
+
+// Creates a client for a product, on a JFrog platform url such as https://acme.jfrog.io, authenticated with an
+// access token. The product urls are derived from the platform url.
+func NewClientWithToken(productId, platformUrl, accessToken string) (*Client, error) {
+	err := util.ValidateArgument("product id", productId, ProductIds())
+	if err != nil {
+		return nil, err
+	}
+	if platformUrl == "" {
+		return nil, fmt.Errorf("platform url must be set")
+	}
+	if accessToken == "" {
+		return nil, fmt.Errorf("access token must be set")
+	}
+	serverId := clientlayer.RegisterPlatform(platformUrl, accessToken)
+	return newClient(productId, serverId), nil
+}
+
+// Reads the live logs of a product on a server, see Config, Snapshot and Stream. The client keeps no state between
+// the calls, several logs can be read and streamed concurrently.
+type Client struct {
+	client *client.Client
+}
+
+func newClient(productId, serverId string) *Client {
+	return &Client{client: client.New(productId, serverId, servicelayer.NewService)}
+}
+
+func (c *Client) ProductId() string {
+	return c.client.ProductId()
+}
+
+func (c *Client) ServerId() string {
+	return c.client.ServerId()
+}
+
+// Sets the interval between the polls of a stream, usually the refresh rate returned with the config. The interval
+// grows while the log is quiet, and is back to the refresh rate as soon as new log data arrives.
+func (c *Client) SetRefreshRate(refreshRate time.Duration) {
+	c.client.SetRefreshRate(refreshRate)
+}
+
+// Sets a fixed interval between the polls of a stream, the interval no longer adapts to the log activity.
+func (c *Client) SetFixedRefreshRate(refreshRate time.Duration) {
+	c.client.SetFixedRefreshRate(refreshRate)
+}
+
+// Returns the nodes and log files of the product, along with the refresh rate advised by the server.
+func (c *Client) Config(ctx context.Context) (*Config, error) {
+	return c.client.Config(ctx)
+}
+
+// Returns the version of the product.
+func (c *Client) Version(ctx context.Context) (string, error) {
+	return c.client.Version(ctx)
+}
+
+// Returns the whole content of a log file at the time of the call.
+func (c *Client) Snapshot(ctx context.Context, nodeId, logName string) (io.Reader, error) {
+	return c.client.Snapshot(ctx, nodeId, logName)
+}
+
+// Follows a log file until the context is done. The polls of the stream adapt to the log activity, unless a fixed
+// refresh rate is set. The lines no longer need to be read once the context is done.
+func (c *Client) Stream(ctx context.Context, nodeId, logName string) *LogStream {
+	return c.client.Stream(ctx, nodeId, logName)
+}
@@ -0,0 +1,57 @@
+package livelogs
+
+import (
+	"github.com/jfrog/live-logs/internal/clientlayer"
+	"github.com/stretchr/testify/require"
+	"testing"
+)
+
+func TestNewClient(t *testing.T) {
+	realGetAllServerIds := getAllServerIds
+	defer func() { getAllServerIds = realGetAllServerIds }()
+	getAllServerIds = func() []string {
+		return []string{"test-rt"}
+	}
+
+	tests := []struct {
+		name      string
+		productId string
+		serverId  string
+		wantErr   string
+	}{
+		{name: "valid", productId: ArtifactoryId, serverId: "test-rt"},
+		{name: "invalid product id", productId: "some-product", serverId: "test-rt", wantErr: "product id"},
+		{name: "invalid server id", productId: ArtifactoryId, serverId: "some-server", wantErr: "server id"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			logClient, err := NewClient(tt.productId, tt.serverId)
+			if tt.wantErr != "" {
+				require.Error(t, err)
+				require.Contains(t, err.Error(), tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tt.productId, logClient.ProductId())
+			require.Equal(t, tt.serverId, logClient.ServerId())
+		})
+	}
+}
+
+func TestNewClientWithToken(t *testing.T) {
+	logClient, err := NewClientWithToken(XrayId, "https://acme.jfrog.io", "some-token")
+	require.NoError(t, err)
+	require.Equal(t, XrayId, logClient.ProductId())
+	require.Equal(t, "https://acme.jfrog.io/", logClient.ServerId())
+
+	_, err = NewClientWithToken(XrayId, "", "some-token")
+	require.Error(t, err)
+	_, err = NewClientWithToken(XrayId, "https://acme.jfrog.io", "")
+	require.EqualError(t, err, "access token must be set")
+}
+
+func TestSetRequestsPerSecond(t *testing.T) {
+	defer SetRequestsPerSecond(DefaultRequestsPerSecond)
+	SetRequestsPerSecond(2)
+	require.Equal(t, float64(2), clientlayer.GetRequestsPerSecond())
+}
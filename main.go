@@ -23,5 +23,13 @@ func getCommands() []components.Command {
 	return []components.Command{
 		commands.GetLogsCommand(),
 		commands.GetConfigCommand(),
+		commands.GetBundleCommand(),
+		commands.GetTuiCommand(),
+		commands.GetProductsCommand(),
+		commands.GetDoctorCommand(),
+		commands.GetTraceCommand(),
+		commands.GetReplayCommand(),
+		commands.GetShipCommand(),
+		commands.GetServeCommand(),
 	}
 }